@@ -0,0 +1,55 @@
+//go:build mockoidc
+
+// The mock-oidc command is only built with -tags mockoidc, so release binaries never contain
+// an issuer that signs anyone in.
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"portal-blog/internal/adapter/oidc/oidctest"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var mockOidcOpts oidctest.IssuerOptions
+var mockOidcPort int
+
+var mockOidcCmd = &cobra.Command{
+	Use:   "mock-oidc",
+	Short: "run a local OpenID Connect issuer for development",
+	Long:  "mock-oidc runs an OpenID Connect issuer that signs every authorization request in as the configured identity. Point OIDC_ISSUER_URL at it to try single sign-on without a real identity provider.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if mockOidcOpts.IssuerURL == "" {
+			mockOidcOpts.IssuerURL = fmt.Sprintf("http://localhost:%d", mockOidcPort)
+		}
+
+		issuer, err := oidctest.NewIssuer(mockOidcOpts)
+		if err != nil {
+			log.Fatal().Msgf("Error creating mock issuer: %v", err)
+			return
+		}
+
+		log.Print("mock OpenID Connect issuer listening on " + mockOidcOpts.IssuerURL)
+		err = http.ListenAndServe(fmt.Sprintf(":%d", mockOidcPort), issuer)
+		if err != nil {
+			log.Fatal().Msgf("Error starting mock issuer: %v", err)
+		}
+	},
+}
+
+// init registers the mock-oidc command and its flags describing the signed in identity.
+func init() {
+	mockOidcCmd.Flags().IntVar(&mockOidcPort, "port", 9000, "port to listen on")
+	mockOidcCmd.Flags().StringVar(&mockOidcOpts.IssuerURL, "issuer", "", "issuer URL (default http://localhost:<port>)")
+	mockOidcCmd.Flags().StringVar(&mockOidcOpts.ClientID, "client-id", "portal-blog", "client ID that may request codes")
+	mockOidcCmd.Flags().StringVar(&mockOidcOpts.Subject, "subject", "mock-user-1", "subject of the signed in identity")
+	mockOidcCmd.Flags().StringVar(&mockOidcOpts.Email, "email", "editor@example.com", "email of the signed in identity")
+	mockOidcCmd.Flags().StringVar(&mockOidcOpts.Name, "name", "Mock Editor", "name of the signed in identity")
+	mockOidcCmd.Flags().StringVar(&mockOidcOpts.RoleClaim, "role-claim", "groups", "claim the roles are sent in")
+	mockOidcCmd.Flags().StringSliceVar(&mockOidcOpts.Roles, "roles", []string{"editors"}, "values of the role claim")
+
+	rootCmd.AddCommand(mockOidcCmd)
+}
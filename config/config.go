@@ -1,13 +1,95 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type App struct {
-	AppPort string `json:"app_port"`
-	AppEnv  string `json:"app_env"`
+	AppPort     string `json:"app_port"`
+	AppEnv      string `json:"app_env"`
+	FrontendURL string `json:"frontend_url"`
+
+	// ProxyHeader names a header set by a reverse proxy, such as "X-Forwarded-For", that holds
+	// the client IP. It is only trusted on requests from TrustedProxies, comma separated IPs or
+	// CIDR ranges; otherwise the client IP is the connection's address.
+	ProxyHeader    string `json:"proxy_header"`
+	TrustedProxies string `json:"trusted_proxies"`
+
+	// JwtAlgorithm is "HS256", signing with JwtSecretKey, "RS256" or "EdDSA". With the latter
+	// two, JwtKeys lists PEM key files as comma separated kid=path pairs and JwtActiveKeyID
+	// picks the one that signs; the others only verify tokens issued before a key rotation.
+	JwtAlgorithm   string `json:"jwt_algorithm"`
+	JwtSecretKey   string `json:"jwt_secret_key"`
+	JwtKeys        string `json:"jwt_keys"`
+	JwtActiveKeyID string `json:"jwt_active_key_id"`
+	// JwtIssuer and, when set, JwtAudience are put in every token and checked on verification
+	// with JwtLeeway of allowed clock skew.
+	JwtIssuer          string        `json:"jwt_issuer"`
+	JwtAudience        string        `json:"jwt_audience"`
+	JwtLeeway          time.Duration `json:"jwt_leeway"`
+	JwtAccessTokenTTL  time.Duration `json:"jwt_access_token_ttl"`
+	JwtRefreshTokenTTL time.Duration `json:"jwt_refresh_token_ttl"`
+
+	// TokenRevocationStore is "postgres" or "memory".
+	TokenRevocationStore           string        `json:"token_revocation_store"`
+	TokenRevocationCleanupInterval time.Duration `json:"token_revocation_cleanup_interval"`
+
+	// PasswordHistorySize is how many recent passwords a user may not reuse.
+	PasswordHistorySize int           `json:"password_history_size"`
+	PasswordResetTTL    time.Duration `json:"password_reset_ttl"`
+	InvitationTTL       time.Duration `json:"invitation_ttl"`
+
+	// ContentSchedulerInterval is how often scheduled contents that are due are published and
+	// published ones whose unpublish_at has passed are archived.
+	ContentSchedulerInterval time.Duration `json:"content_scheduler_interval"`
+	// SearchLanguage is the Postgres text search configuration, such as "english" or
+	// "indonesian"; "simple" does no stemming.
+	SearchLanguage string `json:"search_language"`
+	// SearchTermsRefreshInterval is how often the words "did you mean" suggestions are picked
+	// from are collected.
+	SearchTermsRefreshInterval time.Duration `json:"search_terms_refresh_interval"`
+
+	// RolePermissionsRefreshInterval is how often the role_permissions table is reloaded.
+	RolePermissionsRefreshInterval time.Duration `json:"role_permissions_refresh_interval"`
+
+	// MfaIssuer is the name authenticator apps show for enrolled accounts and MfaChallengeTTL
+	// how long a login may wait for its second factor.
+	MfaIssuer       string        `json:"mfa_issuer"`
+	MfaChallengeTTL time.Duration `json:"mfa_challenge_ttl"`
+
+	// Failed logins and password reset requests are counted per account and per client IP
+	// for LoginAttemptWindow, in LoginAttemptStore, "postgres" or "memory". After a few, each
+	// attempt waits exponentially longer starting at LoginBackoffBase, and LoginMaxFailures
+	// (LoginIPMaxFailures for an IP) locks the key for LoginLockoutDuration.
+	LoginAttemptStore           string        `json:"login_attempt_store"`
+	LoginAttemptWindow          time.Duration `json:"login_attempt_window"`
+	LoginAttemptCleanupInterval time.Duration `json:"login_attempt_cleanup_interval"`
+	LoginBackoffBase            time.Duration `json:"login_backoff_base"`
+	LoginLockoutDuration        time.Duration `json:"login_lockout_duration"`
+	LoginMaxFailures            int           `json:"login_max_failures"`
+	LoginIPMaxFailures          int           `json:"login_ip_max_failures"`
 
-	JwtSecretKey string `json:"jwt_secret_key"`
-	JwtIssuer    string `json:"jwt_issuer"`
+	// OidcIssuerURL enables single sign-on with an OpenID Connect provider.
+	OidcIssuerURL    string `json:"oidc_issuer_url"`
+	OidcClientID     string `json:"oidc_client_id"`
+	OidcClientSecret string `json:"oidc_client_secret"`
+	OidcRedirectURL  string `json:"oidc_redirect_url"`
+	OidcScopes       string `json:"oidc_scopes"`
+	// OidcRoleMapping maps values of the OidcRoleClaim claim to roles as comma separated
+	// value=role pairs, the first match winning. Existing users only change role when a
+	// mapping matches, and admins are never demoted.
+	OidcRoleClaim   string `json:"oidc_role_claim"`
+	OidcRoleMapping string `json:"oidc_role_mapping"`
+	// OidcDefaultRole is given to new users whose identity matches no mapping; when empty
+	// they are refused.
+	OidcDefaultRole string `json:"oidc_default_role"`
+	// OidcLinkExistingUsers links identities to local accounts with the same email, except
+	// accounts protected by MFA.
+	OidcLinkExistingUsers bool          `json:"oidc_link_existing_users"`
+	OidcStateTTL          time.Duration `json:"oidc_state_ttl"`
 }
 
 type PsqlDB struct {
@@ -29,10 +111,36 @@ type CloudflareR2 struct {
 	PublicURL string `json:"public_url"`
 }
 
+type Mail struct {
+	// Driver is "smtp" or "log", writing mails to LogDir.
+	Driver       string `json:"driver"`
+	From         string `json:"from"`
+	SMTPHost     string `json:"smtp_host"`
+	SMTPPort     string `json:"smtp_port"`
+	SMTPUsername string `json:"smtp_username"`
+	SMTPPassword string `json:"smtp_password"`
+	LogDir       string `json:"log_dir"`
+}
+
+type Event struct {
+	// Driver is "log" or "webhook", POSTing events to WebhookURL signed with WebhookSecret.
+	Driver         string        `json:"driver"`
+	WebhookURL     string        `json:"webhook_url"`
+	WebhookSecret  string        `json:"webhook_secret"`
+	WebhookTimeout time.Duration `json:"webhook_timeout"`
+	// Webhook events are queued, up to QueueSize, and delivered in the background with up to
+	// MaxAttempts attempts, starting from RetryBackoff between them.
+	QueueSize    int           `json:"queue_size"`
+	MaxAttempts  int           `json:"max_attempts"`
+	RetryBackoff time.Duration `json:"retry_backoff"`
+}
+
 type Config struct {
 	App  App
 	Psql PsqlDB
 	R2 CloudflareR2
+	Mail Mail
+	Event Event
 }
 
 // NewConfig creates and returns a new Config instance.
@@ -40,17 +148,105 @@ type Config struct {
 // using the viper package. The function populates both the App and PsqlDB
 // structs within the Config.
 //
+// Durations are read as Go durations such as "15m" or "168h"; every interval and TTL must be
+// positive and JWT_LEEWAY must not be negative.
+//
 // Returns:
 //   - *Config: A pointer to a new Config instance with all fields populated
 //     from the corresponding environment variables.
-func NewConfig() *Config {
+//   - error: An error naming the first setting that is not a valid duration.
+func NewConfig() (*Config, error) {
+	viper.SetDefault("JWT_ALGORITHM", "HS256")
+	viper.SetDefault("JWT_LEEWAY", "30s")
+	viper.SetDefault("JWT_ACCESS_TOKEN_TTL", "15m")
+	viper.SetDefault("JWT_REFRESH_TOKEN_TTL", "168h")
+	viper.SetDefault("TOKEN_REVOCATION_STORE", "postgres")
+	viper.SetDefault("TOKEN_REVOCATION_CLEANUP_INTERVAL", "1h")
+	viper.SetDefault("PASSWORD_HISTORY_SIZE", 3)
+	viper.SetDefault("PASSWORD_RESET_TTL", "1h")
+	viper.SetDefault("INVITATION_TTL", "168h")
+	viper.SetDefault("ROLE_PERMISSIONS_REFRESH_INTERVAL", "1m")
+	viper.SetDefault("MFA_ISSUER", "Portal Blog")
+	viper.SetDefault("MFA_CHALLENGE_TTL", "5m")
+	viper.SetDefault("LOGIN_ATTEMPT_STORE", "postgres")
+	viper.SetDefault("LOGIN_ATTEMPT_WINDOW", "15m")
+	viper.SetDefault("LOGIN_ATTEMPT_CLEANUP_INTERVAL", "1h")
+	viper.SetDefault("LOGIN_BACKOFF_BASE", "1s")
+	viper.SetDefault("LOGIN_LOCKOUT_DURATION", "15m")
+	viper.SetDefault("LOGIN_MAX_FAILURES", 10)
+	viper.SetDefault("LOGIN_IP_MAX_FAILURES", 100)
+	viper.SetDefault("OIDC_SCOPES", "openid profile email")
+	viper.SetDefault("OIDC_ROLE_CLAIM", "groups")
+	viper.SetDefault("OIDC_STATE_TTL", "10m")
+	viper.SetDefault("MAIL_DRIVER", "log")
+	viper.SetDefault("MAIL_LOG_DIR", "./temp/mail")
+	viper.SetDefault("CONTENT_SCHEDULER_INTERVAL", "1m")
+	viper.SetDefault("SEARCH_LANGUAGE", "simple")
+	viper.SetDefault("SEARCH_TERMS_REFRESH_INTERVAL", "15m")
+	viper.SetDefault("EVENT_DRIVER", "log")
+	viper.SetDefault("EVENT_WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("EVENT_QUEUE_SIZE", 100)
+	viper.SetDefault("EVENT_MAX_ATTEMPTS", 5)
+	viper.SetDefault("EVENT_RETRY_BACKOFF", "1s")
+
+	err := validateDurations()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		App: App{
-			AppPort: viper.GetString("APP_PORT"),
-			AppEnv:  viper.GetString("APP_ENV"),
+			AppPort:     viper.GetString("APP_PORT"),
+			AppEnv:      viper.GetString("APP_ENV"),
+			FrontendURL: viper.GetString("APP_FRONTEND_URL"),
+
+			ProxyHeader:    viper.GetString("APP_PROXY_HEADER"),
+			TrustedProxies: viper.GetString("APP_TRUSTED_PROXIES"),
 
-			JwtSecretKey: viper.GetString("JWT_SECRET_KEY"),
-			JwtIssuer:    viper.GetString("JWT_ISSUER"),
+			JwtAlgorithm:       viper.GetString("JWT_ALGORITHM"),
+			JwtSecretKey:       viper.GetString("JWT_SECRET_KEY"),
+			JwtKeys:            viper.GetString("JWT_KEYS"),
+			JwtActiveKeyID:     viper.GetString("JWT_ACTIVE_KEY_ID"),
+			JwtIssuer:          viper.GetString("JWT_ISSUER"),
+			JwtAudience:        viper.GetString("JWT_AUDIENCE"),
+			JwtLeeway:          viper.GetDuration("JWT_LEEWAY"),
+			JwtAccessTokenTTL:  viper.GetDuration("JWT_ACCESS_TOKEN_TTL"),
+			JwtRefreshTokenTTL: viper.GetDuration("JWT_REFRESH_TOKEN_TTL"),
+
+			TokenRevocationStore:           viper.GetString("TOKEN_REVOCATION_STORE"),
+			TokenRevocationCleanupInterval: viper.GetDuration("TOKEN_REVOCATION_CLEANUP_INTERVAL"),
+
+			PasswordHistorySize: viper.GetInt("PASSWORD_HISTORY_SIZE"),
+			PasswordResetTTL:    viper.GetDuration("PASSWORD_RESET_TTL"),
+			InvitationTTL:       viper.GetDuration("INVITATION_TTL"),
+
+			ContentSchedulerInterval:   viper.GetDuration("CONTENT_SCHEDULER_INTERVAL"),
+			SearchLanguage:             viper.GetString("SEARCH_LANGUAGE"),
+			SearchTermsRefreshInterval: viper.GetDuration("SEARCH_TERMS_REFRESH_INTERVAL"),
+
+			RolePermissionsRefreshInterval: viper.GetDuration("ROLE_PERMISSIONS_REFRESH_INTERVAL"),
+
+			MfaIssuer:       viper.GetString("MFA_ISSUER"),
+			MfaChallengeTTL: viper.GetDuration("MFA_CHALLENGE_TTL"),
+
+			LoginAttemptStore:           viper.GetString("LOGIN_ATTEMPT_STORE"),
+			LoginAttemptWindow:          viper.GetDuration("LOGIN_ATTEMPT_WINDOW"),
+			LoginAttemptCleanupInterval: viper.GetDuration("LOGIN_ATTEMPT_CLEANUP_INTERVAL"),
+			LoginBackoffBase:            viper.GetDuration("LOGIN_BACKOFF_BASE"),
+			LoginLockoutDuration:        viper.GetDuration("LOGIN_LOCKOUT_DURATION"),
+			LoginMaxFailures:            viper.GetInt("LOGIN_MAX_FAILURES"),
+			LoginIPMaxFailures:          viper.GetInt("LOGIN_IP_MAX_FAILURES"),
+
+			OidcIssuerURL:         viper.GetString("OIDC_ISSUER_URL"),
+			OidcClientID:          viper.GetString("OIDC_CLIENT_ID"),
+			OidcClientSecret:      viper.GetString("OIDC_CLIENT_SECRET"),
+			OidcRedirectURL:       viper.GetString("OIDC_REDIRECT_URL"),
+			OidcScopes:            viper.GetString("OIDC_SCOPES"),
+			OidcRoleClaim:         viper.GetString("OIDC_ROLE_CLAIM"),
+			OidcRoleMapping:       viper.GetString("OIDC_ROLE_MAPPING"),
+			OidcDefaultRole:       viper.GetString("OIDC_DEFAULT_ROLE"),
+			OidcLinkExistingUsers: viper.GetBool("OIDC_LINK_EXISTING_USERS"),
+			OidcStateTTL:          viper.GetDuration("OIDC_STATE_TTL"),
 		},
 
 		Psql: PsqlDB{
@@ -71,5 +267,59 @@ func NewConfig() *Config {
       AccountID:  viper.GetString("CLOUDFLARE_R2_ACCOUNT_ID"),
       PublicURL: viper.GetString("CLOUDFLARE_R2_PUBLIC_URL"),
 		},
+
+		Mail: Mail{
+			Driver:       viper.GetString("MAIL_DRIVER"),
+			From:         viper.GetString("MAIL_FROM"),
+			SMTPHost:     viper.GetString("MAIL_SMTP_HOST"),
+			SMTPPort:     viper.GetString("MAIL_SMTP_PORT"),
+			SMTPUsername: viper.GetString("MAIL_SMTP_USERNAME"),
+			SMTPPassword: viper.GetString("MAIL_SMTP_PASSWORD"),
+			LogDir:       viper.GetString("MAIL_LOG_DIR"),
+		},
+
+		Event: Event{
+			Driver:         viper.GetString("EVENT_DRIVER"),
+			WebhookURL:     viper.GetString("EVENT_WEBHOOK_URL"),
+			WebhookSecret:  viper.GetString("EVENT_WEBHOOK_SECRET"),
+			WebhookTimeout: viper.GetDuration("EVENT_WEBHOOK_TIMEOUT"),
+			QueueSize:      viper.GetInt("EVENT_QUEUE_SIZE"),
+			MaxAttempts:    viper.GetInt("EVENT_MAX_ATTEMPTS"),
+			RetryBackoff:   viper.GetDuration("EVENT_RETRY_BACKOFF"),
+		},
+	}, nil
+}
+
+// validateDurations checks the duration settings NewConfig reads. viper parses an invalid
+// duration as 0, so a typo fails here instead of, for example, panicking a ticker later.
+func validateDurations() error {
+	positive := []string{
+		"JWT_ACCESS_TOKEN_TTL",
+		"JWT_REFRESH_TOKEN_TTL",
+		"TOKEN_REVOCATION_CLEANUP_INTERVAL",
+		"PASSWORD_RESET_TTL",
+		"INVITATION_TTL",
+		"CONTENT_SCHEDULER_INTERVAL",
+		"SEARCH_TERMS_REFRESH_INTERVAL",
+		"ROLE_PERMISSIONS_REFRESH_INTERVAL",
+		"MFA_CHALLENGE_TTL",
+		"LOGIN_ATTEMPT_WINDOW",
+		"LOGIN_ATTEMPT_CLEANUP_INTERVAL",
+		"LOGIN_BACKOFF_BASE",
+		"LOGIN_LOCKOUT_DURATION",
+		"OIDC_STATE_TTL",
+		"EVENT_WEBHOOK_TIMEOUT",
+		"EVENT_RETRY_BACKOFF",
 	}
+	for _, key := range positive {
+		if viper.GetDuration(key) <= 0 {
+			return fmt.Errorf("%s must be a positive duration such as \"15m\", got %q", key, viper.GetString(key))
+		}
+	}
+
+	if _, err := time.ParseDuration(viper.GetString("JWT_LEEWAY")); err != nil || viper.GetDuration("JWT_LEEWAY") < 0 {
+		return fmt.Errorf("JWT_LEEWAY must be a duration of zero or more such as \"30s\", got %q", viper.GetString("JWT_LEEWAY"))
+	}
+
+	return nil
 }
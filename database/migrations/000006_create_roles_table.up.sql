@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS roles (
+  name VARCHAR(20) PRIMARY KEY,
+  description VARCHAR(200) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO roles (name, description) VALUES
+  ('admin', 'Full access, including user management'),
+  ('editor', 'Manages categories and every content'),
+  ('author', 'Writes, publishes and deletes own contents'),
+  ('contributor', 'Writes and edits own contents')
+ON CONFLICT (name) DO NOTHING;
+
+-- Existing users could already do everything, so they keep full access.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'admin' REFERENCES roles(name);
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'author';
+
+CREATE INDEX idx_users_role ON users(role);
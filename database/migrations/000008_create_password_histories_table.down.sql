@@ -0,0 +1,2 @@
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS access_token_jti;
+DROP TABLE IF EXISTS password_histories;
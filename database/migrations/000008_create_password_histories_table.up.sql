@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS password_histories (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  password VARCHAR(100) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_password_histories_user_id ON password_histories(user_id);
+
+-- Remember which access token was issued together with each refresh token so
+-- outstanding access tokens can be revoked when a user's password changes.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS access_token_jti VARCHAR(64) NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_access_token_jti;
+DROP TABLE IF EXISTS sessions;
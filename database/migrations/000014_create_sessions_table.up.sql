@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS sessions (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  family_id VARCHAR(36) UNIQUE NOT NULL,
+  user_agent VARCHAR(512) NOT NULL DEFAULT '',
+  ip VARCHAR(45) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expires_at TIMESTAMP NOT NULL,
+  revoked_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_access_token_jti ON refresh_tokens(access_token_jti);
+
+-- Logins made before sessions were tracked keep working.
+INSERT INTO sessions (user_id, family_id, created_at, last_seen_at, expires_at)
+SELECT user_id, family_id, MIN(created_at), MAX(created_at), MAX(expires_at)
+FROM refresh_tokens
+GROUP BY user_id, family_id
+HAVING BOOL_OR(revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP);
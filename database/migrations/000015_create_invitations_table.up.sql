@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS invitations (
+  id SERIAL PRIMARY KEY,
+  email VARCHAR(255) NOT NULL,
+  role VARCHAR(20) NOT NULL REFERENCES roles(name),
+  token_hash VARCHAR(64) UNIQUE NOT NULL,
+  invited_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+  user_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+  expires_at TIMESTAMP NOT NULL,
+  accepted_at TIMESTAMP NULL,
+  revoked_at TIMESTAMP NULL,
+  sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_invitations_pending_email ON invitations(LOWER(email)) WHERE accepted_at IS NULL AND revoked_at IS NULL;
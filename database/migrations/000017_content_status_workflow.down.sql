@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_contents_status;
+ALTER TABLE contents DROP COLUMN IF EXISTS rejection_reason;
+ALTER TABLE contents DROP CONSTRAINT IF EXISTS contents_status_check;
+ALTER TABLE contents ALTER COLUMN status DROP NOT NULL;
+ALTER TABLE contents ALTER COLUMN status SET DEFAULT 'PUBLISH';
+UPDATE contents SET status = 'PUBLISH' WHERE status = 'PUBLISHED';
@@ -0,0 +1,13 @@
+UPDATE contents SET status = 'PUBLISHED' WHERE status = 'PUBLISH';
+UPDATE contents SET status = 'DRAFT'
+WHERE status IS NULL OR status NOT IN ('DRAFT', 'IN_REVIEW', 'SCHEDULED', 'PUBLISHED', 'ARCHIVED');
+
+ALTER TABLE contents ALTER COLUMN status SET DEFAULT 'DRAFT';
+ALTER TABLE contents ALTER COLUMN status SET NOT NULL;
+ALTER TABLE contents ADD CONSTRAINT contents_status_check
+  CHECK (status IN ('DRAFT', 'IN_REVIEW', 'SCHEDULED', 'PUBLISHED', 'ARCHIVED'));
+
+-- Why the last review sent the content back to draft; cleared when it is submitted again.
+ALTER TABLE contents ADD COLUMN rejection_reason TEXT NULL;
+
+CREATE INDEX idx_contents_status ON contents(status);
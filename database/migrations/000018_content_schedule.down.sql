@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_contents_unpublish_at;
+DROP INDEX IF EXISTS idx_contents_publish_at;
+ALTER TABLE contents DROP CONSTRAINT IF EXISTS contents_schedule_check;
+ALTER TABLE contents DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE contents DROP COLUMN IF EXISTS publish_at;
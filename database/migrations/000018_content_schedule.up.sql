@@ -0,0 +1,8 @@
+-- When a SCHEDULED content goes live and when a PUBLISHED one is taken down again.
+ALTER TABLE contents ADD COLUMN publish_at TIMESTAMP NULL;
+ALTER TABLE contents ADD COLUMN unpublish_at TIMESTAMP NULL;
+ALTER TABLE contents ADD CONSTRAINT contents_schedule_check
+  CHECK (publish_at IS NULL OR unpublish_at IS NULL OR unpublish_at > publish_at);
+
+CREATE INDEX idx_contents_publish_at ON contents(publish_at) WHERE status = 'SCHEDULED';
+CREATE INDEX idx_contents_unpublish_at ON contents(unpublish_at) WHERE status = 'PUBLISHED';
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_content_revisions_immutable ON content_revisions;
+DROP FUNCTION IF EXISTS content_revisions_immutable();
+DROP TABLE IF EXISTS content_revisions;
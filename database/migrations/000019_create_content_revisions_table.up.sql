@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS content_revisions (
+  id BIGSERIAL PRIMARY KEY,
+  content_id INT NOT NULL REFERENCES contents(id) ON DELETE CASCADE,
+  revision INT NOT NULL,
+  title VARCHAR(200) NOT NULL,
+  excerpt VARCHAR(250) NOT NULL,
+  description TEXT NOT NULL,
+  image TEXT NULL,
+  tags TEXT NOT NULL,
+  -- No foreign key: a revision keeps the category it was written with even after it is deleted.
+  category_id INT NULL,
+  author_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+  restored_from INT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (content_id, revision)
+);
+
+-- Revisions are immutable; they only go away together with their content.
+CREATE OR REPLACE FUNCTION content_revisions_immutable() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'content_revisions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_content_revisions_immutable
+  BEFORE UPDATE ON content_revisions
+  FOR EACH ROW EXECUTE FUNCTION content_revisions_immutable();
+
+-- The current state of every content becomes its first revision.
+INSERT INTO content_revisions (content_id, revision, title, excerpt, description, image, tags, category_id, author_id, created_at)
+SELECT id, 1, title, excerpt, description, image, tags, category_id, created_by_id, COALESCE(updated_at, created_at)
+FROM contents;
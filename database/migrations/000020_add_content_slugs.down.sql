@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS content_slug_redirects;
+DROP INDEX IF EXISTS idx_contents_slug;
+ALTER TABLE contents DROP COLUMN IF EXISTS slug;
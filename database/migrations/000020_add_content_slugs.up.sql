@@ -0,0 +1,32 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+ALTER TABLE contents ADD COLUMN slug VARCHAR(200) NULL;
+
+-- Existing contents get a slug from their title; the application transliterates new ones.
+-- Duplicates and titles without any ASCII letter fall back to a suffix with the content ID.
+WITH generated AS (
+  SELECT id, left(trim(BOTH '-' FROM regexp_replace(lower(unaccent(title)), '[^a-z0-9]+', '-', 'g')), 180) AS slug
+  FROM contents
+), ranked AS (
+  SELECT id, slug, row_number() OVER (PARTITION BY slug ORDER BY id) AS n
+  FROM generated
+)
+UPDATE contents SET slug = CASE
+    WHEN ranked.slug = '' THEN 'content-' || contents.id
+    WHEN ranked.n > 1 THEN ranked.slug || '-' || contents.id
+    ELSE ranked.slug
+  END
+FROM ranked
+WHERE ranked.id = contents.id;
+
+ALTER TABLE contents ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX idx_contents_slug ON contents(slug);
+
+-- Slugs a content had before, so links to them keep working after its title changes.
+CREATE TABLE IF NOT EXISTS content_slug_redirects (
+  slug VARCHAR(200) PRIMARY KEY,
+  content_id INT NOT NULL REFERENCES contents(id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_content_slug_redirects_content_id ON content_slug_redirects(content_id);
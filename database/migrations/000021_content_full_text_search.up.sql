@@ -0,0 +1,13 @@
+-- The text search configuration each content was indexed with. The application keeps it equal
+-- to SEARCH_LANGUAGE, re-indexing existing contents when the setting changes.
+ALTER TABLE contents ADD COLUMN search_language REGCONFIG NOT NULL DEFAULT 'simple';
+
+-- Matches in the title rank highest, then the excerpt and tags, then the body.
+ALTER TABLE contents ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+  setweight(to_tsvector(search_language, title), 'A') ||
+  setweight(to_tsvector(search_language, excerpt), 'B') ||
+  setweight(to_tsvector(search_language, replace(tags, ',', ' ')), 'B') ||
+  setweight(to_tsvector(search_language, description), 'C')
+) STORED;
+
+CREATE INDEX idx_contents_search_vector ON contents USING GIN (search_vector);
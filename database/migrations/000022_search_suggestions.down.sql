@@ -0,0 +1,3 @@
+DROP MATERIALIZED VIEW IF EXISTS content_search_terms;
+DROP INDEX IF EXISTS idx_categories_title_trgm;
+DROP INDEX IF EXISTS idx_contents_title_trgm;
@@ -0,0 +1,21 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_contents_title_trgm ON contents USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_categories_title_trgm ON categories USING GIN (title gin_trgm_ops);
+
+-- Words of live contents, for "did you mean" suggestions, with the conditions the application
+-- uses for what the public can read. They are checked when the application refreshes the view,
+-- so a content that goes live or is unpublished is followed at the next refresh.
+CREATE MATERIALIZED VIEW content_search_terms AS
+SELECT word, ndoc
+FROM ts_stat($$
+  SELECT to_tsvector('simple', title || ' ' || excerpt || ' ' || replace(tags, ',', ' ') || ' ' || description)
+  FROM contents
+  WHERE status = 'PUBLISHED'
+    AND (publish_at IS NULL OR publish_at <= now())
+    AND (unpublish_at IS NULL OR unpublish_at > now())
+$$)
+WHERE length(word) > 2;
+
+CREATE UNIQUE INDEX idx_content_search_terms_word ON content_search_terms(word);
+CREATE INDEX idx_content_search_terms_word_trgm ON content_search_terms USING GIN (word gin_trgm_ops);
@@ -0,0 +1,25 @@
+-- What each role may do. The application caches this table and reloads it every
+-- ROLE_PERMISSIONS_REFRESH_INTERVAL, so changes apply without a deploy.
+CREATE TABLE IF NOT EXISTS role_permissions (
+  role VARCHAR(20) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+  permission VARCHAR(50) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (role, permission)
+);
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('admin', 'category:read'), ('admin', 'category:write'), ('admin', 'category:delete'),
+  ('admin', 'content:read'), ('admin', 'content:write'), ('admin', 'content:delete'),
+  ('admin', 'content:manage_all'), ('admin', 'content:upload'), ('admin', 'content:publish'),
+  ('admin', 'user:manage'), ('admin', 'audit:read'),
+
+  ('editor', 'category:read'), ('editor', 'category:write'), ('editor', 'category:delete'),
+  ('editor', 'content:read'), ('editor', 'content:write'), ('editor', 'content:delete'),
+  ('editor', 'content:manage_all'), ('editor', 'content:upload'), ('editor', 'content:publish'),
+
+  ('author', 'category:read'),
+  ('author', 'content:read'), ('author', 'content:write'), ('author', 'content:delete'), ('author', 'content:upload'),
+
+  ('contributor', 'category:read'),
+  ('contributor', 'content:read'), ('contributor', 'content:write')
+ON CONFLICT (role, permission) DO NOTHING;
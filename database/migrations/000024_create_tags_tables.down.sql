@@ -0,0 +1,3 @@
+DELETE FROM role_permissions WHERE permission IN ('tag:read', 'tag:write', 'tag:delete');
+DROP TABLE IF EXISTS content_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,65 @@
+CREATE TABLE IF NOT EXISTS tags (
+  id SERIAL PRIMARY KEY,
+  title VARCHAR(50) NOT NULL,
+  slug VARCHAR(200) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NULL
+);
+
+CREATE UNIQUE INDEX idx_tags_slug ON tags(slug);
+CREATE INDEX idx_tags_title_trgm ON tags USING GIN (title gin_trgm_ops);
+
+-- position keeps the tags of a content in the order they were given.
+CREATE TABLE IF NOT EXISTS content_tags (
+  content_id INT NOT NULL REFERENCES contents(id) ON DELETE CASCADE,
+  tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  position INT NOT NULL,
+  PRIMARY KEY (content_id, tag_id)
+);
+
+CREATE INDEX idx_content_tags_tag_id ON content_tags(tag_id);
+
+-- Existing tags are trimmed, empty ones dropped, and tags with the same slug become one tag.
+-- Tags without any ASCII letter are told apart by their lowercase title and get a slug with
+-- their ID, as the application transliterates new ones.
+CREATE TEMP TABLE tag_import AS
+SELECT content_id, n, title,
+  CASE WHEN slug = '' THEN 'title:' || lower(title) ELSE slug END AS key
+FROM (
+  SELECT contents.id AS content_id, item.n, left(trim(item.title), 50) AS title,
+    left(trim(BOTH '-' FROM regexp_replace(lower(unaccent(trim(item.title))), '[^a-z0-9]+', '-', 'g')), 180) AS slug
+  FROM contents, unnest(string_to_array(contents.tags, ',')) WITH ORDINALITY AS item(title, n)
+) AS items
+WHERE title <> '';
+
+INSERT INTO tags (title, slug)
+SELECT DISTINCT ON (key) title, key
+FROM tag_import
+ORDER BY key, content_id, n;
+
+INSERT INTO content_tags (content_id, tag_id, position)
+SELECT DISTINCT ON (tag_import.content_id, tags.id) tag_import.content_id, tags.id, tag_import.n
+FROM tag_import
+JOIN tags ON tags.slug = tag_import.key
+ORDER BY tag_import.content_id, tags.id, tag_import.n;
+
+UPDATE tags SET slug = 'tag-' || id WHERE slug LIKE 'title:%';
+
+DROP TABLE tag_import;
+
+-- contents.tags stays as the comma-joined titles of the tags of each content, for the full
+-- text index and the search terms. The application keeps it in step with content_tags.
+UPDATE contents SET tags = COALESCE((
+  SELECT string_agg(tags.title, ',' ORDER BY content_tags.position)
+  FROM content_tags
+  JOIN tags ON tags.id = content_tags.tag_id
+  WHERE content_tags.content_id = contents.id
+), '');
+
+-- Tags are managed by admins and editors and readable by every role.
+INSERT INTO role_permissions (role, permission) VALUES
+  ('admin', 'tag:read'), ('admin', 'tag:write'), ('admin', 'tag:delete'),
+  ('editor', 'tag:read'), ('editor', 'tag:write'), ('editor', 'tag:delete'),
+  ('author', 'tag:read'),
+  ('contributor', 'tag:read')
+ON CONFLICT (role, permission) DO NOTHING;
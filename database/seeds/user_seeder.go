@@ -1,6 +1,7 @@
 package seeds
 
 import (
+	"portal-blog/internal/core/domain/entity"
 	"portal-blog/internal/core/domain/model"
 	"portal-blog/lib/conv"
 
@@ -23,6 +24,8 @@ func SeedRoles(db *gorm.DB) {
         Name:     "Admin",
         Email:    "admin@example.com",
         Password: string(bytes),
+        Role:     entity.RoleAdmin,
+        IsActive: true,
     }
 
     if err := db.FirstOrCreate(&admin, model.User{Email: "admin@mail.com"}).Error; err != nil {
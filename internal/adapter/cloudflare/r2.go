@@ -12,9 +12,6 @@ import (
 	"github.com/gofiber/fiber/v2/log"
 )
 
-var code string
-var err error
-
 type CloudflareR2Adapter interface {
 	UploadImage(req *entity.FileUploadEntity) (string, error)
 }
@@ -29,7 +26,7 @@ type cloudflareR2Adapter struct {
 func (c *cloudflareR2Adapter) UploadImage(req *entity.FileUploadEntity) (string, error) {
 	openedFile, err := os.Open(req.Path)
 	if err != nil {
-		code = "[CLOUDFLARE] UploadImage - 1"
+		code := "[CLOUDFLARE] UploadImage - 1"
 		log.Errorw(code, err)
 		return "", err
 	}
@@ -44,7 +41,7 @@ func (c *cloudflareR2Adapter) UploadImage(req *entity.FileUploadEntity) (string,
 	})
 
 	if err != nil {
-		code = "[CLOUDFLARE] UploadImage - 2"
+		code := "[CLOUDFLARE] UploadImage - 2"
 		log.Errorw(code, err)
 		return "", err
 	}
@@ -0,0 +1,110 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"portal-blog/config"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/port"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// ErrQueueFull is returned by AsyncPublisher.Publish when EVENT_QUEUE_SIZE events are already
+// waiting for delivery.
+var ErrQueueFull = errors.New("event queue is full")
+
+// AsyncPublisher queues events and hands them to another port.EventPublisher from Run, so
+// callers never wait on a slow listener. Failed deliveries are retried up to EVENT_MAX_ATTEMPTS
+// times, waiting EVENT_RETRY_BACKOFF before the first retry and twice as long before each next
+// one. Delivery is at-least-once while the process runs; events still queued or retrying when
+// the process exits are lost.
+type AsyncPublisher struct {
+	next        port.EventPublisher
+	queue       chan entity.EventEntity
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// Publish implements port.EventPublisher.
+// The event is only queued; ctx is not used for the delivery.
+func (a *AsyncPublisher) Publish(ctx context.Context, event entity.EventEntity) error {
+	select {
+	case a.queue <- event:
+		return nil
+	default:
+		code := "[EVENT] Publish - 1"
+		log.Errorw(code, ErrQueueFull)
+		return ErrQueueFull
+	}
+}
+
+// Run delivers queued events until ctx is cancelled, then gives every event still queued one
+// last attempt before returning. It is meant to run in its own goroutine for the lifetime of
+// the server.
+func (a *AsyncPublisher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			a.drain()
+			return
+		case event := <-a.queue:
+			a.deliver(ctx, event)
+		}
+	}
+}
+
+// deliver publishes event, retrying with backoff until it succeeds, maxAttempts is reached
+// or ctx is cancelled.
+func (a *AsyncPublisher) deliver(ctx context.Context, event entity.EventEntity) {
+	wait := a.backoff
+	for attempt := 1; ; attempt++ {
+		err := a.next.Publish(context.Background(), event)
+		if err == nil {
+			return
+		}
+
+		if attempt >= a.maxAttempts {
+			code := "[EVENT] deliver - 1"
+			log.Errorw(code, err)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			code := "[EVENT] deliver - 2"
+			log.Errorw(code, err)
+			return
+		case <-time.After(wait):
+			wait *= 2
+		}
+	}
+}
+
+// drain makes a single delivery attempt for every event left in the queue.
+func (a *AsyncPublisher) drain() {
+	for {
+		select {
+		case event := <-a.queue:
+			err := a.next.Publish(context.Background(), event)
+			if err != nil {
+				code := "[EVENT] drain - 1"
+				log.Errorw(code, err)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// NewAsyncPublisher creates an AsyncPublisher delivering to next. Run must be started for
+// queued events to be delivered.
+func NewAsyncPublisher(next port.EventPublisher, cfg *config.Config) *AsyncPublisher {
+	return &AsyncPublisher{
+		next:        next,
+		queue:       make(chan entity.EventEntity, cfg.Event.QueueSize),
+		maxAttempts: cfg.Event.MaxAttempts,
+		backoff:     cfg.Event.RetryBackoff,
+	}
+}
@@ -0,0 +1,33 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/port"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type logPublisher struct{}
+
+// Publish implements port.EventPublisher.
+func (l *logPublisher) Publish(ctx context.Context, event entity.EventEntity) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		code := "[EVENT] Publish - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	log.Infof("[EVENT] type=%s occurred_at=%s data=%s", event.Type, event.OccurredAt.Format(time.RFC3339), data)
+
+	return nil
+}
+
+// NewLogPublisher creates a port.EventPublisher that only logs events, for local development
+// and deployments nothing listens to.
+func NewLogPublisher() port.EventPublisher {
+	return &logPublisher{}
+}
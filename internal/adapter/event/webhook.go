@@ -0,0 +1,78 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"portal-blog/config"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/port"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// SignatureHeader carries the hex encoded HMAC-SHA256 of the request body, keyed with the
+// webhook secret, so receivers can check that an event came from us.
+const SignatureHeader = "X-Portal-Signature"
+
+type webhookPublisher struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// Publish implements port.EventPublisher.
+// The event is POSTed as JSON; any status other than 2xx is an error.
+func (w *webhookPublisher) Publish(ctx context.Context, event entity.EventEntity) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		code := "[EVENT] Publish - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		code := "[EVENT] Publish - 2"
+		log.Errorw(code, err)
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		code := "[EVENT] Publish - 3"
+		log.Errorw(code, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		code := "[EVENT] Publish - 4"
+		err = fmt.Errorf("webhook answered %s to %s event", resp.Status, event.Type)
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// NewWebhookPublisher creates a port.EventPublisher that POSTs every event to EVENT_WEBHOOK_URL.
+func NewWebhookPublisher(cfg *config.Config) port.EventPublisher {
+	return &webhookPublisher{
+		url:    cfg.Event.WebhookURL,
+		secret: cfg.Event.WebhookSecret,
+		client: &http.Client{Timeout: cfg.Event.WebhookTimeout},
+	}
+}
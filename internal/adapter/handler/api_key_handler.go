@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"portal-blog/internal/adapter/handler/request"
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/service"
+	"portal-blog/lib/conv"
+	valid "portal-blog/lib/validator"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type APIKeyHandler interface {
+	GetAPIKeys(c *fiber.Ctx) error
+	CreateAPIKey(c *fiber.Ctx) error
+	RevokeAPIKey(c *fiber.Ctx) error
+}
+
+type apiKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+// GetAPIKeys implements APIKeyHandler.
+// It lists the API keys of the logged in user, including revoked ones.
+func (a *apiKeyHandler) GetAPIKeys(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetAPIKeys - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	results, err := a.apiKeyService.GetAPIKeys(c.Context(), int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] GetAPIKeys - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	apiKeyResponses := []response.APIKeyResponse{}
+	for _, result := range results {
+		apiKeyResponses = append(apiKeyResponses, toAPIKeyResponse(result))
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(apiKeyResponses))
+}
+
+// CreateAPIKey implements APIKeyHandler.
+// The plain key is part of this response only and cannot be retrieved later.
+func (a *apiKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] CreateAPIKey - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	req := request.CreateAPIKeyRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] CreateAPIKey - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] CreateAPIKey - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	result, err := a.apiKeyService.CreateAPIKey(c.Context(), entity.APIKeyEntity{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}, claims)
+	if err != nil {
+		code := "[HANDLER] CreateAPIKey - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.NewSuccessResponse("API key created. Store it now, it will not be shown again").WithData(toAPIKeyResponse(*result)))
+}
+
+// RevokeAPIKey implements APIKeyHandler.
+func (a *apiKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] RevokeAPIKey - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	apiKeyID, err := conv.StringToInt64(c.Params("apiKeyID"))
+	if err != nil {
+		code := "[HANDLER] RevokeAPIKey - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = a.apiKeyService.RevokeAPIKey(c.Context(), apiKeyID, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] RevokeAPIKey - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("API key revoked"))
+}
+
+func toAPIKeyResponse(apiKey entity.APIKeyEntity) response.APIKeyResponse {
+	resp := response.APIKeyResponse{
+		ID:        apiKey.ID,
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Scopes:    apiKey.Scopes,
+		CreatedAt: apiKey.CreatedAt.Local().Format(time.RFC3339),
+		Key:       apiKey.Key,
+	}
+
+	if resp.Scopes == nil {
+		resp.Scopes = []string{}
+	}
+
+	if apiKey.ExpiresAt != nil {
+		resp.ExpiresAt = apiKey.ExpiresAt.Local().Format(time.RFC3339)
+	}
+
+	if apiKey.LastUsedAt != nil {
+		resp.LastUsedAt = apiKey.LastUsedAt.Local().Format(time.RFC3339)
+	}
+
+	if apiKey.RevokedAt != nil {
+		resp.RevokedAt = apiKey.RevokedAt.Local().Format(time.RFC3339)
+	}
+
+	return resp
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) APIKeyHandler {
+	return &apiKeyHandler{apiKeyService: apiKeyService}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/service"
+	"portal-blog/lib/conv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type AuditLogHandler interface {
+	GetAuditLogs(c *fiber.Ctx) error
+}
+
+type auditLogHandler struct {
+	auditLogService service.AuditLogService
+}
+
+// GetAuditLogs lists audit log entries with pagination, newest first.
+//
+// Input:
+//   - c: *fiber.Ctx - The request context. Supports the page, limit, actorID, action, entityType,
+//     entityID, from and to query parameters; from and to are RFC 3339 timestamps.
+//
+// Output:
+//   - error: Returns a bad request response for invalid query parameters or an internal server error if fetching fails.
+func (a *auditLogHandler) GetAuditLogs(c *fiber.Ctx) error {
+	var err error
+	// Page
+	page := 1
+	if c.Query("page") != "" {
+		page, err = conv.StringToInt(c.Query("page"))
+		if err != nil || page < 1 {
+			log.Errorw("[HANDLER] GetAuditLogs - 1", "Error parsing page query", err)
+			errorResp := response.NewErrorResponse("Invalid page number")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	// Limit
+	limit := 20
+	if c.Query("limit") != "" {
+		limit, err = conv.StringToInt(c.Query("limit"))
+		if err != nil || limit < 1 || limit > 100 {
+			log.Errorw("[HANDLER] GetAuditLogs - 2", "Error parsing limit query", err)
+			errorResp := response.NewErrorResponse("Invalid limit number")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	// Actor
+	var actorID int64
+	if c.Query("actorID") != "" {
+		actorID, err = conv.StringToInt64(c.Query("actorID"))
+		if err != nil || actorID < 1 {
+			log.Errorw("[HANDLER] GetAuditLogs - 3", "Error parsing actorID query", err)
+			errorResp := response.NewErrorResponse("Invalid actorID")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	// Period
+	var from, to *time.Time
+	if c.Query("from") != "" {
+		parsed, err := time.Parse(time.RFC3339, c.Query("from"))
+		if err != nil {
+			log.Errorw("[HANDLER] GetAuditLogs - 4", "Error parsing from query", err)
+			errorResp := response.NewErrorResponse("Invalid from timestamp, expected RFC 3339")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+		from = &parsed
+	}
+
+	if c.Query("to") != "" {
+		parsed, err := time.Parse(time.RFC3339, c.Query("to"))
+		if err != nil {
+			log.Errorw("[HANDLER] GetAuditLogs - 5", "Error parsing to query", err)
+			errorResp := response.NewErrorResponse("Invalid to timestamp, expected RFC 3339")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+		to = &parsed
+	}
+
+	queryEntity := entity.AuditLogQueryString{
+		Limit:      limit,
+		Page:       page,
+		ActorID:    actorID,
+		Action:     c.Query("action"),
+		EntityType: c.Query("entityType"),
+		EntityID:   c.Query("entityID"),
+		From:       from,
+		To:         to,
+	}
+
+	results, totalData, totalPages, err := a.auditLogService.GetAuditLogs(c.Context(), queryEntity)
+	if err != nil {
+		code := "[HANDLER] GetAuditLogs - 6"
+		log.Errorw(code, err)
+		return err
+	}
+
+	respAuditLogs := []response.AuditLogResponse{}
+	for _, result := range results {
+		respAuditLogs = append(respAuditLogs, response.AuditLogResponse{
+			ID:         result.ID,
+			ActorID:    result.ActorID,
+			APIKeyID:   result.APIKeyID,
+			Action:     result.Action,
+			EntityType: result.EntityType,
+			EntityID:   result.EntityID,
+			Before:     result.Before,
+			After:      result.After,
+			IP:         result.IP,
+			RequestID:  result.RequestID,
+			CreatedAt:  result.CreatedAt.Local().Format(time.RFC3339),
+		})
+	}
+
+	pagination := &response.PaginationResponse{
+		TotalRecords: int(totalData),
+		Page:         page,
+		PerPage:      limit,
+		TotalPages:   int(totalPages),
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(respAuditLogs).WithPagination(pagination))
+}
+
+func NewAuditLogHandler(auditLogService service.AuditLogService) AuditLogHandler {
+	return &auditLogHandler{auditLogService: auditLogService}
+}
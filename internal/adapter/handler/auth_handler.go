@@ -1,22 +1,25 @@
 package handler
 
 import (
+	"errors"
 	"portal-blog/internal/adapter/handler/request"
 	"portal-blog/internal/adapter/handler/response"
 	"portal-blog/internal/core/domain/entity"
 	"portal-blog/internal/core/service"
+	"portal-blog/lib/conv"
 	valid "portal-blog/lib/validator"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
 )
 
-var err error
-var code string
-var errorResp response.ErrorResponseDefault
-
 type AuthHandler interface {
 	Login(c *fiber.Ctx) error
+	LoginMfa(c *fiber.Ctx) error
+	RefreshToken(c *fiber.Ctx) error
+	Logout(c *fiber.Ctx) error
+	UnlockAccount(c *fiber.Ctx) error
 }
 
 type authHandler struct {
@@ -28,50 +31,213 @@ func (a *authHandler) Login(c *fiber.Ctx) error {
 	req := request.LoginRequest{}
 	resp := response.SuccessAuthResponse{}
 
-	if err = c.BodyParser(&req); err != nil {
-		code = "[HANDLER] Login - 1"
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] Login - 1"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	if err = valid.ValidateStruct(req); err != nil {
-		code = "[HANDLER] Login - 2"
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] Login - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		return err
 	}
 
 	reqLogin := entity.LoginRequest{
-		Email:    req.Email,
-		Password: req.Password,
+		Email:     req.Email,
+		Password:  req.Password,
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
 	}
 
 	result, err := a.authService.GetUserByEmail(c.Context(), reqLogin)
 	if err != nil {
-		code = "[HANDLER] Login - 3"
+		code := "[HANDLER] Login - 3"
+		log.Errorw(code, err)
+
+		var tooManyAttempts *service.TooManyLoginAttemptsError
+		if errors.As(err, &tooManyAttempts) {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(tooManyAttempts.RetryAfterSeconds()))
+			errorResp := response.NewErrorResponse(err.Error()).WithCode("too_many_login_attempts")
+
+			return c.Status(fiber.StatusTooManyRequests).JSON(errorResp)
+		}
+
+		return err
+	}
+
+	if result.MfaChallenge != nil {
+		return c.JSON(toMfaChallengeResponse(*result.MfaChallenge))
+	}
+
+	resp.Meta.Status = true
+	resp.Meta.Message = "Login Successfull"
+	resp.AccessToken = result.AccessToken
+	resp.ExpiredAt = result.ExpiredAt
+	resp.RefreshToken = result.RefreshToken
+	resp.RefreshExpiredAt = result.RefreshExpiredAt
+
+	return c.JSON(resp)
+
+}
+
+// LoginMfa implements AuthHandler.
+// It exchanges the MFA challenge token returned by Login and a TOTP or recovery code for the tokens.
+func (a *authHandler) LoginMfa(c *fiber.Ctx) error {
+	req := request.LoginMfaRequest{}
+	resp := response.SuccessAuthResponse{}
+
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] LoginMfa - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] LoginMfa - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	result, err := a.authService.LoginMfa(c.Context(), req.MfaToken, req.Code, clientInfo(c))
+	if err != nil {
+		code := "[HANDLER] LoginMfa - 3"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
 
-		if err.Error() == "invalid password" {
+		// A wrong code fails the login here instead of rejecting a form field as it does elsewhere.
+		if errors.Is(err, service.ErrInvalidMfaCode) {
+			errorResp := response.NewErrorResponse(err.Error()).WithCode(service.ErrInvalidMfaCode.Code)
+
 			return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
 	resp.Meta.Status = true
 	resp.Meta.Message = "Login Successfull"
 	resp.AccessToken = result.AccessToken
 	resp.ExpiredAt = result.ExpiredAt
+	resp.RefreshToken = result.RefreshToken
+	resp.RefreshExpiredAt = result.RefreshExpiredAt
+	resp.RecoveryCodes = result.RecoveryCodes
 
 	return c.JSON(resp)
+}
+
+// RefreshToken implements AuthHandler.
+// It exchanges a refresh token for a new access token and a rotated refresh token.
+func (a *authHandler) RefreshToken(c *fiber.Ctx) error {
+	req := request.RefreshTokenRequest{}
+	resp := response.SuccessAuthResponse{}
+
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] RefreshToken - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] RefreshToken - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	result, err := a.authService.RefreshToken(c.Context(), req.RefreshToken)
+	if err != nil {
+		code := "[HANDLER] RefreshToken - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	resp.Meta.Status = true
+	resp.Meta.Message = "Token refreshed successfully"
+	resp.AccessToken = result.AccessToken
+	resp.ExpiredAt = result.ExpiredAt
+	resp.RefreshToken = result.RefreshToken
+	resp.RefreshExpiredAt = result.RefreshExpiredAt
+
+	return c.JSON(resp)
+}
+
+// Logout implements AuthHandler.
+// It revokes the access token used for the request and, when provided, the refresh token of the session.
+func (a *authHandler) Logout(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] Logout - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	req := request.LogoutRequest{}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			code := "[HANDLER] Logout - 2"
+			log.Errorw(code, err)
+			errorResp := response.NewErrorResponse(err.Error())
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	err := a.authService.Logout(c.Context(), claims, req.RefreshToken)
+	if err != nil {
+		code := "[HANDLER] Logout - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Logout successfully"))
+}
+
+// UnlockAccount implements AuthHandler.
+// It lifts the login lockout of the user in the userID path parameter.
+func (a *authHandler) UnlockAccount(c *fiber.Ctx) error {
+	userID, err := conv.StringToInt64(c.Params("userID"))
+	if err != nil {
+		code := "[HANDLER] UnlockAccount - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = a.authService.UnlockAccount(c.Context(), userID)
+	if err != nil {
+		code := "[HANDLER] UnlockAccount - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Account unlocked"))
+}
+
+// clientInfo describes the client of a login request for its session.
+func clientInfo(c *fiber.Ctx) entity.ClientInfo {
+	return entity.ClientInfo{IP: c.IP(), UserAgent: c.Get(fiber.HeaderUserAgent)}
+}
 
+// toMfaChallengeResponse answers a login that has to be completed with LoginMfa.
+func toMfaChallengeResponse(challenge entity.MfaChallengeEntity) response.MfaChallengeResponse {
+	challengeResp := response.MfaChallengeResponse{}
+	challengeResp.Meta.Status = true
+	challengeResp.Meta.Message = "Two-factor authentication required"
+	challengeResp.MfaRequired = true
+	challengeResp.MfaToken = challenge.Token
+	challengeResp.MfaExpiredAt = challenge.ExpiresAt.Unix()
+	challengeResp.EnrollmentRequired = challenge.EnrollmentRequired
+
+	return challengeResp
 }
 
 func NewAuthHandler(authService service.AuthService) AuthHandler {
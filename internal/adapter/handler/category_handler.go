@@ -13,8 +13,6 @@ import (
 	"github.com/gofiber/fiber/v2/log"
 )
 
-var defaultSuccessResponse response.DefaultSucessResponse
-
 type CategoryHandler interface {
 	GetCategories(c *fiber.Ctx) error
 	GetCategoryById(c *fiber.Ctx) error
@@ -33,12 +31,9 @@ type categoryHandler struct {
 func (ch *categoryHandler) GetCategoryFE(c *fiber.Ctx) error {
 	results, err := ch.categoryService.GetCategories(c.Context())
 	if err != nil {
-		code = "[HANDLER] GetCategoryFE - 1"
+		code := "[HANDLER] GetCategoryFE - 1"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
 	categoryResponses := []response.SuccessCategoryResponse{}
@@ -52,12 +47,7 @@ func (ch *categoryHandler) GetCategoryFE(c *fiber.Ctx) error {
 		categoryResponses = append(categoryResponses, categoryResponse)
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Data = categoryResponses
-	defaultSuccessResponse.Pagination = nil
-	defaultSuccessResponse.Meta.Message = "Categories fetched successfully"
-
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Categories fetched successfully").WithData(categoryResponses))
 }
 
 // CreateCategory implements CategoryHandler.
@@ -66,31 +56,26 @@ func (ch *categoryHandler) CreateCategory(c *fiber.Ctx) error {
 	claims := c.Locals("user").(*entity.JwtData)
 	userId := claims.UserID
 	if userId == 0 {
-		code = "[HANDLER] CreateCategory - 1"
-		err = errors.New("user not authorized")
+		code := "[HANDLER] CreateCategory - 1"
+		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
 
-	if err = c.BodyParser(&req); err != nil {
-		code = "[HANDLER] CreateCategory - 2"
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] CreateCategory - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	if err = validator.ValidateStruct(req); err != nil {
-		code = "[HANDLER] CreateCategory - 3"
+	if err := validator.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] CreateCategory - 3"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		return err
 	}
 
 	reqEntity := entity.CategoryEntity{
@@ -100,21 +85,14 @@ func (ch *categoryHandler) CreateCategory(c *fiber.Ctx) error {
 		},
 	}
 
-	err = ch.categoryService.CreateCategory(c.Context(), reqEntity)
+	err := ch.categoryService.CreateCategory(c.Context(), reqEntity)
 	if err != nil {
-		code = "[HANDLER] CreateCategory - 4"
+		code := "[HANDLER] CreateCategory - 4"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Category created successfully"
-	defaultSuccessResponse.Data = nil
-	defaultSuccessResponse.Pagination = nil
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Category created successfully"))
 }
 
 // DeleteCategoryById implements CategoryHandler.
@@ -123,11 +101,10 @@ func (ch *categoryHandler) DeleteCategoryById(c *fiber.Ctx) error {
 	claims := c.Locals("user").(*entity.JwtData)
 	userId := claims.UserID
 	if userId == 0 {
-		code = "[HANDLER] DeleteCategoryById - 1"
-		err = errors.New("user not authorized")
+		code := "[HANDLER] DeleteCategoryById - 1"
+		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
@@ -136,10 +113,9 @@ func (ch *categoryHandler) DeleteCategoryById(c *fiber.Ctx) error {
 	idParam := c.Params("categoryID")
 	id, err := conv.StringToInt64(idParam)
 	if err != nil {
-		code = "[HANDLER] DeleteCategoryById - 2"
+		code := "[HANDLER] DeleteCategoryById - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
@@ -147,20 +123,12 @@ func (ch *categoryHandler) DeleteCategoryById(c *fiber.Ctx) error {
 	err = ch.categoryService.DeleteCategoryById(c.Context(), id)
 
 	if err != nil {
-		code = "[HANDLER] DeleteCategoryById - 3"
+		code := "[HANDLER] DeleteCategoryById - 3"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Category deleted successfully"
-	defaultSuccessResponse.Data = nil
-	defaultSuccessResponse.Pagination = nil
-
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Category deleted successfully"))
 
 }
 
@@ -171,21 +139,19 @@ func (ch *categoryHandler) EditCategoryById(c *fiber.Ctx) error {
 	claims := c.Locals("user").(*entity.JwtData)
 	userId := claims.UserID
 	if userId == 0 {
-		code = "[HANDLER] EditCategoryById - 1"
-		err = errors.New("user not authorized")
+		code := "[HANDLER] EditCategoryById - 1"
+		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
 
 	// Body Parser Category Entity
-	if err = c.BodyParser(&req); err != nil {
-		code = "[HANDLER] EditCategoryById - 2"
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] EditCategoryById - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
@@ -194,10 +160,9 @@ func (ch *categoryHandler) EditCategoryById(c *fiber.Ctx) error {
 	idParam := c.Params("categoryID")
 	id, err := conv.StringToInt64(idParam)
 	if err != nil {
-		code = "[HANDLER] EditCategoryById - 3"
+		code := "[HANDLER] EditCategoryById - 3"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
@@ -212,20 +177,12 @@ func (ch *categoryHandler) EditCategoryById(c *fiber.Ctx) error {
 
 	err = ch.categoryService.EditCategoryById(c.Context(), reqEntity)
 	if err != nil {
-		code = "[HANDLER] EditCategoryById - 4"
+		code := "[HANDLER] EditCategoryById - 4"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
-	defaultSuccessResponse.Data = nil
-	defaultSuccessResponse.Pagination = nil
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Category updated successfully"
-
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Category updated successfully"))
 
 }
 
@@ -234,23 +191,19 @@ func (ch *categoryHandler) GetCategories(c *fiber.Ctx) error {
 	claims := c.Locals("user").(*entity.JwtData)
 	userID := claims.UserID
 	if userID == 0 {
-		code = "[HANDLER] GetCategories - 1"
-		err = errors.New("user not authorized")
+		code := "[HANDLER] GetCategories - 1"
+		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
 
 	results, err := ch.categoryService.GetCategories(c.Context())
 	if err != nil {
-		code = "[HANDLER] GetCategories - 2"
+		code := "[HANDLER] GetCategories - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
 	categoryResponses := []response.SuccessCategoryResponse{}
@@ -265,12 +218,7 @@ func (ch *categoryHandler) GetCategories(c *fiber.Ctx) error {
 		categoryResponses = append(categoryResponses, categoryResponse)
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Categories fetched successfully"
-	defaultSuccessResponse.Pagination = nil
-	defaultSuccessResponse.Data = categoryResponses
-
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Categories fetched successfully").WithData(categoryResponses))
 }
 
 // GetCategoryById implements CategoryHandler.
@@ -278,11 +226,10 @@ func (ch *categoryHandler) GetCategoryById(c *fiber.Ctx) error {
 	claims := c.Locals("user").(*entity.JwtData)
 	userID := claims.UserID
 	if userID == 0 {
-		code = "[HANDLER] GetCategoryById - 1"
-		err = errors.New("user not authorized")
+		code := "[HANDLER] GetCategoryById - 1"
+		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
@@ -290,22 +237,18 @@ func (ch *categoryHandler) GetCategoryById(c *fiber.Ctx) error {
 	idParam := c.Params("categoryID")
 	id, err := conv.StringToInt64(idParam)
 	if err != nil {
-		code = "[HANDLER] GetCategoryById - 2"
+		code := "[HANDLER] GetCategoryById - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
 	result, err := ch.categoryService.GetCategoryById(c.Context(), id)
 	if err != nil {
-		code = "[HANDLER] GetCategoryById - 3"
+		code := "[HANDLER] GetCategoryById - 3"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
 	categoryResponse := response.SuccessCategoryResponse{
@@ -315,12 +258,7 @@ func (ch *categoryHandler) GetCategoryById(c *fiber.Ctx) error {
 		CreatedByName: result.User.Name,
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Category fetched successfully"
-	defaultSuccessResponse.Pagination = nil
-	defaultSuccessResponse.Data = categoryResponse
-
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Category fetched successfully").WithData(categoryResponse))
 }
 
 func NewCategoryHandler(categoryService service.CategoryService) CategoryHandler {
@@ -10,7 +10,7 @@ import (
 	"portal-blog/internal/core/service"
 	"portal-blog/lib/conv"
 	"portal-blog/lib/validator"
-	"strings"
+	"slices"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -25,13 +25,27 @@ type ContentHandler interface {
 	DeleteContent(c *fiber.Ctx) error
 	UploadImageR2(c *fiber.Ctx) error
 
+	// Workflow
+	SubmitContent(c *fiber.Ctx) error
+	ApproveContent(c *fiber.Ctx) error
+	RejectContent(c *fiber.Ctx) error
+	PublishContent(c *fiber.Ctx) error
+	ArchiveContent(c *fiber.Ctx) error
+
+	// Revisions
+	GetContentRevisions(c *fiber.Ctx) error
+	GetContentRevisionDiff(c *fiber.Ctx) error
+	RestoreContentRevision(c *fiber.Ctx) error
+
 	// FE
 	GetContentWithQuery(c *fiber.Ctx) error
 	GetContentDetail(c *fiber.Ctx) error
+	GetContentDetailBySlug(c *fiber.Ctx) error
 }
 
 type contentHandler struct {
 	contentService service.ContentService
+	searchService  service.SearchService
 }
 
 // GetContentDetail implements ContentHandler.
@@ -41,54 +55,52 @@ func (ch *contentHandler) GetContentDetail(c *fiber.Ctx) error {
 	if err != nil {
 		code := "[HANDLER] GetContentDetail - 1"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	result, err := ch.contentService.GetContentByID(c.Context(), contentID)
+	result, err := ch.contentService.GetPublishedContentByID(c.Context(), contentID)
 	if err != nil {
 		code := "[HANDLER] GetContentDetail - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
-	respContent := response.ContentResponse{
-		ID:           result.ID,
-		Title:        result.Title,
-		Excerpt:      result.Excerpt,
-		Description:  result.Description,
-		Image:        result.Image,
-		Tags:         result.Tags,
-		Status:       result.Status,
-		CategoryID:   result.CategoryID,
-		CreatedByID:  result.CreatedByID,
-		CreatedAt:    result.CreatedAt.Local().String(),
-		CategoryName: result.Category.Title,
-		Author:       result.User.Name,
+	respContent := toPublicContentResponse(*result)
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(respContent))
+}
+
+// GetContentDetailBySlug implements ContentHandler.
+// A slug the content had before answers with a permanent redirect to its current slug.
+func (ch *contentHandler) GetContentDetailBySlug(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+
+	result, err := ch.contentService.GetPublishedContentBySlug(c.Context(), slug)
+	if err != nil {
+		code := "[HANDLER] GetContentDetailBySlug - 1"
+		log.Errorw(code, err)
+		return err
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Data = respContent
-	defaultSuccessResponse.Meta.Message = "Success"
+	if result.Slug != slug {
+		return c.Redirect("/api/fe/content/slug/"+result.Slug, fiber.StatusMovedPermanently)
+	}
 
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Success").WithData(toPublicContentResponse(*result)))
 }
 
 // GetContentWithQuery implements ContentHandler.
 func (ch *contentHandler) GetContentWithQuery(c *fiber.Ctx) error {
+	var err error
 	// Page
 	page := 1
 	if c.Query("page") != "" {
 		page, err = conv.StringToInt(c.Query("page"))
 		if err != nil {
 			log.Errorw("[HANDLER] GetContentWithQuery - 1", "Error parsing page query", err)
-			errorResp.Meta.Status = false
-			errorResp.Meta.Message = "Invalid page number"
+			errorResp := response.NewErrorResponse("Invalid page number")
 
 			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 		}
@@ -100,8 +112,7 @@ func (ch *contentHandler) GetContentWithQuery(c *fiber.Ctx) error {
 		limit, err = conv.StringToInt(c.Query("limit"))
 		if err != nil {
 			log.Errorw("[HANDLER] GetContentWithQuery - 2", "Error parsing limit query", err)
-			errorResp.Meta.Status = false
-			errorResp.Meta.Message = "Invalid limit number"
+			errorResp := response.NewErrorResponse("Invalid limit number")
 
 			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 		}
@@ -109,6 +120,9 @@ func (ch *contentHandler) GetContentWithQuery(c *fiber.Ctx) error {
 
 	// OrderBy
 	orderBy := "created_at"
+	if c.Query("search") != "" {
+		orderBy = entity.OrderByRelevance
+	}
 	if c.Query("orderBy") != "" {
 		orderBy = c.Query("orderBy")
 	}
@@ -131,8 +145,7 @@ func (ch *contentHandler) GetContentWithQuery(c *fiber.Ctx) error {
 		categoryID, err = conv.StringToInt64(c.Query("categoryID"))
 		if err != nil {
 			log.Errorw("[HANDLER] GetContentWithQuery - 3", "Error parsing categoryID query", err)
-			errorResp.Meta.Status = false
-			errorResp.Meta.Message = "Invalid categoryID"
+			errorResp := response.NewErrorResponse("Invalid categoryID")
 
 			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 		}
@@ -144,8 +157,10 @@ func (ch *contentHandler) GetContentWithQuery(c *fiber.Ctx) error {
 		OrderBy:    orderBy,
 		OrderType:  orderType,
 		Search:     search,
-		Status:     "PUBLISH",
 		CategoryID: categoryID,
+		Tag:        c.Query("tag"),
+		Live:       true,
+		Highlight:  true,
 	}
 
 	results, totalData, totalPages, err := ch.contentService.GetContents(c.Context(), queryEntity)
@@ -153,45 +168,35 @@ func (ch *contentHandler) GetContentWithQuery(c *fiber.Ctx) error {
 	if err != nil {
 		code := "[HANDLER] GetContentWithQuery - 3"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Success"
-
 	respContents := []response.ContentResponse{}
 
 	for _, content := range results {
-		respContent := response.ContentResponse{
-			ID:           content.ID,
-			Title:        content.Title,
-			Excerpt:      content.Excerpt,
-			Description:  content.Description,
-			Image:        content.Image,
-			Tags:         content.Tags,
-			Status:       content.Status,
-			CategoryID:   content.CategoryID,
-			CreatedByID:  content.CreatedByID,
-			CreatedAt:    content.CreatedAt.Local().String(),
-			CategoryName: content.Category.Title,
-			Author:       content.User.Name,
-		}
-
-		respContents = append(respContents, respContent)
+		respContents = append(respContents, toPublicContentResponse(content))
 	}
 
-	defaultSuccessResponse.Data = respContents
-	defaultSuccessResponse.Pagination = &response.PaginationResponse{
+	pagination := &response.PaginationResponse{
 		TotalRecords: int(totalData),
 		Page:         page,
 		PerPage:      limit,
 		TotalPages:   int(totalPages),
 	}
 
-	return c.JSON(defaultSuccessResponse)
+	resp := response.NewSuccessResponse("Success").WithData(respContents).WithPagination(pagination)
+
+	// A search without results suggests a spelling that has some; failing to is not an error.
+	if search != "" && totalData == 0 {
+		didYouMean, err := ch.searchService.DidYouMean(c.Context(), search)
+		if err != nil {
+			code := "[HANDLER] GetContentWithQuery - 4"
+			log.Errorw(code, err)
+		}
+		resp = resp.WithDidYouMean(didYouMean)
+	}
+
+	return c.JSON(resp)
 }
 
 // CreateContent implements ContentHandler.
@@ -201,8 +206,7 @@ func (ch *contentHandler) CreateContent(c *fiber.Ctx) error {
 		code := "[HANDLER] CreateContent - 1"
 		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
@@ -210,52 +214,44 @@ func (ch *contentHandler) CreateContent(c *fiber.Ctx) error {
 	userID := claims.UserID
 
 	var req request.ContentRequest
-	if err = c.BodyParser(&req); err != nil {
+	if err := c.BodyParser(&req); err != nil {
 		code := "[HANDLER] CreateContent - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	if err = validator.ValidateStruct(&req); err != nil {
+	if err := validator.ValidateStruct(&req, acceptLanguage(c)); err != nil {
 		code := "[HANDLER] CreateContent - 3"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		return err
 	}
 
-	tags := strings.Split(req.Tags, ",")
+	tags := conv.SplitList(req.Tags)
 
 	reqEntity := entity.ContentEntity{
 		Title:       req.Title,
+		Slug:        req.Slug,
 		Excerpt:     req.Excerpt,
 		Description: req.Description,
 		Image:       req.Image,
 		Tags:        tags,
 		CategoryID:  req.CategoryID,
 		Status:      req.Status,
+		PublishAt:   req.PublishAt,
+		UnpublishAt: req.UnpublishAt,
 		CreatedByID: int64(userID),
 	}
 
-	err = ch.contentService.CreateContent(c.Context(), reqEntity)
+	err := ch.contentService.CreateContent(c.Context(), reqEntity)
 	if err != nil {
 		code := "[HANDLER] CreateContent - 4"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Content created successfully"
-	defaultSuccessResponse.Data = nil
-
-	return c.Status(fiber.StatusCreated).JSON(defaultSuccessResponse)
+	return c.Status(fiber.StatusCreated).JSON(response.NewSuccessResponse("Content created successfully"))
 }
 
 // DeleteContent implements ContentHandler.
@@ -265,8 +261,7 @@ func (ch *contentHandler) DeleteContent(c *fiber.Ctx) error {
 		code := "[HANDLER] GetContentByID - 1"
 		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
@@ -276,26 +271,19 @@ func (ch *contentHandler) DeleteContent(c *fiber.Ctx) error {
 	if err != nil {
 		code := "[HANDLER] GetContentByID - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	err = ch.contentService.DeleteContent(c.Context(), contentID)
+	err = ch.contentService.DeleteContent(c.Context(), contentID, claims)
 	if err != nil {
 		code := "[HANDLER] DeleteContent - 1"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Content Deleted Successfully"
-
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Content Deleted Successfully"))
 }
 
 // GetContentByID implements ContentHandler.
@@ -305,8 +293,7 @@ func (ch *contentHandler) GetContentByID(c *fiber.Ctx) error {
 		code := "[HANDLER] GetContentByID - 1"
 		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
@@ -316,8 +303,7 @@ func (ch *contentHandler) GetContentByID(c *fiber.Ctx) error {
 	if err != nil {
 		code := "[HANDLER] GetContentByID - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
@@ -326,44 +312,22 @@ func (ch *contentHandler) GetContentByID(c *fiber.Ctx) error {
 	if err != nil {
 		code := "[HANDLER] GetContentByID - 3"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
-	respContent := response.ContentResponse{
-		ID:           result.ID,
-		Title:        result.Title,
-		Excerpt:      result.Excerpt,
-		Description:  result.Description,
-		Image:        result.Image,
-		Tags:         result.Tags,
-		Status:       result.Status,
-		CategoryID:   result.CategoryID,
-		CreatedByID:  result.CreatedByID,
-		CreatedAt:    result.CreatedAt.Local().String(),
-		CategoryName: result.Category.Title,
-		Author:       result.User.Name,
-	}
-
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Data = respContent
-	defaultSuccessResponse.Meta.Message = "Success"
-
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Success").WithData(toContentResponse(*result)))
 
 }
 
 // GetContents implements ContentHandler.
 func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
+	var err error
 	claims := c.Locals("user").(*entity.JwtData)
 	if claims.UserID == 0 {
 		code := "[HANDLER] GetContents - 1"
 		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
@@ -374,8 +338,7 @@ func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
 		page, err = conv.StringToInt(c.Query("page"))
 		if err != nil {
 			log.Errorw("[HANDLER] GetContents - 2", "Error parsing page query", err)
-			errorResp.Meta.Status = false
-			errorResp.Meta.Message = "Invalid page number"
+			errorResp := response.NewErrorResponse("Invalid page number")
 
 			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 		}
@@ -387,8 +350,7 @@ func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
 		limit, err = conv.StringToInt(c.Query("limit"))
 		if err != nil {
 			log.Errorw("[HANDLER] GetContents - 3", "Error parsing limit query", err)
-			errorResp.Meta.Status = false
-			errorResp.Meta.Message = "Invalid limit number"
+			errorResp := response.NewErrorResponse("Invalid limit number")
 
 			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 		}
@@ -396,6 +358,9 @@ func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
 
 	// OrderBy
 	orderBy := "created_at"
+	if c.Query("search") != "" {
+		orderBy = entity.OrderByRelevance
+	}
 	if c.Query("orderBy") != "" {
 		orderBy = c.Query("orderBy")
 	}
@@ -418,13 +383,21 @@ func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
 		categoryID, err = conv.StringToInt64(c.Query("categoryID"))
 		if err != nil {
 			log.Errorw("[HANDLER] GetContents - 4", "Error parsing categoryID query", err)
-			errorResp.Meta.Status = false
-			errorResp.Meta.Message = "Invalid categoryID"
+			errorResp := response.NewErrorResponse("Invalid categoryID")
 
 			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 		}
 	}
 
+	// Status
+	status := c.Query("status")
+	if status != "" && !slices.Contains(entity.ContentStatuses, status) {
+		log.Errorw("[HANDLER] GetContents - 6", "Invalid status query", status)
+		errorResp := response.NewErrorResponse("Invalid status")
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
 	queryEntity := entity.QueryString{
 		Limit:      limit,
 		Page:       page,
@@ -432,6 +405,8 @@ func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
 		OrderType:  orderType,
 		Search:     search,
 		CategoryID: categoryID,
+		Status:     status,
+		Tag:        c.Query("tag"),
 	}
 
 	results, _, _, err := ch.contentService.GetContents(c.Context(), queryEntity)
@@ -439,38 +414,16 @@ func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
 	if err != nil {
 		code := "[HANDLER] GetContents - 5"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Successfully"
-
 	respContents := []response.ContentResponse{}
 
 	for _, content := range results {
-		respContent := response.ContentResponse{
-			ID:           content.ID,
-			Title:        content.Title,
-			Excerpt:      content.Excerpt,
-			Description:  content.Description,
-			Image:        content.Image,
-			Tags:         content.Tags,
-			Status:       content.Status,
-			CategoryID:   content.CategoryID,
-			CreatedByID:  content.CreatedByID,
-			CreatedAt:    content.CreatedAt.Local().String(),
-			CategoryName: content.Category.Title,
-			Author:       content.User.Name,
-		}
-
-		respContents = append(respContents, respContent)
+		respContents = append(respContents, toContentResponse(content))
 	}
 
-	defaultSuccessResponse.Data = respContents
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Successfully").WithData(respContents))
 }
 
 // UpdateContent implements ContentHandler.
@@ -480,29 +433,24 @@ func (ch *contentHandler) UpdateContent(c *fiber.Ctx) error {
 		code := "[HANDLER] UpdateContent - 1"
 		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
 
-	var req request.ContentRequest
-	if err = c.BodyParser(&req); err != nil {
+	var req request.UpdateContentRequest
+	if err := c.BodyParser(&req); err != nil {
 		code := "[HANDLER] UpdateContent - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	if err = validator.ValidateStruct(&req); err != nil {
+	if err := validator.ValidateStruct(&req, acceptLanguage(c)); err != nil {
 		code := "[HANDLER] UpdateContent - 3"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		return err
 	}
 
 	idParam := c.Params("contentID")
@@ -510,39 +458,33 @@ func (ch *contentHandler) UpdateContent(c *fiber.Ctx) error {
 	if err != nil {
 		code := "[HANDLER] UpdateContent - 4"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	tags := strings.Split(req.Tags, ",")
+	tags := conv.SplitList(req.Tags)
 	reqEntity := entity.ContentEntity{
 		ID:          contentID,
 		Title:       req.Title,
+		Slug:        req.Slug,
 		Excerpt:     req.Excerpt,
 		Description: req.Description,
 		Image:       req.Image,
 		Tags:        tags,
-		Status:      req.Status,
+		PublishAt:   req.PublishAt,
+		UnpublishAt: req.UnpublishAt,
 		CategoryID:  req.CategoryID,
 	}
 
-	err = ch.contentService.UpdateContent(c.Context(), reqEntity)
+	err = ch.contentService.UpdateContent(c.Context(), reqEntity, claims)
 	if err != nil {
 		code := "[HANDLER] UpdateContent - 5"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Success"
-	defaultSuccessResponse.Data = nil
-
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Success"))
 }
 
 // UploadImageR2 implements ContentHandler.
@@ -552,8 +494,7 @@ func (ch *contentHandler) UploadImageR2(c *fiber.Ctx) error {
 		code := "[HANDLER] UploadImageR2 - 1"
 		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
@@ -563,8 +504,7 @@ func (ch *contentHandler) UploadImageR2(c *fiber.Ctx) error {
 	if err != nil {
 		code := "[HANDLER] UploadImageR2 - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
@@ -572,10 +512,7 @@ func (ch *contentHandler) UploadImageR2(c *fiber.Ctx) error {
 	if err := c.SaveFile(file, fmt.Sprintf("./temp/content/%s", file.Filename)); err != nil {
 		code := "[HANDLER] UploadImageR2 - 3"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
 	req.Image = fmt.Sprintf("./temp/content/%s", file.Filename)
@@ -588,10 +525,7 @@ func (ch *contentHandler) UploadImageR2(c *fiber.Ctx) error {
 	if err != nil {
 		code := "[HANDLER] UploadImageR2 - 4"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
 	if req.Image != "" {
@@ -599,10 +533,7 @@ func (ch *contentHandler) UploadImageR2(c *fiber.Ctx) error {
 		if err != nil {
 			code := "[HANDLER] UploadImageR2 - 5"
 			log.Errorw(code, err)
-			errorResp.Meta.Status = false
-			errorResp.Meta.Message = err.Error()
-
-			return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+			return err
 		}
 	}
 
@@ -610,16 +541,285 @@ func (ch *contentHandler) UploadImageR2(c *fiber.Ctx) error {
 		"urlImage": imageUrl,
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Upload Success"
-	defaultSuccessResponse.Data = urlImageResp
+	return c.Status(fiber.StatusCreated).JSON(response.NewSuccessResponse("Upload Success").WithData(urlImageResp))
+
+}
+
+// SubmitContent implements ContentHandler.
+// It sends a draft to review.
+func (ch *contentHandler) SubmitContent(c *fiber.Ctx) error {
+	return ch.transitionContent(c, entity.ContentTransitionSubmit, "", "Content submitted for review")
+}
+
+// ApproveContent implements ContentHandler.
+// It accepts a content in review and schedules it for publishing.
+func (ch *contentHandler) ApproveContent(c *fiber.Ctx) error {
+	return ch.transitionContent(c, entity.ContentTransitionApprove, "", "Content approved")
+}
+
+// RejectContent implements ContentHandler.
+// It sends a content in review back to draft with the reason from the request body.
+func (ch *contentHandler) RejectContent(c *fiber.Ctx) error {
+	var req request.RejectContentRequest
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] RejectContent - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := validator.ValidateStruct(&req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] RejectContent - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return ch.transitionContent(c, entity.ContentTransitionReject, req.Reason, "Content rejected")
+}
+
+// PublishContent implements ContentHandler.
+func (ch *contentHandler) PublishContent(c *fiber.Ctx) error {
+	return ch.transitionContent(c, entity.ContentTransitionPublish, "", "Content published")
+}
+
+// ArchiveContent implements ContentHandler.
+func (ch *contentHandler) ArchiveContent(c *fiber.Ctx) error {
+	return ch.transitionContent(c, entity.ContentTransitionArchive, "", "Content archived")
+}
+
+// transitionContent takes a workflow step for the content in the contentID path parameter and
+// answers with the content in its new status.
+func (ch *contentHandler) transitionContent(c *fiber.Ctx, transition, reason, message string) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] transitionContent - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
-	return c.Status(fiber.StatusCreated).JSON(defaultSuccessResponse)
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] transitionContent - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := ch.contentService.TransitionContent(c.Context(), contentID, transition, reason, claims)
+	if err != nil {
+		code := "[HANDLER] transitionContent - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse(message).WithData(toContentResponse(*result)))
+}
+
+// GetContentRevisions implements ContentHandler.
+func (ch *contentHandler) GetContentRevisions(c *fiber.Ctx) error {
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] GetContentRevisions - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	results, err := ch.contentService.GetContentRevisions(c.Context(), contentID)
+	if err != nil {
+		code := "[HANDLER] GetContentRevisions - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	revisionResponses := []response.ContentRevisionResponse{}
+	for _, result := range results {
+		revisionResponses = append(revisionResponses, toContentRevisionResponse(result))
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(revisionResponses))
+}
+
+// GetContentRevisionDiff implements ContentHandler.
+// The from and to query parameters pick the revisions; by default the latest revision is
+// compared with the one before it.
+func (ch *contentHandler) GetContentRevisionDiff(c *fiber.Ctx) error {
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] GetContentRevisionDiff - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var from, to int
+	if c.Query("from") != "" {
+		from, err = conv.StringToInt(c.Query("from"))
+		if err != nil || from < 1 {
+			log.Errorw("[HANDLER] GetContentRevisionDiff - 2", "Error parsing from query", err)
+			errorResp := response.NewErrorResponse("Invalid from revision")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	if c.Query("to") != "" {
+		to, err = conv.StringToInt(c.Query("to"))
+		if err != nil || to < 1 {
+			log.Errorw("[HANDLER] GetContentRevisionDiff - 3", "Error parsing to query", err)
+			errorResp := response.NewErrorResponse("Invalid to revision")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	result, err := ch.contentService.DiffContentRevisions(c.Context(), contentID, from, to)
+	if err != nil {
+		code := "[HANDLER] GetContentRevisionDiff - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	changes := []response.ContentFieldChangeResponse{}
+	for _, change := range result.Changes {
+		changes = append(changes, response.ContentFieldChangeResponse{
+			Field: change.Field,
+			From:  change.From,
+			To:    change.To,
+		})
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(response.ContentRevisionDiffResponse{
+		ContentID:     result.ContentID,
+		From:          result.From.Revision,
+		To:            result.To.Revision,
+		FromCreatedAt: result.From.CreatedAt.Local().Format(time.RFC3339),
+		ToCreatedAt:   result.To.CreatedAt.Local().Format(time.RFC3339),
+		Changes:       changes,
+	}))
+}
+
+// RestoreContentRevision implements ContentHandler.
+func (ch *contentHandler) RestoreContentRevision(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] RestoreContentRevision - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] RestoreContentRevision - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	revision, err := conv.StringToInt(c.Params("revision"))
+	if err != nil {
+		code := "[HANDLER] RestoreContentRevision - 3"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := ch.contentService.RestoreContentRevision(c.Context(), contentID, revision, claims)
+	if err != nil {
+		code := "[HANDLER] RestoreContentRevision - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Revision restored").WithData(toContentRevisionResponse(*result)))
+}
+
+// toPublicContentResponse leaves out what only editors see, such as the rejection reason.
+func toPublicContentResponse(result entity.ContentEntity) response.ContentResponse {
+	respContent := response.ContentResponse{
+		ID:           result.ID,
+		Title:        result.Title,
+		Slug:         result.Slug,
+		Excerpt:      result.Excerpt,
+		Description:  result.Description,
+		Image:        result.Image,
+		Tags:         result.Tags,
+		Status:       result.Status,
+		CategoryID:   result.CategoryID,
+		CreatedByID:  result.CreatedByID,
+		CreatedAt:    result.CreatedAt.Local().String(),
+		CategoryName: result.Category.Title,
+		Author:       result.User.Name,
+		Headline:     result.Headline,
+	}
+
+	if result.PublishAt != nil {
+		respContent.PublishAt = result.PublishAt.Local().Format(time.RFC3339)
+	}
+
+	return respContent
+}
+
+func toContentRevisionResponse(revision entity.ContentRevisionEntity) response.ContentRevisionResponse {
+	return response.ContentRevisionResponse{
+		Revision:     revision.Revision,
+		Title:        revision.Title,
+		Excerpt:      revision.Excerpt,
+		Description:  revision.Description,
+		Image:        revision.Image,
+		Tags:         revision.Tags,
+		CategoryID:   revision.CategoryID,
+		AuthorID:     revision.AuthorID,
+		Author:       revision.AuthorName,
+		RestoredFrom: revision.RestoredFrom,
+		CreatedAt:    revision.CreatedAt.Local().Format(time.RFC3339),
+	}
+}
+
+func toContentResponse(content entity.ContentEntity) response.ContentResponse {
+	resp := response.ContentResponse{
+		ID:              content.ID,
+		Title:           content.Title,
+		Slug:            content.Slug,
+		Excerpt:         content.Excerpt,
+		Description:     content.Description,
+		Image:           content.Image,
+		Tags:            content.Tags,
+		Status:          content.Status,
+		RejectionReason: content.RejectionReason,
+		CategoryID:      content.CategoryID,
+		CreatedByID:     content.CreatedByID,
+		CreatedAt:       content.CreatedAt.Local().String(),
+		CategoryName:    content.Category.Title,
+		Author:          content.User.Name,
+	}
+
+	if content.PublishAt != nil {
+		resp.PublishAt = content.PublishAt.Local().Format(time.RFC3339)
+	}
+
+	if content.UnpublishAt != nil {
+		resp.UnpublishAt = content.UnpublishAt.Local().Format(time.RFC3339)
+	}
 
+	return resp
 }
 
-func NewContentHandler(contentService service.ContentService) ContentHandler {
+func NewContentHandler(contentService service.ContentService, searchService service.SearchService) ContentHandler {
 	return &contentHandler{
 		contentService: contentService,
+		searchService:  searchService,
 	}
 }
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+func TestContentListsRejectUnknownOrder(t *testing.T) {
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	app := newRaceTestApp()
+
+	tests := []struct {
+		name  string
+		path  string
+		query url.Values
+		want  int
+	}{
+		{"admin injected orderBy", "/admin/content", url.Values{"orderBy": {"(SELECT pg_sleep(5))"}}, fiber.StatusUnprocessableEntity},
+		{"admin injected orderType", "/admin/content", url.Values{"orderType": {"desc; DROP TABLE contents"}}, fiber.StatusUnprocessableEntity},
+		{"public injected orderBy", "/fe/content", url.Values{"orderBy": {"created_at desc, (CASE WHEN 1=1 THEN title END)"}}, fiber.StatusUnprocessableEntity},
+		{"public unknown column", "/fe/content", url.Values{"orderBy": {"password"}}, fiber.StatusUnprocessableEntity},
+		{"admin known column", "/admin/content", url.Values{"orderBy": {"title"}, "orderType": {"ASC"}}, fiber.StatusOK},
+		{"public relevance", "/fe/content", url.Values{"search": {"go"}, "orderBy": {"relevance"}}, fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.path+"?"+tt.query.Encode(), nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status %d, want %d", resp.StatusCode, tt.want)
+			}
+
+			if tt.want != fiber.StatusUnprocessableEntity {
+				return
+			}
+
+			var body struct {
+				Code string `json:"code"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Code != "invalid_content_order" {
+				t.Fatalf("code %q, want invalid_content_order", body.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/domain/apperror"
+	valid "portal-blog/lib/validator"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// errorKindStatus maps the kinds of domain errors to HTTP status codes.
+var errorKindStatus = map[apperror.Kind]int{
+	apperror.KindNotFound:     fiber.StatusNotFound,
+	apperror.KindConflict:     fiber.StatusConflict,
+	apperror.KindValidation:   fiber.StatusUnprocessableEntity,
+	apperror.KindUnauthorized: fiber.StatusUnauthorized,
+	apperror.KindForbidden:    fiber.StatusForbidden,
+}
+
+// ErrorHandler answers errors returned by handlers with an error envelope.
+//
+// Domain errors keep their message and code and get the status of their kind. Validation
+// errors are answered with a 422 listing the failed fields. Fiber errors,
+// such as unknown routes, keep their status. Anything else is answered with a 500 whose
+// message does not expose the cause.
+//
+// Input:
+//   - c: *fiber.Ctx - The request context.
+//   - err: error - The error returned by the handler.
+//
+// Output:
+//   - error: Returns an error only if the response cannot be written.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	if appErr, ok := apperror.As(err); ok {
+		status, ok := errorKindStatus[appErr.Kind]
+		if !ok {
+			status = fiber.StatusInternalServerError
+		}
+		errorResp := response.NewErrorResponse(appErr.Message).WithCode(appErr.Code)
+
+		return c.Status(status).JSON(errorResp)
+	}
+
+	var validationErrs valid.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		errorResp := response.NewErrorResponse("Validation failed").WithCode("validation_failed").WithErrors(validationErrs)
+
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(errorResp)
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		errorResp := response.NewErrorResponse(fiberErr.Message).WithCode(statusCode(fiberErr.Code))
+
+		return c.Status(fiberErr.Code).JSON(errorResp)
+	}
+
+	code := "[HANDLER] ErrorHandler - 1"
+	log.Errorw(code, err)
+	errorResp := response.NewErrorResponse("Internal server error").WithCode(statusCode(fiber.StatusInternalServerError))
+
+	return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+}
+
+// statusCode returns the snake case status text of an HTTP status, e.g. "not_found" for 404.
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(utils.StatusMessage(status)), " ", "_")
+}
+
+// acceptLanguage picks the language of validation messages from the Accept-Language header.
+func acceptLanguage(c *fiber.Ctx) string {
+	return c.AcceptsLanguages(valid.Languages...)
+}
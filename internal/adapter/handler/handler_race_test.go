@@ -0,0 +1,350 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/domain/apperror"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/service"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Run with -race. The handlers, services and the envelopes they answer with must not share
+// state between requests; every response below is checked against the request that made it.
+
+// raceMissingID and every ID above it are unknown to the fake repositories.
+const raceMissingID = 1_000_000
+
+type raceContentRepository struct{}
+
+func (raceContentRepository) GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, int64, error) {
+	contents := []entity.ContentEntity{{ID: int64(query.Page), Title: query.Search, Status: query.Status}}
+	return contents, int64(query.Page * query.Limit), int64(query.Page), nil
+}
+
+func (raceContentRepository) GetContentByID(ctx context.Context, id int64) (*entity.ContentEntity, error) {
+	if id >= raceMissingID {
+		return nil, apperror.NotFound("content_not_found", fmt.Sprintf("content %d not found", id))
+	}
+
+	return &entity.ContentEntity{ID: id, Title: fmt.Sprintf("content-%d", id), CreatedByID: 1}, nil
+}
+
+func (raceContentRepository) GetContentBySlug(ctx context.Context, slug string) (*entity.ContentEntity, error) {
+	return nil, apperror.NotFound("content_not_found", fmt.Sprintf("content %s not found", slug))
+}
+
+func (raceContentRepository) GetContentIDBySlugRedirect(ctx context.Context, slug string) (int64, error) {
+	return 0, apperror.NotFound("content_not_found", fmt.Sprintf("content %s not found", slug))
+}
+
+func (raceContentRepository) GetTakenContentSlugs(ctx context.Context, base string, contentID int64) ([]string, error) {
+	return nil, nil
+}
+
+func (raceContentRepository) SyncSearchLanguage(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (raceContentRepository) CreateContent(ctx context.Context, req entity.ContentEntity) error {
+	return nil
+}
+
+func (raceContentRepository) UpdateContent(ctx context.Context, req entity.ContentEntity, editableStatuses []string) error {
+	return nil
+}
+
+func (raceContentRepository) DeleteContent(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (raceContentRepository) UpdateContentStatus(ctx context.Context, id int64, from, to, rejectionReason string) error {
+	return nil
+}
+
+func (raceContentRepository) GetContentsDueToPublish(ctx context.Context, now time.Time) ([]entity.ContentEntity, error) {
+	return nil, nil
+}
+
+func (raceContentRepository) GetContentsDueToUnpublish(ctx context.Context, now time.Time) ([]entity.ContentEntity, error) {
+	return nil, nil
+}
+
+func (raceContentRepository) GetContentRevisions(ctx context.Context, contentID int64) ([]entity.ContentRevisionEntity, error) {
+	return nil, nil
+}
+
+func (raceContentRepository) GetContentRevision(ctx context.Context, contentID int64, revision int) (*entity.ContentRevisionEntity, error) {
+	return nil, apperror.NotFound("content_revision_not_found", "content revision not found")
+}
+
+func (raceContentRepository) RestoreContentRevision(ctx context.Context, contentID int64, revision int) (*entity.ContentRevisionEntity, error) {
+	return nil, apperror.NotFound("content_revision_not_found", "content revision not found")
+}
+
+type raceCategoryRepository struct{}
+
+func (raceCategoryRepository) GetCategories(ctx context.Context) ([]entity.CategoryEntity, error) {
+	return []entity.CategoryEntity{{ID: 1, Title: "category-1"}}, nil
+}
+
+func (raceCategoryRepository) GetCategoryById(ctx context.Context, id int64) (*entity.CategoryEntity, error) {
+	if id >= raceMissingID {
+		return nil, apperror.NotFound("category_not_found", fmt.Sprintf("category %d not found", id))
+	}
+
+	return &entity.CategoryEntity{ID: id, Title: fmt.Sprintf("category-%d", id)}, nil
+}
+
+func (raceCategoryRepository) CreateCategory(ctx context.Context, req entity.CategoryEntity) error {
+	if strings.HasPrefix(req.Title, "fail") {
+		return apperror.Conflict("duplicate_record", fmt.Sprintf("cannot create %s", req.Title))
+	}
+
+	return nil
+}
+
+func (raceCategoryRepository) EditCategoryById(ctx context.Context, req entity.CategoryEntity) error {
+	return nil
+}
+
+func (raceCategoryRepository) DeleteCategoryById(ctx context.Context, id int64) error {
+	return nil
+}
+
+type raceSearchRepository struct{}
+
+func (raceSearchRepository) SuggestContents(ctx context.Context, q string, limit int) ([]entity.ContentEntity, error) {
+	return nil, nil
+}
+
+func (raceSearchRepository) SuggestCategories(ctx context.Context, q string, limit int) ([]entity.CategoryEntity, error) {
+	return nil, nil
+}
+
+func (raceSearchRepository) SuggestTags(ctx context.Context, q string, limit int) ([]string, error) {
+	return nil, nil
+}
+
+func (raceSearchRepository) GetClosestSearchTerm(ctx context.Context, word string) (string, error) {
+	return word, nil
+}
+
+func (raceSearchRepository) RefreshSearchTerms(ctx context.Context) error {
+	return nil
+}
+
+// raceEnvelope decodes both envelopes: success responses nest status and message under meta,
+// error responses carry them at the top level.
+type raceEnvelope struct {
+	Meta       response.Meta                `json:"meta"`
+	Message    string                       `json:"message"`
+	Data       json.RawMessage              `json:"data"`
+	Pagination *response.PaginationResponse `json:"pagination"`
+}
+
+func (e raceEnvelope) message() string {
+	if e.Meta.Message != "" {
+		return e.Meta.Message
+	}
+
+	return e.Message
+}
+
+func newRaceTestApp() *fiber.App {
+	entity.SetRolePermissions(map[string][]string{
+		entity.RoleAdmin: {entity.PermissionCategoryRead, entity.PermissionCategoryWrite, entity.PermissionContentRead, entity.PermissionContentManageAll},
+	})
+
+	contentHandler := NewContentHandler(service.NewContentService(raceContentRepository{}, nil, nil, nil), service.NewSearchService(raceSearchRepository{}))
+	categoryHandler := NewCategoryHandler(service.NewCategoryService(raceCategoryRepository{}))
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+
+	admin := app.Group("/admin", func(c *fiber.Ctx) error {
+		c.Locals("user", &entity.JwtData{UserID: 1, Role: entity.RoleAdmin})
+		return c.Next()
+	})
+	admin.Get("/content", contentHandler.GetContents)
+	admin.Get("/content/:contentID", contentHandler.GetContentByID)
+	admin.Get("/category", categoryHandler.GetCategories)
+	admin.Get("/category/:categoryID", categoryHandler.GetCategoryById)
+	admin.Post("/category", categoryHandler.CreateCategory)
+
+	app.Get("/fe/content", contentHandler.GetContentWithQuery)
+
+	return app
+}
+
+func TestConcurrentRequestsDoNotShareResponses(t *testing.T) {
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	app := newRaceTestApp()
+
+	type check func(status int, body raceEnvelope) error
+
+	expectData := func(wantStatus int, wantMessage string, wantPagination bool, want func(json.RawMessage) error) check {
+		return func(status int, body raceEnvelope) error {
+			if status != wantStatus {
+				return fmt.Errorf("status %d, want %d", status, wantStatus)
+			}
+			if body.message() != wantMessage {
+				return fmt.Errorf("message %q, want %q", body.message(), wantMessage)
+			}
+			if (body.Pagination != nil) != wantPagination {
+				return fmt.Errorf("pagination %+v, want present=%v", body.Pagination, wantPagination)
+			}
+			if want != nil {
+				return want(body.Data)
+			}
+			if len(body.Data) != 0 {
+				return fmt.Errorf("unexpected data %s", body.Data)
+			}
+			return nil
+		}
+	}
+
+	contentTitled := func(title string) func(json.RawMessage) error {
+		return func(data json.RawMessage) error {
+			var contents []response.ContentResponse
+			if err := json.Unmarshal(data, &contents); err != nil {
+				return err
+			}
+			if len(contents) != 1 || contents[0].Title != title {
+				return fmt.Errorf("contents %+v, want one titled %q", contents, title)
+			}
+			return nil
+		}
+	}
+
+	const workers = 16
+	const iterations = 50
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*iterations)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			for i := 0; i < iterations; i++ {
+				n := w*iterations + i + 1
+				failing := (n/6)%2 == 1
+				var method, target, body string
+				var verify check
+
+				switch n % 6 {
+				case 0:
+					method, target = "GET", fmt.Sprintf("/fe/content?page=%d&search=fe-%d", n, n)
+					verify = func(status int, env raceEnvelope) error {
+						if err := expectData(fiber.StatusOK, "Success", true, contentTitled(fmt.Sprintf("fe-%d", n)))(status, env); err != nil {
+							return err
+						}
+						if env.Pagination.Page != n || env.Pagination.TotalPages != n {
+							return fmt.Errorf("pagination %+v, want page %d", env.Pagination, n)
+						}
+						return nil
+					}
+				case 1:
+					method, target = "GET", fmt.Sprintf("/admin/content?search=admin-%d", n)
+					verify = expectData(fiber.StatusOK, "Successfully", false, contentTitled(fmt.Sprintf("admin-%d", n)))
+				case 2:
+					method, target = "GET", fmt.Sprintf("/admin/content/%d", n)
+					if failing {
+						target = fmt.Sprintf("/admin/content/%d", raceMissingID+n)
+						verify = expectData(fiber.StatusNotFound, fmt.Sprintf("content %d not found", raceMissingID+n), false, nil)
+						break
+					}
+					verify = expectData(fiber.StatusOK, "Success", false, func(data json.RawMessage) error {
+						var content response.ContentResponse
+						if err := json.Unmarshal(data, &content); err != nil {
+							return err
+						}
+						if content.ID != int64(n) || content.Title != fmt.Sprintf("content-%d", n) {
+							return fmt.Errorf("content %+v, want id %d", content, n)
+						}
+						return nil
+					})
+				case 3:
+					method, target = "GET", fmt.Sprintf("/admin/category/%d", n)
+					if failing {
+						target = fmt.Sprintf("/admin/category/%d", raceMissingID+n)
+						verify = expectData(fiber.StatusNotFound, fmt.Sprintf("category %d not found", raceMissingID+n), false, nil)
+						break
+					}
+					verify = expectData(fiber.StatusOK, "Category fetched successfully", false, func(data json.RawMessage) error {
+						var category response.SuccessCategoryResponse
+						if err := json.Unmarshal(data, &category); err != nil {
+							return err
+						}
+						if category.ID != int64(n) || category.Title != fmt.Sprintf("category-%d", n) {
+							return fmt.Errorf("category %+v, want id %d", category, n)
+						}
+						return nil
+					})
+				case 4:
+					method, target = "GET", "/admin/category"
+					verify = expectData(fiber.StatusOK, "Categories fetched successfully", false, func(data json.RawMessage) error {
+						var categories []response.SuccessCategoryResponse
+						if err := json.Unmarshal(data, &categories); err != nil {
+							return err
+						}
+						if len(categories) != 1 || categories[0].Title != "category-1" {
+							return fmt.Errorf("categories %+v", categories)
+						}
+						return nil
+					})
+				case 5:
+					title := fmt.Sprintf("category-%d", n)
+					verify = expectData(fiber.StatusOK, "Category created successfully", false, nil)
+					if failing {
+						title = fmt.Sprintf("fail-%d", n)
+						verify = expectData(fiber.StatusConflict, "cannot create "+title, false, nil)
+					}
+					method, target, body = "POST", "/admin/category", fmt.Sprintf(`{"title":%q}`, title)
+				}
+
+				req := httptest.NewRequest(method, target, strings.NewReader(body))
+				req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+				resp, err := app.Test(req, -1)
+				if err != nil {
+					errs <- fmt.Errorf("%s %s: %w", method, target, err)
+					continue
+				}
+
+				var env raceEnvelope
+				err = json.NewDecoder(resp.Body).Decode(&env)
+				resp.Body.Close()
+				if err != nil {
+					errs <- fmt.Errorf("%s %s: decode: %w", method, target, err)
+					continue
+				}
+
+				if err := verify(resp.StatusCode, env); err != nil {
+					errs <- fmt.Errorf("%s %s: %w", method, target, err)
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"errors"
+	"portal-blog/internal/adapter/handler/request"
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/service"
+	"portal-blog/lib/conv"
+	valid "portal-blog/lib/validator"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type InvitationHandler interface {
+	GetInvitations(c *fiber.Ctx) error
+	CreateInvitation(c *fiber.Ctx) error
+	ResendInvitation(c *fiber.Ctx) error
+	RevokeInvitation(c *fiber.Ctx) error
+	AcceptInvitation(c *fiber.Ctx) error
+}
+
+type invitationHandler struct {
+	invitationService service.InvitationService
+}
+
+// GetInvitations implements InvitationHandler.
+func (i *invitationHandler) GetInvitations(c *fiber.Ctx) error {
+	results, err := i.invitationService.GetInvitations(c.Context())
+	if err != nil {
+		code := "[HANDLER] GetInvitations - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	now := time.Now()
+	invitationResponses := []response.InvitationResponse{}
+	for _, result := range results {
+		invitationResponses = append(invitationResponses, toInvitationResponse(result, now))
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(invitationResponses))
+}
+
+// CreateInvitation implements InvitationHandler.
+// The invitation link is mailed to the invitee and never returned to the admin.
+func (i *invitationHandler) CreateInvitation(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] CreateInvitation - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	req := request.CreateInvitationRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] CreateInvitation - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] CreateInvitation - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	result, err := i.invitationService.CreateInvitation(c.Context(), entity.InvitationEntity{
+		Email: req.Email,
+		Role:  req.Role,
+	}, claims)
+	if err != nil {
+		code := "[HANDLER] CreateInvitation - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.NewSuccessResponse("Invitation sent").WithData(toInvitationResponse(*result, time.Now())))
+}
+
+// ResendInvitation implements InvitationHandler.
+func (i *invitationHandler) ResendInvitation(c *fiber.Ctx) error {
+	invitationID, err := conv.StringToInt64(c.Params("invitationID"))
+	if err != nil {
+		code := "[HANDLER] ResendInvitation - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := i.invitationService.ResendInvitation(c.Context(), invitationID)
+	if err != nil {
+		code := "[HANDLER] ResendInvitation - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Invitation resent").WithData(toInvitationResponse(*result, time.Now())))
+}
+
+// RevokeInvitation implements InvitationHandler.
+func (i *invitationHandler) RevokeInvitation(c *fiber.Ctx) error {
+	invitationID, err := conv.StringToInt64(c.Params("invitationID"))
+	if err != nil {
+		code := "[HANDLER] RevokeInvitation - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = i.invitationService.RevokeInvitation(c.Context(), invitationID)
+	if err != nil {
+		code := "[HANDLER] RevokeInvitation - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Invitation revoked"))
+}
+
+// AcceptInvitation implements InvitationHandler.
+// It creates the invitee's account; they log in afterwards with their email and new password.
+func (i *invitationHandler) AcceptInvitation(c *fiber.Ctx) error {
+	req := request.AcceptInvitationRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] AcceptInvitation - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] AcceptInvitation - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err := i.invitationService.AcceptInvitation(c.Context(), entity.AcceptInvitationEntity{
+		Token:    req.Token,
+		Name:     req.Name,
+		Password: req.Password,
+	})
+	if err != nil {
+		code := "[HANDLER] AcceptInvitation - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.NewSuccessResponse("Invitation accepted, you can now log in"))
+}
+
+func toInvitationResponse(invitation entity.InvitationEntity, now time.Time) response.InvitationResponse {
+	resp := response.InvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		Status:    invitation.Status(now),
+		InvitedBy: invitation.InvitedBy,
+		UserID:    invitation.UserID,
+		ExpiresAt: invitation.ExpiresAt.Local().Format(time.RFC3339),
+		SentAt:    invitation.SentAt.Local().Format(time.RFC3339),
+		CreatedAt: invitation.CreatedAt.Local().Format(time.RFC3339),
+	}
+
+	if invitation.AcceptedAt != nil {
+		resp.AcceptedAt = invitation.AcceptedAt.Local().Format(time.RFC3339)
+	}
+
+	if invitation.RevokedAt != nil {
+		resp.RevokedAt = invitation.RevokedAt.Local().Format(time.RFC3339)
+	}
+
+	return resp
+}
+
+func NewInvitationHandler(invitationService service.InvitationService) InvitationHandler {
+	return &invitationHandler{invitationService: invitationService}
+}
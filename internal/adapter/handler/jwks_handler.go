@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"portal-blog/lib/auth"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type JwksHandler interface {
+	GetJwks(c *fiber.Ctx) error
+}
+
+type jwksHandler struct {
+	authJwt auth.Jwt
+}
+
+// GetJwks implements JwksHandler.
+// The key set is served as a plain RFC 7517 document, without the usual meta envelope,
+// so JWT libraries can consume it directly.
+func (j *jwksHandler) GetJwks(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+
+	return c.JSON(j.authJwt.JWKS())
+}
+
+func NewJwksHandler(authJwt auth.Jwt) JwksHandler {
+	return &jwksHandler{authJwt: authJwt}
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"errors"
+	"portal-blog/internal/adapter/handler/request"
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/service"
+	"portal-blog/lib/conv"
+	valid "portal-blog/lib/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type MfaHandler interface {
+	// Login enrolment
+	SetupChallenge(c *fiber.Ctx) error
+
+	// Own account
+	GetStatus(c *fiber.Ctx) error
+	Setup(c *fiber.Ctx) error
+	Enable(c *fiber.Ctx) error
+	Disable(c *fiber.Ctx) error
+	RegenerateRecoveryCodes(c *fiber.Ctx) error
+
+	// Admin
+	ResetUserMfa(c *fiber.Ctx) error
+}
+
+type mfaHandler struct {
+	mfaService service.MfaService
+}
+
+// SetupChallenge implements MfaHandler.
+// It returns a new TOTP secret for a user whose login challenge requires enrolment.
+func (m *mfaHandler) SetupChallenge(c *fiber.Ctx) error {
+	req := request.MfaChallengeSetupRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] SetupChallenge - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] SetupChallenge - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	result, err := m.mfaService.SetupChallenge(c.Context(), req.MfaToken)
+	if err != nil {
+		code := "[HANDLER] SetupChallenge - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(response.MfaSetupResponse{
+		Secret:          result.Secret,
+		ProvisioningURI: result.ProvisioningURI,
+	}))
+}
+
+// GetStatus implements MfaHandler.
+func (m *mfaHandler) GetStatus(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetStatus - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	result, err := m.mfaService.GetStatus(c.Context(), int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] GetStatus - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(response.MfaStatusResponse{
+		Enabled:  result.Enabled,
+		Required: result.Required,
+	}))
+}
+
+// Setup implements MfaHandler.
+// The returned provisioning URI is meant to be rendered as a QR code by the client.
+func (m *mfaHandler) Setup(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] Setup - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	result, err := m.mfaService.Setup(c.Context(), int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] Setup - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(response.MfaSetupResponse{
+		Secret:          result.Secret,
+		ProvisioningURI: result.ProvisioningURI,
+	}))
+}
+
+// Enable implements MfaHandler.
+func (m *mfaHandler) Enable(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] Enable - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	req := request.MfaCodeRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] Enable - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] Enable - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	recoveryCodes, err := m.mfaService.Enable(c.Context(), int64(claims.UserID), req.Code)
+	if err != nil {
+		code := "[HANDLER] Enable - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Two-factor authentication enabled").WithData(response.RecoveryCodesResponse{RecoveryCodes: recoveryCodes}))
+}
+
+// Disable implements MfaHandler.
+func (m *mfaHandler) Disable(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] Disable - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	req := request.DisableMfaRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] Disable - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] Disable - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err := m.mfaService.Disable(c.Context(), int64(claims.UserID), req.Password, req.Code)
+	if err != nil {
+		code := "[HANDLER] Disable - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Two-factor authentication disabled"))
+}
+
+// RegenerateRecoveryCodes implements MfaHandler.
+func (m *mfaHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] RegenerateRecoveryCodes - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	req := request.MfaCodeRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] RegenerateRecoveryCodes - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] RegenerateRecoveryCodes - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	recoveryCodes, err := m.mfaService.RegenerateRecoveryCodes(c.Context(), int64(claims.UserID), req.Code)
+	if err != nil {
+		code := "[HANDLER] RegenerateRecoveryCodes - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(response.RecoveryCodesResponse{RecoveryCodes: recoveryCodes}))
+}
+
+// ResetUserMfa implements MfaHandler.
+// It removes the MFA enrolment of the user in the userID path parameter.
+func (m *mfaHandler) ResetUserMfa(c *fiber.Ctx) error {
+	userID, err := conv.StringToInt64(c.Params("userID"))
+	if err != nil {
+		code := "[HANDLER] ResetUserMfa - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = m.mfaService.ResetUserMfa(c.Context(), userID)
+	if err != nil {
+		code := "[HANDLER] ResetUserMfa - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Two-factor authentication reset"))
+}
+
+func NewMfaHandler(mfaService service.MfaService) MfaHandler {
+	return &mfaHandler{mfaService: mfaService}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"portal-blog/internal/adapter/handler/request"
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/service"
+	valid "portal-blog/lib/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type OidcHandler interface {
+	Login(c *fiber.Ctx) error
+	Callback(c *fiber.Ctx) error
+}
+
+type oidcHandler struct {
+	oidcService service.OidcService
+}
+
+// Login implements OidcHandler.
+// The admin panel keeps the returned state, sends the browser to the authorization URL and
+// posts the code and state it is redirected back with to Callback.
+func (o *oidcHandler) Login(c *fiber.Ctx) error {
+	resp := response.OidcLoginResponse{}
+
+	result, err := o.oidcService.StartLogin(c.Context())
+	if err != nil {
+		code := "[HANDLER] OidcLogin - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	resp.Meta.Status = true
+	resp.Meta.Message = "Continue at the identity provider"
+	resp.AuthorizationURL = result.AuthorizationURL
+	resp.State = result.State
+	resp.StateExpiredAt = result.ExpiresAt.Unix()
+
+	return c.JSON(resp)
+}
+
+// Callback implements OidcHandler.
+// It completes the sign-in with the authorization code and answers like Login, with an MFA
+// challenge to complete at /login/mfa when the user needs a second factor.
+func (o *oidcHandler) Callback(c *fiber.Ctx) error {
+	req := request.OidcCallbackRequest{}
+	resp := response.SuccessAuthResponse{}
+
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] OidcCallback - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] OidcCallback - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	result, err := o.oidcService.CompleteLogin(c.Context(), req.Code, req.State, clientInfo(c))
+	if err != nil {
+		code := "[HANDLER] OidcCallback - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if result.MfaChallenge != nil {
+		return c.JSON(toMfaChallengeResponse(*result.MfaChallenge))
+	}
+
+	resp.Meta.Status = true
+	resp.Meta.Message = "Login Successfull"
+	resp.AccessToken = result.AccessToken
+	resp.ExpiredAt = result.ExpiredAt
+	resp.RefreshToken = result.RefreshToken
+	resp.RefreshExpiredAt = result.RefreshExpiredAt
+
+	return c.JSON(resp)
+}
+
+func NewOidcHandler(oidcService service.OidcService) OidcHandler {
+	return &oidcHandler{oidcService: oidcService}
+}
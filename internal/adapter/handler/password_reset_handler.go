@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"portal-blog/internal/adapter/handler/request"
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/service"
+	valid "portal-blog/lib/validator"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type PasswordResetHandler interface {
+	ForgotPassword(c *fiber.Ctx) error
+	ResetPassword(c *fiber.Ctx) error
+}
+
+type passwordResetHandler struct {
+	passwordResetService service.PasswordResetService
+}
+
+// ForgotPassword implements PasswordResetHandler.
+// It always answers with the same message so the response does not reveal whether the email is registered,
+// unless the client IP or the email made too many requests.
+func (p *passwordResetHandler) ForgotPassword(c *fiber.Ctx) error {
+	req := request.ForgotPasswordRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] ForgotPassword - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] ForgotPassword - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err := p.passwordResetService.ForgotPassword(c.Context(), req.Email, c.IP())
+	if err != nil {
+		code := "[HANDLER] ForgotPassword - 3"
+		log.Errorw(code, err)
+
+		var tooManyRequests *service.TooManyLoginAttemptsError
+		if errors.As(err, &tooManyRequests) {
+			seconds := tooManyRequests.RetryAfterSeconds()
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+			errorResp := response.NewErrorResponse(fmt.Sprintf("too many password reset requests, retry after %d seconds", seconds)).
+				WithCode("too_many_password_reset_requests")
+
+			return c.Status(fiber.StatusTooManyRequests).JSON(errorResp)
+		}
+	}
+
+	return c.JSON(response.NewSuccessResponse("If the email is registered, a password reset link has been sent"))
+}
+
+// ResetPassword implements PasswordResetHandler.
+func (p *passwordResetHandler) ResetPassword(c *fiber.Ctx) error {
+	req := request.ResetPasswordRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] ResetPassword - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] ResetPassword - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err := p.passwordResetService.ResetPassword(c.Context(), req.Token, req.NewPassword)
+	if err != nil {
+		code := "[HANDLER] ResetPassword - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Password reset successfully"))
+}
+
+func NewPasswordResetHandler(passwordResetService service.PasswordResetService) PasswordResetHandler {
+	return &passwordResetHandler{passwordResetService: passwordResetService}
+}
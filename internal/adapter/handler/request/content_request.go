@@ -1,11 +1,45 @@
 package request
 
+import (
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/lib/validator"
+	"time"
+)
+
+func init() {
+	err := validator.RegisterOneOf("initial_content_status", []string{entity.ContentStatusDraft, entity.ContentStatusInReview})
+	if err != nil {
+		panic(err)
+	}
+}
+
 type ContentRequest struct {
-	Title       string `json:"title" validate:"required"`
-	Excerpt     string `json:"excerpt" validate:"required"`
-	Description string `json:"description,omitempty" validate:"required"`
-	Image       string `json:"image" validate:"required"`
-	Tags        string `json:"tags"`
-	CategoryID  int64  `json:"category_id" validate:"required"`
-	Status      string `json:"status" validate:"required"`
+	Title       string     `json:"title" validate:"required"`
+	Slug        string     `json:"slug" validate:"omitempty,max=200,slug"`
+	Excerpt     string     `json:"excerpt" validate:"required"`
+	Description string     `json:"description,omitempty" validate:"required"`
+	Image       string     `json:"image" validate:"required"`
+	Tags        string     `json:"tags" validate:"omitempty,tags"`
+	CategoryID  int64      `json:"category_id" validate:"required"`
+	Status      string     `json:"status" validate:"omitempty,initial_content_status"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// UpdateContentRequest is ContentRequest without the status, which only changes through the
+// workflow endpoints.
+type UpdateContentRequest struct {
+	Title       string     `json:"title" validate:"required"`
+	Slug        string     `json:"slug" validate:"omitempty,max=200,slug"`
+	Excerpt     string     `json:"excerpt" validate:"required"`
+	Description string     `json:"description,omitempty" validate:"required"`
+	Image       string     `json:"image" validate:"required"`
+	Tags        string     `json:"tags" validate:"omitempty,tags"`
+	CategoryID  int64      `json:"category_id" validate:"required"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+type RejectContentRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
 }
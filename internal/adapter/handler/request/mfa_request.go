@@ -0,0 +1,14 @@
+package request
+
+type MfaCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type DisableMfaRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type UpdateRoleMfaRequest struct {
+	Required *bool `json:"required" validate:"required"`
+}
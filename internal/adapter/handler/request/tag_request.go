@@ -0,0 +1,12 @@
+package request
+
+// TagRequest creates or renames a tag. Titles may not contain commas, which separate the tags
+// of a content.
+type TagRequest struct {
+	Title string `json:"title" validate:"required,max=50,excludesall=0x2C"`
+	Slug  string `json:"slug" validate:"omitempty,max=200,slug"`
+}
+
+type MergeTagRequest struct {
+	TargetID int64 `json:"target_id" validate:"required"`
+}
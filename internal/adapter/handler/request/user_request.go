@@ -1,7 +1,35 @@
 package request
 
+import (
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/lib/validator"
+)
+
+func init() {
+	err := validator.RegisterRule("role", entity.IsRole, map[string]string{
+		"en": "{0} must be an existing role",
+		"id": "{0} harus berupa peran yang ada",
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
 type UpdatePasswordRequest struct {
 	CurrentPassword string `json:"current_password" validate:"required"`
 	NewPassword     string `json:"new_password" validate:"required,min=8"`
 	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
 }
+
+type CreateUserRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8"`
+	Role     string `json:"role" validate:"required,role"`
+}
+
+type UpdateUserRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"omitempty,role"`
+}
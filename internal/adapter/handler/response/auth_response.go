@@ -2,6 +2,25 @@ package response
 
 type SuccessAuthResponse struct {
 	Meta
-	AccessToken string `json:"access_token"`
-	ExpiredAt int64 `json:"expired_at"`
-}
\ No newline at end of file
+	AccessToken      string `json:"access_token"`
+	ExpiredAt        int64  `json:"expired_at"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiredAt int64  `json:"refresh_expired_at"`
+
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type MfaChallengeResponse struct {
+	Meta
+	MfaRequired        bool   `json:"mfa_required"`
+	MfaToken           string `json:"mfa_token"`
+	MfaExpiredAt       int64  `json:"mfa_expired_at"`
+	EnrollmentRequired bool   `json:"enrollment_required"`
+}
+
+type OidcLoginResponse struct {
+	Meta
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+	StateExpiredAt   int64  `json:"state_expired_at"`
+}
@@ -1,16 +1,50 @@
 package response
 
 type ContentResponse struct {
-	ID           int64    `json:"id"`
+	ID              int64    `json:"id"`
+	Title           string   `json:"title"`
+	Slug            string   `json:"slug"`
+	Excerpt         string   `json:"excerpt"`
+	Description     string   `json:"description,omitempty"`
+	Image           string   `json:"image"`
+	Tags            []string `json:"tags,omitempty"`
+	Status          string   `json:"status"`
+	RejectionReason string   `json:"rejection_reason,omitempty"`
+	PublishAt       string   `json:"publish_at,omitempty"`
+	UnpublishAt     string   `json:"unpublish_at,omitempty"`
+	CategoryID      int64    `json:"category_id,omitempty"`
+	CreatedByID     int64    `json:"created_by_id,omitempty"`
+	CreatedAt       string   `json:"created_at,omitempty"`
+	CategoryName    string   `json:"category_name"`
+	Author          string   `json:"author"`
+	Headline        string   `json:"headline,omitempty"`
+}
+
+type ContentRevisionResponse struct {
+	Revision     int      `json:"revision"`
 	Title        string   `json:"title"`
 	Excerpt      string   `json:"excerpt"`
-	Description  string   `json:"description,omitempty"`
+	Description  string   `json:"description"`
 	Image        string   `json:"image"`
-	Tags         []string `json:"tags,omitempty"`
-	Status       string   `json:"status"`
-	CategoryID   int64    `json:"category_id,omitempty"`
-	CreatedByID  int64    `json:"created_by_id,omitempty"`
-	CreatedAt    string   `json:"created_at,omitempty"`
-	CategoryName string   `json:"category_name"`
+	Tags         []string `json:"tags"`
+	CategoryID   int64    `json:"category_id"`
+	AuthorID     *int64   `json:"author_id"`
 	Author       string   `json:"author"`
+	RestoredFrom *int     `json:"restored_from,omitempty"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+type ContentFieldChangeResponse struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type ContentRevisionDiffResponse struct {
+	ContentID     int64                        `json:"content_id"`
+	From          int                          `json:"from"`
+	To            int                          `json:"to"`
+	FromCreatedAt string                       `json:"from_created_at"`
+	ToCreatedAt   string                       `json:"to_created_at"`
+	Changes       []ContentFieldChangeResponse `json:"changes"`
 }
@@ -1,7 +1,11 @@
 package response
 
+import "portal-blog/lib/validator"
+
 type ErrorResponseDefault struct {
 	Meta    
+	Code string `json:"code,omitempty"`
+	Errors []validator.FieldError `json:"errors,omitempty"`
 }
 
 type Meta struct {
@@ -13,6 +17,7 @@ type DefaultSucessResponse struct {
 	Meta Meta `json:"meta"`
 	Data interface{} `json:"data,omitempty"`
 	Pagination *PaginationResponse `json:"pagination,omitempty"`
+	DidYouMean string `json:"did_you_mean,omitempty"`
 }
 
 type PaginationResponse struct {
@@ -0,0 +1,58 @@
+package response
+
+import "portal-blog/lib/validator"
+
+// NewErrorResponse returns a new error envelope with the given message.
+//
+// Handlers build a fresh envelope for every response; a shared one would be overwritten by
+// requests served concurrently.
+func NewErrorResponse(message string) ErrorResponseDefault {
+	return ErrorResponseDefault{
+		Meta: Meta{
+			Status:  false,
+			Message: message,
+		},
+	}
+}
+
+// WithCode sets the machine-readable error code of the envelope and returns it.
+func (r ErrorResponseDefault) WithCode(code string) ErrorResponseDefault {
+	r.Code = code
+	return r
+}
+
+// WithErrors sets the field errors of the envelope and returns it.
+func (r ErrorResponseDefault) WithErrors(errors []validator.FieldError) ErrorResponseDefault {
+	r.Errors = errors
+	return r
+}
+
+// NewSuccessResponse returns a new success envelope with the given message and neither data
+// nor pagination. Use WithData and WithPagination to fill them in.
+func NewSuccessResponse(message string) *DefaultSucessResponse {
+	return &DefaultSucessResponse{
+		Meta: Meta{
+			Status:  true,
+			Message: message,
+		},
+	}
+}
+
+// WithData sets the data of the envelope and returns it.
+func (r *DefaultSucessResponse) WithData(data interface{}) *DefaultSucessResponse {
+	r.Data = data
+	return r
+}
+
+// WithPagination sets the pagination of the envelope and returns it.
+func (r *DefaultSucessResponse) WithPagination(pagination *PaginationResponse) *DefaultSucessResponse {
+	r.Pagination = pagination
+	return r
+}
+
+// WithDidYouMean sets the corrected search query suggested for a search without results and
+// returns the envelope.
+func (r *DefaultSucessResponse) WithDidYouMean(query string) *DefaultSucessResponse {
+	r.DidYouMean = query
+	return r
+}
@@ -0,0 +1,15 @@
+package response
+
+type InvitationResponse struct {
+	ID         int64  `json:"id"`
+	Email      string `json:"email"`
+	Role       string `json:"role"`
+	Status     string `json:"status"`
+	InvitedBy  *int64 `json:"invited_by"`
+	UserID     *int64 `json:"user_id"`
+	ExpiresAt  string `json:"expires_at"`
+	SentAt     string `json:"sent_at"`
+	AcceptedAt string `json:"accepted_at,omitempty"`
+	RevokedAt  string `json:"revoked_at,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
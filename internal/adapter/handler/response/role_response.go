@@ -0,0 +1,7 @@
+package response
+
+type RoleResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MfaRequired bool   `json:"mfa_required"`
+}
@@ -0,0 +1,19 @@
+package response
+
+type SuggestedContentResponse struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+type SuggestedCategoryResponse struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+type SearchSuggestionResponse struct {
+	Contents   []SuggestedContentResponse  `json:"contents"`
+	Categories []SuggestedCategoryResponse `json:"categories"`
+	Tags       []string                    `json:"tags"`
+}
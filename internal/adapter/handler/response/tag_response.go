@@ -0,0 +1,8 @@
+package response
+
+type TagResponse struct {
+	ID           int64  `json:"id"`
+	Title        string `json:"title"`
+	Slug         string `json:"slug"`
+	ContentCount int64  `json:"content_count"`
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"portal-blog/internal/adapter/handler/request"
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/service"
+	valid "portal-blog/lib/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type RoleHandler interface {
+	GetRoles(c *fiber.Ctx) error
+	UpdateRoleMfa(c *fiber.Ctx) error
+}
+
+type roleHandler struct {
+	roleService service.RoleService
+}
+
+// GetRoles implements RoleHandler.
+func (r *roleHandler) GetRoles(c *fiber.Ctx) error {
+	results, err := r.roleService.GetRoles(c.Context())
+	if err != nil {
+		code := "[HANDLER] GetRoles - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	roleResponses := []response.RoleResponse{}
+	for _, result := range results {
+		roleResponses = append(roleResponses, response.RoleResponse{
+			Name:        result.Name,
+			Description: result.Description,
+			MfaRequired: result.MfaRequired,
+		})
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(roleResponses))
+}
+
+// UpdateRoleMfa implements RoleHandler.
+// It sets whether users of the role in the roleName path parameter must use MFA.
+func (r *roleHandler) UpdateRoleMfa(c *fiber.Ctx) error {
+	req := request.UpdateRoleMfaRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] UpdateRoleMfa - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := valid.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] UpdateRoleMfa - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err := r.roleService.SetRoleMfaRequired(c.Context(), c.Params("roleName"), *req.Required)
+	if err != nil {
+		code := "[HANDLER] UpdateRoleMfa - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success"))
+}
+
+func NewRoleHandler(roleService service.RoleService) RoleHandler {
+	return &roleHandler{roleService: roleService}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/service"
+	"portal-blog/lib/conv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// maxSuggestLimit caps the limit query parameter of Suggest.
+const maxSuggestLimit = 10
+
+type SearchHandler interface {
+	Suggest(c *fiber.Ctx) error
+}
+
+type searchHandler struct {
+	searchService service.SearchService
+}
+
+// Suggest returns type-ahead suggestions for the search box: titles of live contents,
+// categories and tags similar to the q query parameter, at most limit of each.
+func (s *searchHandler) Suggest(c *fiber.Ctx) error {
+	var err error
+
+	limit := 5
+	if c.Query("limit") != "" {
+		limit, err = conv.StringToInt(c.Query("limit"))
+		if err != nil || limit < 1 || limit > maxSuggestLimit {
+			log.Errorw("[HANDLER] Suggest - 1", "Error parsing limit query", err)
+			errorResp := response.NewErrorResponse("Invalid limit number")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	result, err := s.searchService.Suggest(c.Context(), c.Query("q"), limit)
+	if err != nil {
+		code := "[HANDLER] Suggest - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	resp := response.SearchSuggestionResponse{
+		Contents:   []response.SuggestedContentResponse{},
+		Categories: []response.SuggestedCategoryResponse{},
+		Tags:       result.Tags,
+	}
+
+	for _, content := range result.Contents {
+		resp.Contents = append(resp.Contents, response.SuggestedContentResponse{
+			ID:    content.ID,
+			Title: content.Title,
+			Slug:  content.Slug,
+		})
+	}
+
+	for _, category := range result.Categories {
+		resp.Categories = append(resp.Categories, response.SuggestedCategoryResponse{
+			ID:    category.ID,
+			Title: category.Title,
+			Slug:  category.Slug,
+		})
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(resp))
+}
+
+func NewSearchHandler(searchService service.SearchService) SearchHandler {
+	return &searchHandler{searchService: searchService}
+}
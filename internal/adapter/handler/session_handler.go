@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/service"
+	"portal-blog/lib/conv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type SessionHandler interface {
+	GetSessions(c *fiber.Ctx) error
+	RevokeSession(c *fiber.Ctx) error
+	RevokeAllSessions(c *fiber.Ctx) error
+}
+
+type sessionHandler struct {
+	sessionService service.SessionService
+}
+
+// GetSessions implements SessionHandler.
+// It lists where the logged in user is signed in and marks the session making the request.
+func (s *sessionHandler) GetSessions(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetSessions - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	results, err := s.sessionService.GetSessions(c.Context(), int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] GetSessions - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	sessionResponses := []response.SessionResponse{}
+	for _, result := range results {
+		sessionResponses = append(sessionResponses, response.SessionResponse{
+			ID:         result.ID,
+			UserAgent:  result.UserAgent,
+			IP:         result.IP,
+			CreatedAt:  result.CreatedAt.Local().Format(time.RFC3339),
+			LastSeenAt: result.LastSeenAt.Local().Format(time.RFC3339),
+			ExpiresAt:  result.ExpiresAt.Local().Format(time.RFC3339),
+			Current:    result.ID == claims.SessionID,
+		})
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(sessionResponses))
+}
+
+// RevokeSession implements SessionHandler.
+// The session is signed out on every device using it, which may be the current one.
+func (s *sessionHandler) RevokeSession(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] RevokeSession - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	sessionID, err := conv.StringToInt64(c.Params("sessionID"))
+	if err != nil {
+		code := "[HANDLER] RevokeSession - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = s.sessionService.RevokeSession(c.Context(), sessionID, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] RevokeSession - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Session signed out"))
+}
+
+// RevokeAllSessions implements SessionHandler.
+// It signs the user out everywhere, including the session making the request.
+func (s *sessionHandler) RevokeAllSessions(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] RevokeAllSessions - 1"
+		err := errors.New("user not authorized")
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse("Unauthorized access")
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	err := s.sessionService.RevokeAllSessions(c.Context(), int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] RevokeAllSessions - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Signed out everywhere"))
+}
+
+func NewSessionHandler(sessionService service.SessionService) SessionHandler {
+	return &sessionHandler{sessionService: sessionService}
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"portal-blog/internal/adapter/handler/request"
+	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/service"
+	"portal-blog/lib/conv"
+	"portal-blog/lib/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type TagHandler interface {
+	GetTags(c *fiber.Ctx) error
+	GetTagByID(c *fiber.Ctx) error
+	CreateTag(c *fiber.Ctx) error
+	UpdateTag(c *fiber.Ctx) error
+	DeleteTag(c *fiber.Ctx) error
+	MergeTags(c *fiber.Ctx) error
+
+	// FE
+	GetTagsFE(c *fiber.Ctx) error
+}
+
+type tagHandler struct {
+	tagService service.TagService
+}
+
+// GetTags implements TagHandler.
+func (th *tagHandler) GetTags(c *fiber.Ctx) error {
+	results, err := th.tagService.GetTags(c.Context())
+	if err != nil {
+		code := "[HANDLER] GetTags - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Tags fetched successfully").WithData(toTagResponses(results)))
+}
+
+// GetTagByID implements TagHandler.
+func (th *tagHandler) GetTagByID(c *fiber.Ctx) error {
+	id, err := conv.StringToInt64(c.Params("tagID"))
+	if err != nil {
+		code := "[HANDLER] GetTagByID - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := th.tagService.GetTagByID(c.Context(), id)
+	if err != nil {
+		code := "[HANDLER] GetTagByID - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Tag fetched successfully").WithData(toTagResponse(*result)))
+}
+
+// CreateTag implements TagHandler.
+func (th *tagHandler) CreateTag(c *fiber.Ctx) error {
+	var req request.TagRequest
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] CreateTag - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := validator.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] CreateTag - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	result, err := th.tagService.CreateTag(c.Context(), entity.TagEntity{
+		Title: req.Title,
+		Slug:  req.Slug,
+	})
+	if err != nil {
+		code := "[HANDLER] CreateTag - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.NewSuccessResponse("Tag created successfully").WithData(toTagResponse(*result)))
+}
+
+// UpdateTag implements TagHandler.
+// Renaming a tag renames it in every content that has it.
+func (th *tagHandler) UpdateTag(c *fiber.Ctx) error {
+	id, err := conv.StringToInt64(c.Params("tagID"))
+	if err != nil {
+		code := "[HANDLER] UpdateTag - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var req request.TagRequest
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] UpdateTag - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := validator.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] UpdateTag - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	result, err := th.tagService.UpdateTag(c.Context(), entity.TagEntity{
+		ID:    id,
+		Title: req.Title,
+		Slug:  req.Slug,
+	})
+	if err != nil {
+		code := "[HANDLER] UpdateTag - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Tag updated successfully").WithData(toTagResponse(*result)))
+}
+
+// DeleteTag implements TagHandler.
+func (th *tagHandler) DeleteTag(c *fiber.Ctx) error {
+	id, err := conv.StringToInt64(c.Params("tagID"))
+	if err != nil {
+		code := "[HANDLER] DeleteTag - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = th.tagService.DeleteTag(c.Context(), id)
+	if err != nil {
+		code := "[HANDLER] DeleteTag - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Tag deleted successfully"))
+}
+
+// MergeTags implements TagHandler.
+// The tag in the path is merged into the tag with the target_id of the body and answers with
+// the latter.
+func (th *tagHandler) MergeTags(c *fiber.Ctx) error {
+	id, err := conv.StringToInt64(c.Params("tagID"))
+	if err != nil {
+		code := "[HANDLER] MergeTags - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var req request.MergeTagRequest
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] MergeTags - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := validator.ValidateStruct(req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] MergeTags - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	result, err := th.tagService.MergeTags(c.Context(), id, req.TargetID)
+	if err != nil {
+		code := "[HANDLER] MergeTags - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Tags merged successfully").WithData(toTagResponse(*result)))
+}
+
+// GetTagsFE implements TagHandler.
+// Only tags of live contents are listed, the most used first, counting live contents only.
+func (th *tagHandler) GetTagsFE(c *fiber.Ctx) error {
+	results, err := th.tagService.GetPublicTags(c.Context())
+	if err != nil {
+		code := "[HANDLER] GetTagsFE - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Tags fetched successfully").WithData(toTagResponses(results)))
+}
+
+func toTagResponse(tag entity.TagEntity) response.TagResponse {
+	return response.TagResponse{
+		ID:           tag.ID,
+		Title:        tag.Title,
+		Slug:         tag.Slug,
+		ContentCount: tag.ContentCount,
+	}
+}
+
+func toTagResponses(tags []entity.TagEntity) []response.TagResponse {
+	resps := []response.TagResponse{}
+	for _, tag := range tags {
+		resps = append(resps, toTagResponse(tag))
+	}
+
+	return resps
+}
+
+func NewTagHandler(tagService service.TagService) TagHandler {
+	return &tagHandler{tagService: tagService}
+}
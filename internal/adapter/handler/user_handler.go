@@ -6,7 +6,9 @@ import (
 	"portal-blog/internal/adapter/handler/response"
 	"portal-blog/internal/core/domain/entity"
 	"portal-blog/internal/core/service"
+	"portal-blog/lib/conv"
 	"portal-blog/lib/validator"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
@@ -15,6 +17,15 @@ import (
 type UserHandler interface {
 	UpdatePassword(c *fiber.Ctx) error
 	GetUserByID(c *fiber.Ctx) error
+
+	// Admin user management
+	GetUsers(c *fiber.Ctx) error
+	GetUserDetail(c *fiber.Ctx) error
+	CreateUser(c *fiber.Ctx) error
+	UpdateUser(c *fiber.Ctx) error
+	ActivateUser(c *fiber.Ctx) error
+	DeactivateUser(c *fiber.Ctx) error
+	DeleteUser(c *fiber.Ctx) error
 }
 
 type userHandler struct {
@@ -38,8 +49,7 @@ func (u *userHandler) GetUserByID(c *fiber.Ctx) error {
 		code := "[HANDLER] GetContentByID - 1"
 		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
@@ -48,23 +58,18 @@ func (u *userHandler) GetUserByID(c *fiber.Ctx) error {
 	if err != nil {
 		code := "[HANDLER] GetContentByID - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
-
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		return err
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Success"
 	resp := response.UserResponse{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
+		ID:       user.ID,
+		Name:     user.Name,
+		Email:    user.Email,
+		Role:     user.Role,
+		IsActive: user.IsActive,
 	}
 
-	defaultSuccessResponse.Data = resp
-
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("Success").WithData(resp))
 }
 
 // UpdatePassword updates the user's password.
@@ -83,46 +88,323 @@ func (u *userHandler) UpdatePassword(c *fiber.Ctx) error {
 		code := "[HANDLER] GetContentByID - 1"
 		err := errors.New("user not authorized")
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = "Unauthorized access"
+		errorResp := response.NewErrorResponse("Unauthorized access")
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
 
 	var req request.UpdatePasswordRequest
-	if err = c.BodyParser(&req); err != nil {
+	if err := c.BodyParser(&req); err != nil {
 		code := "[HANDLER] UpdatePassword - 1"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	if err = validator.ValidateStruct(&req); err != nil {
+	if err := validator.ValidateStruct(&req, acceptLanguage(c)); err != nil {
 		code := "[HANDLER] UpdatePassword - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		return err
+	}
+
+	err := u.userService.UpdatePassword(c.Context(), req.CurrentPassword, req.NewPassword, claims)
+	if err != nil {
+		code := "[HANDLER] UpdatePassword - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success"))
+}
+
+// GetUsers lists users with pagination.
+//
+// Input:
+//   - c: *fiber.Ctx - The request context. Supports the page, limit, search, role and isActive query parameters.
+//
+// Output:
+//   - error: Returns a bad request response for invalid query parameters or an internal server error if fetching fails.
+func (u *userHandler) GetUsers(c *fiber.Ctx) error {
+	var err error
+	// Page
+	page := 1
+	if c.Query("page") != "" {
+		page, err = conv.StringToInt(c.Query("page"))
+		if err != nil || page < 1 {
+			log.Errorw("[HANDLER] GetUsers - 1", "Error parsing page query", err)
+			errorResp := response.NewErrorResponse("Invalid page number")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	// Limit
+	limit := 10
+	if c.Query("limit") != "" {
+		limit, err = conv.StringToInt(c.Query("limit"))
+		if err != nil || limit < 1 {
+			log.Errorw("[HANDLER] GetUsers - 2", "Error parsing limit query", err)
+			errorResp := response.NewErrorResponse("Invalid limit number")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	// IsActive
+	var isActive *bool
+	if c.Query("isActive") != "" {
+		active, err := strconv.ParseBool(c.Query("isActive"))
+		if err != nil {
+			log.Errorw("[HANDLER] GetUsers - 3", "Error parsing isActive query", err)
+			errorResp := response.NewErrorResponse("Invalid isActive value")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+		isActive = &active
+	}
+
+	queryEntity := entity.UserQueryString{
+		Limit:    limit,
+		Page:     page,
+		Search:   c.Query("search"),
+		Role:     c.Query("role"),
+		IsActive: isActive,
+	}
+
+	results, totalData, totalPages, err := u.userService.GetUsers(c.Context(), queryEntity)
+	if err != nil {
+		code := "[HANDLER] GetUsers - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	respUsers := []response.UserResponse{}
+	for _, user := range results {
+		respUsers = append(respUsers, response.UserResponse{
+			ID:        user.ID,
+			Name:      user.Name,
+			Email:     user.Email,
+			Role:      user.Role,
+			IsActive:  user.IsActive,
+			CreatedAt: user.CreatedAt.Local().String(),
+		})
+	}
+
+	pagination := &response.PaginationResponse{
+		TotalRecords: int(totalData),
+		Page:         page,
+		PerPage:      limit,
+		TotalPages:   int(totalPages),
+	}
+
+	return c.JSON(response.NewSuccessResponse("Success").WithData(respUsers).WithPagination(pagination))
+}
+
+// GetUserDetail retrieves any user by the userID path parameter.
+//
+// Input:
+//   - c: *fiber.Ctx - The request context containing the userID parameter.
+//
+// Output:
+//   - error: Returns a bad request response for an invalid ID, or the service error for ErrorHandler,
+//     which answers a not found for unknown users.
+func (u *userHandler) GetUserDetail(c *fiber.Ctx) error {
+	userID, err := conv.StringToInt64(c.Params("userID"))
+	if err != nil {
+		code := "[HANDLER] GetUserDetail - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
 
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	err = u.userService.UpdatePassword(c.Context(), req.NewPassword, int64(claims.UserID))
+	user, err := u.userService.GetUserByID(c.Context(), userID)
 	if err != nil {
-		code := "[HANDLER] UpdatePassword - 3"
+		code := "[HANDLER] GetUserDetail - 2"
 		log.Errorw(code, err)
-		errorResp.Meta.Status = false
-		errorResp.Meta.Message = err.Error()
+		return err
+	}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	return c.JSON(response.NewSuccessResponse("Success").WithData(response.UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt.Local().String(),
+	}))
+}
+
+// CreateUser creates a new user with the given role.
+//
+// Input:
+//   - c: *fiber.Ctx - The request context containing the create user request body.
+//
+// Output:
+//   - error: Returns a bad request response if validation fails, a conflict if the email is taken,
+//     or an internal server error if creating the user fails.
+func (u *userHandler) CreateUser(c *fiber.Ctx) error {
+	var req request.CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		code := "[HANDLER] CreateUser - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err := validator.ValidateStruct(&req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] CreateUser - 2"
+		log.Errorw(code, err)
+		return err
 	}
 
-	defaultSuccessResponse.Meta.Status = true
-	defaultSuccessResponse.Meta.Message = "Success"
-	defaultSuccessResponse.Data = nil
+	reqEntity := entity.UserEntity{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+		Role:     req.Role,
+	}
+
+	err := u.userService.CreateUser(c.Context(), reqEntity)
+	if err != nil {
+		code := "[HANDLER] CreateUser - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.NewSuccessResponse("User created successfully"))
+}
+
+// UpdateUser updates the name, email and optionally the role of a user.
+//
+// Input:
+//   - c: *fiber.Ctx - The request context containing the userID parameter and the update user request body.
+//
+// Output:
+//   - error: Returns a bad request response if validation fails, a conflict if the email is taken,
+//     or an internal server error if updating the user fails.
+func (u *userHandler) UpdateUser(c *fiber.Ctx) error {
+	userID, err := conv.StringToInt64(c.Params("userID"))
+	if err != nil {
+		code := "[HANDLER] UpdateUser - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var req request.UpdateUserRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] UpdateUser - 2"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validator.ValidateStruct(&req, acceptLanguage(c)); err != nil {
+		code := "[HANDLER] UpdateUser - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	reqEntity := entity.UserEntity{
+		ID:    userID,
+		Name:  req.Name,
+		Email: req.Email,
+		Role:  req.Role,
+	}
+
+	err = u.userService.UpdateUser(c.Context(), reqEntity)
+	if err != nil {
+		code := "[HANDLER] UpdateUser - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return c.JSON(response.NewSuccessResponse("User updated successfully"))
+}
+
+// ActivateUser re-enables a deactivated user.
+func (u *userHandler) ActivateUser(c *fiber.Ctx) error {
+	return u.updateUserStatus(c, true)
+}
+
+// DeactivateUser disables a user so they can no longer log in or refresh their tokens.
+func (u *userHandler) DeactivateUser(c *fiber.Ctx) error {
+	return u.updateUserStatus(c, false)
+}
+
+// updateUserStatus sets the active flag of the user in the userID path parameter.
+func (u *userHandler) updateUserStatus(c *fiber.Ctx, isActive bool) error {
+	claims := c.Locals("user").(*entity.JwtData)
+
+	userID, err := conv.StringToInt64(c.Params("userID"))
+	if err != nil {
+		code := "[HANDLER] updateUserStatus - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = u.userService.UpdateUserStatus(c.Context(), userID, isActive, claims)
+	if err != nil {
+		code := "[HANDLER] updateUserStatus - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	message := "User deactivated successfully"
+	if isActive {
+		message = "User activated successfully"
+	}
+
+	return c.JSON(response.NewSuccessResponse(message))
+}
+
+// DeleteUser deletes a user, reassigning their contents and categories to the reassignTo query parameter.
+//
+// Input:
+//   - c: *fiber.Ctx - The request context containing the userID parameter and the reassignTo query parameter.
+//
+// Output:
+//   - error: Returns a bad request response for invalid input, or the service error for ErrorHandler,
+//     which answers a conflict when reassignment is required.
+func (u *userHandler) DeleteUser(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+
+	userID, err := conv.StringToInt64(c.Params("userID"))
+	if err != nil {
+		code := "[HANDLER] DeleteUser - 1"
+		log.Errorw(code, err)
+		errorResp := response.NewErrorResponse(err.Error())
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var reassignTo int64
+	if c.Query("reassignTo") != "" {
+		reassignTo, err = conv.StringToInt64(c.Query("reassignTo"))
+		if err != nil {
+			code := "[HANDLER] DeleteUser - 2"
+			log.Errorw(code, err)
+			errorResp := response.NewErrorResponse("Invalid reassignTo")
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	err = u.userService.DeleteUser(c.Context(), userID, reassignTo, claims)
+	if err != nil {
+		code := "[HANDLER] DeleteUser - 3"
+		log.Errorw(code, err)
+		return err
+	}
 
-	return c.JSON(defaultSuccessResponse)
+	return c.JSON(response.NewSuccessResponse("User deleted successfully"))
 }
 
 func NewUserHandler(userService service.UserService) UserHandler {
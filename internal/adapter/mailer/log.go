@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/port"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type logMailer struct {
+	dir  string
+	from string
+}
+
+// Send implements port.Mailer.
+// The message is logged and, when a directory is configured, written to it as an .eml file.
+func (l *logMailer) Send(ctx context.Context, mail entity.MailEntity) error {
+	log.Infof("[MAILER] to=%s subject=%q\n%s", mail.To, mail.Subject, mail.Body)
+
+	if l.dir == "" {
+		return nil
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), mail.To)
+	err := os.WriteFile(filepath.Join(l.dir, name), buildMessage(l.from, mail), 0644)
+	if err != nil {
+		code := "[MAILER] Send - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// NewLogMailer creates a port.Mailer for local development and tests that never delivers mail.
+// Messages are logged and, when dir is not empty, saved to dir.
+func NewLogMailer(dir, from string) (port.Mailer, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0775); err != nil {
+			return nil, err
+		}
+	}
+
+	return &logMailer{dir: dir, from: from}, nil
+}
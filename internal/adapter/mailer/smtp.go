@@ -0,0 +1,62 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"portal-blog/config"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/port"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// Send implements port.Mailer.
+func (s *smtpMailer) Send(ctx context.Context, mail entity.MailEntity) error {
+	err := smtp.SendMail(s.addr, s.auth, s.from, []string{mail.To}, buildMessage(s.from, mail))
+	if err != nil {
+		code := "[MAILER] Send - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// buildMessage renders a plain text RFC 5322 message.
+func buildMessage(from string, mail entity.MailEntity) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mail.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
+
+// NewSMTPMailer creates a port.Mailer that delivers mail through the configured SMTP server.
+// PLAIN authentication is used when a username is configured.
+func NewSMTPMailer(cfg *config.Config) port.Mailer {
+	var auth smtp.Auth
+	if cfg.Mail.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.SMTPHost)
+	}
+
+	return &smtpMailer{
+		addr: fmt.Sprintf("%s:%s", cfg.Mail.SMTPHost, cfg.Mail.SMTPPort),
+		auth: auth,
+		from: cfg.Mail.From,
+	}
+}
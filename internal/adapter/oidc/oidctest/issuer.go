@@ -0,0 +1,204 @@
+// Package oidctest provides an OpenID Connect issuer for tests and local development. It
+// signs anyone in and must never be part of a production build.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+	"portal-blog/lib/auth"
+	"portal-blog/lib/conv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const issuerKeyID = "mock"
+
+// IssuerOptions configures the identity the issuer signs in.
+type IssuerOptions struct {
+	IssuerURL string
+	ClientID  string
+	Subject   string
+	Email     string
+	Name      string
+	RoleClaim string
+	Roles     []string
+}
+
+type mockAuthorization struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	expiresAt     time.Time
+}
+
+type mockIssuer struct {
+	opts IssuerOptions
+	key  *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]mockAuthorization
+}
+
+// ServeHTTP implements http.Handler.
+func (m *mockIssuer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		m.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"issuer":                                m.opts.IssuerURL,
+			"authorization_endpoint":                m.opts.IssuerURL + "/authorize",
+			"token_endpoint":                        m.opts.IssuerURL + "/token",
+			"jwks_uri":                              m.opts.IssuerURL + "/jwks",
+			"response_types_supported":              []string{"code"},
+			"subject_types_supported":               []string{"public"},
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+			"code_challenge_methods_supported":      []string{"S256"},
+		})
+	case "/jwks":
+		m.writeJSON(w, http.StatusOK, auth.JSONWebKeySet{Keys: []auth.JSONWebKey{{
+			Kty: "RSA",
+			Kid: issuerKeyID,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}}})
+	case "/authorize":
+		m.authorize(w, r)
+	case "/token":
+		m.token(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// authorize signs the configured identity in without asking and redirects back with a code.
+func (m *mockIssuer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != m.opts.ClientID ||
+		query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	authCode, err := conv.GenerateRandomToken(16)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	m.mu.Lock()
+	m.codes[authCode] = mockAuthorization{
+		clientID:      m.opts.ClientID,
+		redirectURI:   redirectURI.String(),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	m.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", authCode)
+	params.Set("state", query.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// token redeems a code once, checking the redirect URI and the PKCE verifier.
+func (m *mockIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		m.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	authCode := r.PostForm.Get("code")
+	m.mu.Lock()
+	authorization, ok := m.codes[authCode]
+	delete(m.codes, authCode)
+	m.mu.Unlock()
+
+	clientID := r.PostForm.Get("client_id")
+	if user, _, hasBasic := r.BasicAuth(); hasBasic {
+		clientID, _ = url.QueryUnescape(user)
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	if !ok || time.Now().After(authorization.expiresAt) || clientID != authorization.clientID ||
+		r.PostForm.Get("redirect_uri") != authorization.redirectURI ||
+		subtle.ConstantTimeCompare([]byte(challenge), []byte(authorization.codeChallenge)) != 1 {
+		m.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            m.opts.IssuerURL,
+		"sub":            m.opts.Subject,
+		"aud":            m.opts.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          authorization.nonce,
+		"email":          m.opts.Email,
+		"email_verified": true,
+		"name":           m.opts.Name,
+	}
+	if m.opts.RoleClaim != "" {
+		claims[m.opts.RoleClaim] = m.opts.Roles
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = issuerKeyID
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		m.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": authCode,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (m *mockIssuer) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// NewIssuer creates a minimal OpenID Connect issuer for local development and tests.
+// Every authorization request is approved for the identity in opts without a login page,
+// and ID tokens are signed with an RSA key generated at startup.
+// It must never be exposed outside a development environment.
+func NewIssuer(opts IssuerOptions) (http.Handler, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.IssuerURL = strings.TrimSuffix(opts.IssuerURL, "/")
+
+	return &mockIssuer{opts: opts, key: key, codes: make(map[string]mockAuthorization)}, nil
+}
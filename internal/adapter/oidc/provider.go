@@ -0,0 +1,378 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"portal-blog/config"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/port"
+	"portal-blog/lib/auth"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksRefreshInterval limits how often the issuer's keys are fetched again when an ID token
+// is signed with an unknown kid.
+const jwksRefreshInterval = time.Minute
+
+// ErrInvalidIDToken is wrapped by every error caused by an ID token that failed validation.
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+// discoveryDocument holds the fields of the issuer's OpenID Provider Metadata that are used.
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	TokenType        string `json:"token_type"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type provider struct {
+	issuerURL    string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       string
+	leeway       time.Duration
+	client       *http.Client
+
+	// mu guards the cached fields below. It is never held during a request to the issuer,
+	// so a slow issuer does not stall logins that can be served from the cache.
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+	// keysFetching is closed when the JWKS fetch in progress ends; nil when none is.
+	keysFetching chan struct{}
+}
+
+// AuthCodeURL implements port.IdentityProvider.
+func (p *provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		code := "[OIDC] AuthCodeURL - 1"
+		log.Errorw(code, err)
+		return "", err
+	}
+
+	authURL, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		code := "[OIDC] AuthCodeURL - 2"
+		log.Errorw(code, err)
+		return "", err
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.clientID)
+	query.Set("redirect_uri", p.redirectURL)
+	query.Set("scope", p.scopes)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange implements port.IdentityProvider.
+// The code is redeemed at the token endpoint and the returned ID token is checked for its
+// signature, issuer, audience, expiry and nonce.
+func (p *provider) Exchange(ctx context.Context, authCode, codeVerifier, nonce string) (*entity.OidcIdentityEntity, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		code := "[OIDC] Exchange - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", authCode)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.clientID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		code := "[OIDC] Exchange - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+	}
+
+	var token tokenResponse
+	status, err := p.do(req, &token)
+	if err != nil {
+		code := "[OIDC] Exchange - 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if status != http.StatusOK || token.Error != "" {
+		code := "[OIDC] Exchange - 4"
+		err = fmt.Errorf("token endpoint returned %d: %s %s", status, token.Error, token.ErrorDescription)
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if token.IDToken == "" {
+		code := "[OIDC] Exchange - 5"
+		err = fmt.Errorf("%w: token response has no id_token", ErrInvalidIDToken)
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	identity, err := p.verifyIDToken(ctx, doc, token.IDToken, nonce)
+	if err != nil {
+		code := "[OIDC] Exchange - 6"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// verifyIDToken validates an ID token as described in OpenID Connect Core 3.1.3.7.
+func (p *provider) verifyIDToken(ctx context.Context, doc *discoveryDocument, rawToken, nonce string) (*entity.OidcIdentityEntity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.publicKey(ctx, doc, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(p.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(p.leeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce == "" || tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	audience, _ := claims.GetAudience()
+	if azp, ok := claims["azp"].(string); (ok || len(audience) > 1) && azp != p.clientID {
+		return nil, fmt.Errorf("%w: authorized party mismatch", ErrInvalidIDToken)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	identity := &entity.OidcIdentityEntity{
+		Issuer:  doc.Issuer,
+		Subject: subject,
+		Claims:  claims,
+	}
+	identity.Email, _ = claims["email"].(string)
+	identity.Name, _ = claims["name"].(string)
+
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+
+	return identity, nil
+}
+
+// publicKey returns the issuer key with the given kid, fetching the JWKS again when the kid
+// is unknown so keys rotated by the issuer are picked up. Concurrent callers share one fetch.
+func (p *provider) publicKey(ctx context.Context, doc *discoveryDocument, kid string) (crypto.PublicKey, error) {
+	for {
+		p.mu.Lock()
+		if key, ok := p.lookupKey(kid); ok {
+			p.mu.Unlock()
+			return key, nil
+		}
+
+		if fetching := p.keysFetching; fetching != nil {
+			p.mu.Unlock()
+			select {
+			case <-fetching:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		if time.Since(p.keysFetchedAt) < jwksRefreshInterval {
+			p.mu.Unlock()
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+
+		done := make(chan struct{})
+		p.keysFetching = done
+		p.mu.Unlock()
+
+		keys, err := p.fetchKeys(ctx, doc)
+
+		p.mu.Lock()
+		if err == nil {
+			p.keys = keys
+			p.keysFetchedAt = time.Now()
+		}
+		p.keysFetching = nil
+		close(done)
+		p.mu.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// fetchKeys downloads the issuer's JWKS and returns its signing keys by kid.
+func (p *provider) fetchKeys(ctx context.Context, doc *discoveryDocument) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, doc.JwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set auth.JSONWebKeySet
+	status, err := p.do(req, &set)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("jwks endpoint returned %d", status)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.PublicKey()
+		if err != nil {
+			log.Warnf("[OIDC] fetchKeys - skipping key %q: %v", jwk.Kid, err)
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+// lookupKey finds a cached key by kid. A token without kid is accepted when the issuer
+// publishes a single key. The caller must hold p.mu.
+func (p *provider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// discover fetches and caches the issuer's discovery document. A failed fetch is retried on
+// the next call.
+func (p *provider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	cached := p.discovery
+	p.mu.Unlock()
+
+	if cached != nil {
+		return cached, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.issuerURL+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc discoveryDocument
+	status, err := p.do(req, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("discovery endpoint returned %d", status)
+	}
+
+	if strings.TrimSuffix(doc.Issuer, "/") != p.issuerURL {
+		return nil, fmt.Errorf("discovery document issuer %q does not match %q", doc.Issuer, p.issuerURL)
+	}
+
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JwksURI == "" {
+		return nil, fmt.Errorf("discovery document is missing endpoints")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery == nil {
+		p.discovery = &doc
+	}
+
+	return p.discovery, nil
+}
+
+// do sends the request and decodes a JSON response body into dst.
+func (p *provider) do(req *http.Request, dst interface{}) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, err
+	}
+
+	if err = json.Unmarshal(body, dst); err != nil {
+		return resp.StatusCode, fmt.Errorf("decode response of %s: %w", req.URL, err)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// NewProvider creates a port.IdentityProvider for the issuer at OIDC_ISSUER_URL.
+// The discovery document and the issuer's keys are fetched on first use, so the issuer
+// does not have to be reachable when the server starts.
+func NewProvider(cfg *config.Config) port.IdentityProvider {
+	return &provider{
+		issuerURL:    strings.TrimSuffix(cfg.App.OidcIssuerURL, "/"),
+		clientID:     cfg.App.OidcClientID,
+		clientSecret: cfg.App.OidcClientSecret,
+		redirectURL:  cfg.App.OidcRedirectURL,
+		scopes:       cfg.App.OidcScopes,
+		leeway:       cfg.App.JwtLeeway,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/domain/model"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, req entity.APIKeyEntity) (*entity.APIKeyEntity, error)
+	GetAPIKeysByUserID(ctx context.Context, userID int64) ([]entity.APIKeyEntity, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKeyEntity, error)
+	RevokeAPIKey(ctx context.Context, id, userID int64) error
+	TouchAPIKey(ctx context.Context, id int64, minInterval time.Duration) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// CreateAPIKey implements APIKeyRepository.
+func (a *apiKeyRepository) CreateAPIKey(ctx context.Context, req entity.APIKeyEntity) (*entity.APIKeyEntity, error) {
+	modelKey := model.APIKey{
+		UserID:    req.UserID,
+		Name:      req.Name,
+		Prefix:    req.Prefix,
+		KeyHash:   req.KeyHash,
+		Scopes:    strings.Join(req.Scopes, ","),
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	err := a.db.Create(&modelKey).Error
+	if err != nil {
+		code := "[REPOSITORY] CreateAPIKey - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resp := toAPIKeyEntity(modelKey)
+	return &resp, nil
+}
+
+// GetAPIKeysByUserID implements APIKeyRepository.
+// Revoked keys are included so the owner can see their history.
+func (a *apiKeyRepository) GetAPIKeysByUserID(ctx context.Context, userID int64) ([]entity.APIKeyEntity, error) {
+	var modelKeys []model.APIKey
+
+	err := a.db.Where("user_id = ?", userID).Order("created_at desc").Find(&modelKeys).Error
+	if err != nil {
+		code := "[REPOSITORY] GetAPIKeysByUserID - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := []entity.APIKeyEntity{}
+	for _, val := range modelKeys {
+		resps = append(resps, toAPIKeyEntity(val))
+	}
+
+	return resps, nil
+}
+
+// GetAPIKeyByPrefix implements APIKeyRepository.
+func (a *apiKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKeyEntity, error) {
+	var modelKey model.APIKey
+
+	err := a.db.Where("prefix = ?", prefix).First(&modelKey).Error
+	if err != nil {
+		code := "[REPOSITORY] GetAPIKeyByPrefix - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrAPIKeyNotFound)
+	}
+
+	resp := toAPIKeyEntity(modelKey)
+	return &resp, nil
+}
+
+// RevokeAPIKey implements APIKeyRepository.
+// ErrAPIKeyNotFound is returned when the user has no active key with the given ID.
+func (a *apiKeyRepository) RevokeAPIKey(ctx context.Context, id, userID int64) error {
+	result := a.db.Model(&model.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		code := "[REPOSITORY] RevokeAPIKey - 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// TouchAPIKey implements APIKeyRepository.
+// last_used_at is only written when it is older than minInterval, so busy keys do not
+// cause a write on every request.
+func (a *apiKeyRepository) TouchAPIKey(ctx context.Context, id int64, minInterval time.Duration) error {
+	now := time.Now()
+	err := a.db.Model(&model.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-minInterval)).
+		Update("last_used_at", now).Error
+	if err != nil {
+		code := "[REPOSITORY] TouchAPIKey - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+func toAPIKeyEntity(modelKey model.APIKey) entity.APIKeyEntity {
+	scopes := []string{}
+	if modelKey.Scopes != "" {
+		scopes = strings.Split(modelKey.Scopes, ",")
+	}
+
+	return entity.APIKeyEntity{
+		ID:         modelKey.ID,
+		UserID:     modelKey.UserID,
+		Name:       modelKey.Name,
+		Prefix:     modelKey.Prefix,
+		KeyHash:    modelKey.KeyHash,
+		Scopes:     scopes,
+		ExpiresAt:  modelKey.ExpiresAt,
+		LastUsedAt: modelKey.LastUsedAt,
+		RevokedAt:  modelKey.RevokedAt,
+		CreatedAt:  modelKey.CreatedAt,
+	}
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
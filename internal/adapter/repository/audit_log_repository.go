@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/domain/model"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	GetAuditLogs(ctx context.Context, query entity.AuditLogQueryString) ([]entity.AuditLogEntity, int64, int64, error)
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+// auditState is the audited snapshot of a row, keyed by column name.
+type auditState map[string]interface{}
+
+// GetAuditLogs implements AuditLogRepository.
+// Entries are returned newest first.
+func (a *auditLogRepository) GetAuditLogs(ctx context.Context, query entity.AuditLogQueryString) ([]entity.AuditLogEntity, int64, int64, error) {
+	var modelAuditLogs []model.AuditLog
+	var countData int64
+
+	offset := (query.Page - 1) * query.Limit
+
+	sqlMain := a.db.Model(&model.AuditLog{})
+
+	if query.ActorID > 0 {
+		sqlMain = sqlMain.Where("actor_id = ?", query.ActorID)
+	}
+
+	if query.Action != "" {
+		sqlMain = sqlMain.Where("action = ?", query.Action)
+	}
+
+	if query.EntityType != "" {
+		sqlMain = sqlMain.Where("entity_type = ?", query.EntityType)
+	}
+
+	if query.EntityID != "" {
+		sqlMain = sqlMain.Where("entity_id = ?", query.EntityID)
+	}
+
+	if query.From != nil {
+		sqlMain = sqlMain.Where("created_at >= ?", *query.From)
+	}
+
+	if query.To != nil {
+		sqlMain = sqlMain.Where("created_at < ?", *query.To)
+	}
+
+	err := sqlMain.Count(&countData).Error
+	if err != nil {
+		code := "[REPOSITORY] GetAuditLogs - 1"
+		log.Errorw(code, err)
+		return nil, 0, 0, err
+	}
+
+	totalPages := int(math.Ceil(float64(countData) / float64(query.Limit)))
+
+	err = sqlMain.
+		Order("created_at desc, id desc").
+		Limit(query.Limit).
+		Offset(offset).
+		Find(&modelAuditLogs).Error
+	if err != nil {
+		code := "[REPOSITORY] GetAuditLogs - 2"
+		log.Errorw(code, err)
+		return nil, 0, 0, err
+	}
+
+	resps := []entity.AuditLogEntity{}
+	for _, v := range modelAuditLogs {
+		resp := entity.AuditLogEntity{
+			ID:         v.ID,
+			ActorID:    v.ActorID,
+			APIKeyID:   v.APIKeyID,
+			Action:     v.Action,
+			EntityType: v.EntityType,
+			EntityID:   v.EntityID,
+			IP:         v.IP,
+			RequestID:  v.RequestID,
+			CreatedAt:  v.CreatedAt,
+		}
+
+		if v.Before != nil {
+			resp.Before = []byte(*v.Before)
+		}
+
+		if v.After != nil {
+			resp.After = []byte(*v.After)
+		}
+
+		resps = append(resps, resp)
+	}
+
+	return resps, countData, int64(totalPages), nil
+}
+
+// writeAuditLog appends an entry to the audit log using tx, so it is committed or rolled back
+// together with the change it records. The actor is read from ctx. For updates only the fields
+// that differ between before and after are kept, and updates that changed nothing are skipped.
+func writeAuditLog(ctx context.Context, tx *gorm.DB, action, entityType string, entityID int64, before, after auditState) error {
+	return writeAuditLogByKey(ctx, tx, action, entityType, strconv.FormatInt(entityID, 10), before, after)
+}
+
+// writeAuditLogByKey is writeAuditLog for entities keyed by something other than an ID,
+// such as roles.
+func writeAuditLogByKey(ctx context.Context, tx *gorm.DB, action, entityType, entityID string, before, after auditState) error {
+	actor := entity.AuditActorFromContext(ctx)
+	before, after = auditDiff(before, after)
+	if before != nil && after != nil && len(after) == 0 {
+		return nil
+	}
+
+	modelAuditLog := model.AuditLog{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		IP:         actor.IP,
+		RequestID:  actor.RequestID,
+		CreatedAt:  time.Now(),
+	}
+
+	if actor.UserID > 0 {
+		modelAuditLog.ActorID = &actor.UserID
+	}
+
+	if actor.APIKeyID > 0 {
+		modelAuditLog.APIKeyID = &actor.APIKeyID
+	}
+
+	if before != nil {
+		raw, err := json.Marshal(before)
+		if err != nil {
+			return err
+		}
+		encoded := string(raw)
+		modelAuditLog.Before = &encoded
+	}
+
+	if after != nil {
+		raw, err := json.Marshal(after)
+		if err != nil {
+			return err
+		}
+		encoded := string(raw)
+		modelAuditLog.After = &encoded
+	}
+
+	if err := tx.Create(&modelAuditLog).Error; err != nil {
+		code := "[REPOSITORY] writeAuditLog - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// auditDiff drops the fields before and after have in common. Creations and deletions, where
+// one side is nil, are kept whole.
+func auditDiff(before, after auditState) (auditState, auditState) {
+	if before == nil || after == nil {
+		return before, after
+	}
+
+	changedBefore := auditState{}
+	changedAfter := auditState{}
+	for key, value := range after {
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, value) {
+			changedBefore[key] = before[key]
+			changedAfter[key] = value
+		}
+	}
+
+	for key, old := range before {
+		if _, ok := after[key]; !ok {
+			changedBefore[key] = old
+			changedAfter[key] = nil
+		}
+	}
+
+	return changedBefore, changedAfter
+}
+
+func categoryAuditState(category model.Category) auditState {
+	return auditState{
+		"title":         category.Title,
+		"slug":          category.Slug,
+		"created_by_id": category.CreatedByID,
+	}
+}
+
+func tagAuditState(tag model.Tag) auditState {
+	return auditState{
+		"title": tag.Title,
+		"slug":  tag.Slug,
+	}
+}
+
+func contentAuditState(content model.Content) auditState {
+	return auditState{
+		"title":            content.Title,
+		"slug":             content.Slug,
+		"excerpt":          content.Excerpt,
+		"description":      content.Description,
+		"image":            content.Image,
+		"tags":             content.Tags,
+		"status":           content.Status,
+		"rejection_reason": content.RejectionReason,
+		"publish_at":       content.PublishAt,
+		"unpublish_at":     content.UnpublishAt,
+		"category_id":      content.CategoryID,
+		"created_by_id":    content.CreatedByID,
+	}
+}
+
+// userAuditState leaves the password hash out; password changes are recorded by their action.
+func userAuditState(user model.User) auditState {
+	return auditState{
+		"name":      user.Name,
+		"email":     user.Email,
+		"role":      user.Role,
+		"is_active": user.IsActive,
+	}
+}
+
+func roleAuditState(role model.Role) auditState {
+	return auditState{
+		"mfa_required": role.MfaRequired,
+	}
+}
+
+// invitationAuditState leaves the token hash out.
+func invitationAuditState(invitation model.Invitation) auditState {
+	state := auditState{
+		"email":      invitation.Email,
+		"role":       invitation.Role,
+		"invited_by": invitation.InvitedBy,
+		"user_id":    invitation.UserID,
+		"expires_at": invitation.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+
+	if invitation.AcceptedAt != nil {
+		state["accepted_at"] = invitation.AcceptedAt.UTC().Format(time.RFC3339)
+	}
+
+	if invitation.RevokedAt != nil {
+		state["revoked_at"] = invitation.RevokedAt.UTC().Format(time.RFC3339)
+	}
+
+	return state
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
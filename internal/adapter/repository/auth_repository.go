@@ -2,18 +2,29 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"portal-blog/internal/core/domain/entity"
 	"portal-blog/internal/core/domain/model"
+	"time"
 
 	"github.com/gofiber/fiber/v2/log"
 	"gorm.io/gorm"
 )
 
-var err error
-var code string
+
+// ErrRefreshTokenRevoked is returned when a refresh token was revoked
+// (rotated or logged out) between being read and being rotated.
+var ErrRefreshTokenRevoked = errors.New("refresh token already revoked")
 
 type AuthRepository interface {
 	GetUserByEmail(ctx context.Context, req entity.LoginRequest) (*entity.UserEntity, error)
+	GetUserByID(ctx context.Context, id int64) (*entity.UserEntity, error)
+
+	CreateRefreshToken(ctx context.Context, req entity.RefreshTokenEntity) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*entity.RefreshTokenEntity, error)
+	RotateRefreshToken(ctx context.Context, oldID int64, req entity.RefreshTokenEntity) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	GetRefreshTokensIssuedSince(ctx context.Context, userID int64, since time.Time) ([]entity.RefreshTokenEntity, error)
 }
 
 type authRepository struct {
@@ -28,15 +39,15 @@ type authRepository struct {
 //
 // Returns:
 //   - *entity.UserEntity: A pointer to the UserEntity if found, containing user details.
-//   - error: An error if the user is not found or if there's a database error, nil otherwise.
+//   - error: ErrUserNotFound if the user is not found, any other database error, nil otherwise.
 func (a *authRepository) GetUserByEmail(ctx context.Context, req entity.LoginRequest) (*entity.UserEntity, error) {
     var modelUser model.User
 
-    err = a.db.Where("email = ?", req.Email).First(&modelUser).Error
+    err := a.db.Where("email = ?", req.Email).First(&modelUser).Error
     if err != nil {
-        code = "[REPOSITORY] GetUserByEmail - 1"
+        code := "[REPOSITORY] GetUserByEmail - 1"
         log.Errorw(code, err)
-        return nil, err
+        return nil, translateError(err, ErrUserNotFound)
     }
 
     resp := entity.UserEntity{
@@ -44,11 +55,209 @@ func (a *authRepository) GetUserByEmail(ctx context.Context, req entity.LoginReq
         Name: modelUser.Name,
         Email: modelUser.Email,
         Password: modelUser.Password,
+        Role: modelUser.Role,
+        IsActive: modelUser.IsActive,
     }
 
     return &resp, nil
 }
 
+// GetUserByID retrieves a user from the database based on their ID.
+//
+// Parameters:
+//   - ctx: A context.Context for handling timeouts and cancellations.
+//   - id: The ID of the user to retrieve.
+//
+// Returns:
+//   - *entity.UserEntity: A pointer to the UserEntity if found, containing user details.
+//   - error: ErrUserNotFound if the user is not found, any other database error, nil otherwise.
+func (a *authRepository) GetUserByID(ctx context.Context, id int64) (*entity.UserEntity, error) {
+	var modelUser model.User
+
+	err := a.db.Where("id = ?", id).First(&modelUser).Error
+	if err != nil {
+		code := "[REPOSITORY] GetUserByID - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrUserNotFound)
+	}
+
+	resp := entity.UserEntity{
+		ID:       modelUser.ID,
+		Name:     modelUser.Name,
+		Email:    modelUser.Email,
+		Role:     modelUser.Role,
+		IsActive: modelUser.IsActive,
+	}
+
+	return &resp, nil
+}
+
+// CreateRefreshToken persists a new refresh token. Only the token hash is stored.
+//
+// Parameters:
+//   - ctx: A context.Context for handling timeouts and cancellations.
+//   - req: An entity.RefreshTokenEntity describing the token to store.
+//
+// Returns:
+//   - error: An error if the insert fails, nil otherwise.
+func (a *authRepository) CreateRefreshToken(ctx context.Context, req entity.RefreshTokenEntity) error {
+	modelToken := model.RefreshToken{
+		UserID:         req.UserID,
+		TokenHash:      req.TokenHash,
+		FamilyID:       req.FamilyID,
+		ExpiresAt:      req.ExpiresAt,
+		AccessTokenJTI: req.AccessTokenJTI,
+	}
+
+	err := a.db.Create(&modelToken).Error
+	if err != nil {
+		code := "[REPOSITORY] CreateRefreshToken - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetRefreshTokenByHash retrieves a refresh token by the SHA-256 hash of its value.
+//
+// Parameters:
+//   - ctx: A context.Context for handling timeouts and cancellations.
+//   - tokenHash: The hex encoded hash of the opaque refresh token.
+//
+// Returns:
+//   - *entity.RefreshTokenEntity: The stored token, including its revocation state.
+//   - error: ErrRefreshTokenNotFound if no token matches, or any other database error.
+func (a *authRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*entity.RefreshTokenEntity, error) {
+	var modelToken model.RefreshToken
+
+	err := a.db.Where("token_hash = ?", tokenHash).First(&modelToken).Error
+	if err != nil {
+		code := "[REPOSITORY] GetRefreshTokenByHash - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrRefreshTokenNotFound)
+	}
+
+	resp := toRefreshTokenEntity(modelToken)
+
+	return &resp, nil
+}
+
+// RotateRefreshToken stores a replacement refresh token and revokes the old one in a single transaction.
+//
+// The old token is only revoked when it is still active, so two concurrent refreshes with the
+// same token cannot both succeed; the loser receives ErrRefreshTokenRevoked.
+//
+// Parameters:
+//   - ctx: A context.Context for handling timeouts and cancellations.
+//   - oldID: The ID of the refresh token being exchanged.
+//   - req: The replacement token, in the same family as the old one.
+//
+// Returns:
+//   - error: ErrRefreshTokenRevoked if the old token is no longer active, or any database error.
+func (a *authRepository) RotateRefreshToken(ctx context.Context, oldID int64, req entity.RefreshTokenEntity) error {
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		modelToken := model.RefreshToken{
+			UserID:         req.UserID,
+			TokenHash:      req.TokenHash,
+			FamilyID:       req.FamilyID,
+			ExpiresAt:      req.ExpiresAt,
+			AccessTokenJTI: req.AccessTokenJTI,
+		}
+
+		if err := tx.Create(&modelToken).Error; err != nil {
+			code := "[REPOSITORY] RotateRefreshToken - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", oldID).
+			Updates(map[string]interface{}{
+				"revoked_at":     time.Now(),
+				"replaced_by_id": modelToken.ID,
+			})
+		if result.Error != nil {
+			code := "[REPOSITORY] RotateRefreshToken - 2"
+			log.Errorw(code, result.Error)
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			code := "[REPOSITORY] RotateRefreshToken - 3"
+			log.Errorw(code, ErrRefreshTokenRevoked)
+			return ErrRefreshTokenRevoked
+		}
+
+		return nil
+	})
+}
+
+// RevokeRefreshTokenFamily revokes every still-active refresh token that belongs to the given family.
+//
+// Parameters:
+//   - ctx: A context.Context for handling timeouts and cancellations.
+//   - familyID: The family shared by all tokens rotated from the same login.
+//
+// Returns:
+//   - error: An error if the update fails, nil otherwise.
+func (a *authRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	err := a.db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		code := "[REPOSITORY] RevokeRefreshTokenFamily - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetRefreshTokensIssuedSince lists the refresh tokens of a user created after the given time,
+// including revoked ones. The access tokens issued alongside them may still be valid.
+//
+// Parameters:
+//   - ctx: A context.Context for handling timeouts and cancellations.
+//   - userID: The owner of the tokens.
+//   - since: Only tokens created after this time are returned.
+//
+// Returns:
+//   - []entity.RefreshTokenEntity: The matching tokens.
+//   - error: An error if the query fails, nil otherwise.
+func (a *authRepository) GetRefreshTokensIssuedSince(ctx context.Context, userID int64, since time.Time) ([]entity.RefreshTokenEntity, error) {
+	var modelTokens []model.RefreshToken
+
+	err := a.db.Where("user_id = ? AND created_at > ?", userID, since).Find(&modelTokens).Error
+	if err != nil {
+		code := "[REPOSITORY] GetRefreshTokensIssuedSince - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	var resps []entity.RefreshTokenEntity
+	for _, v := range modelTokens {
+		resps = append(resps, toRefreshTokenEntity(v))
+	}
+
+	return resps, nil
+}
+
+// toRefreshTokenEntity maps a refresh token row to its entity.
+func toRefreshTokenEntity(modelToken model.RefreshToken) entity.RefreshTokenEntity {
+	return entity.RefreshTokenEntity{
+		ID:             modelToken.ID,
+		UserID:         modelToken.UserID,
+		TokenHash:      modelToken.TokenHash,
+		FamilyID:       modelToken.FamilyID,
+		ExpiresAt:      modelToken.ExpiresAt,
+		RevokedAt:      modelToken.RevokedAt,
+		ReplacedByID:   modelToken.ReplacedByID,
+		AccessTokenJTI: modelToken.AccessTokenJTI,
+		CreatedAt:      modelToken.CreatedAt,
+	}
+}
+
 // NewAuthRepository creates and returns a new instance of AuthRepository.
 //
 // Parameters:
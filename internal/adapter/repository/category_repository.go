@@ -26,9 +26,9 @@ type categoryRepository struct {
 // CreateCategory implements CategoryRepository.
 func (c *categoryRepository) CreateCategory(ctx context.Context, req entity.CategoryEntity) error {
 	var countSlug int64
-	err = c.db.Table("categories").Where("slug = ?", req.Slug).Count(&countSlug).Error
+	err := c.db.Table("categories").Where("slug = ?", req.Slug).Count(&countSlug).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		code = "[REPOSITORY] CreateCategory - 1"
+		code := "[REPOSITORY] CreateCategory - 1"
 		log.Errorw(code, err)
 		return err
 	}
@@ -44,11 +44,17 @@ func (c *categoryRepository) CreateCategory(ctx context.Context, req entity.Cate
 		CreatedByID: req.User.ID,
 	}
 
-	err = c.db.Create(&modelCategory).Error
+	err = c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&modelCategory).Error; err != nil {
+			code := "[REPOSITORY] CreateCategory - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionCreate, entity.AuditEntityCategory, modelCategory.ID, nil, categoryAuditState(modelCategory))
+	})
 	if err != nil {
-		code = "[REPOSITORY] CreateCategory - 2"
-		log.Errorw(code, err)
-		return err
+		return translateError(err, nil)
 	}
 
 	return nil
@@ -57,22 +63,35 @@ func (c *categoryRepository) CreateCategory(ctx context.Context, req entity.Cate
 // DeleteCategoryById implements CategoryRepository.
 func (c *categoryRepository) DeleteCategoryById(ctx context.Context, id int64) error {
 	var count int64
-	err = c.db.Table("contents").Where("category_id = ?", id).Count(&count).Error
+	err := c.db.Table("contents").Where("category_id = ?", id).Count(&count).Error
 	if err != nil {
-		code = "[REPOSITORY] DeleteCategoryById - 1"
+		code := "[REPOSITORY] DeleteCategoryById - 1"
 		log.Errorw(code, err)
 		return err
 	}
 
 	if count > 0 {
-		return errors.New("cannot delete a category that has associate contents")
+		return ErrCategoryHasContents
 	}
 
-	err = c.db.Where("id = ?", id).Delete(&model.Category{}).Error
+	err = c.db.Transaction(func(tx *gorm.DB) error {
+		var before model.Category
+		if err := tx.Where("id = ?", id).Take(&before).Error; err != nil {
+			code := "[REPOSITORY] DeleteCategoryById - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := tx.Where("id = ?", id).Delete(&model.Category{}).Error; err != nil {
+			code := "[REPOSITORY] DeleteCategoryById - 3"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionDelete, entity.AuditEntityCategory, id, categoryAuditState(before), nil)
+	})
 	if err != nil {
-		code = "[REPOSITORY] DeleteCategoryById - 2"
-		log.Errorw(code, err)
-		return err
+		return translateError(err, ErrCategoryNotFound)
 	}
 
 	return nil
@@ -82,9 +101,9 @@ func (c *categoryRepository) DeleteCategoryById(ctx context.Context, id int64) e
 // EditCategoryById implements CategoryRepository.
 func (c *categoryRepository) EditCategoryById(ctx context.Context, req entity.CategoryEntity) error {
 	var countSlug int64
-	err = c.db.Table("categories").Where("slug = ?", req.Slug).Count(&countSlug).Error
+	err := c.db.Table("categories").Where("slug = ?", req.Slug).Count(&countSlug).Error
 	if err != nil {
-		code = "[REPOSITORY] EditCategoryById - 1"
+		code := "[REPOSITORY] EditCategoryById - 1"
 		log.Errorw(code, err)
 		return err
 	}
@@ -102,11 +121,30 @@ func (c *categoryRepository) EditCategoryById(ctx context.Context, req entity.Ca
 		CreatedByID: req.User.ID,
 	}
 
-	err = c.db.Where("id = ?", req.ID).Updates(&modelCategory).Error
+	err = c.db.Transaction(func(tx *gorm.DB) error {
+		var before, after model.Category
+		if err := tx.Where("id = ?", req.ID).Take(&before).Error; err != nil {
+			code := "[REPOSITORY] EditCategoryById - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := tx.Where("id = ?", req.ID).Updates(&modelCategory).Error; err != nil {
+			code := "[REPOSITORY] EditCategoryById - 3"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := tx.Where("id = ?", req.ID).Take(&after).Error; err != nil {
+			code := "[REPOSITORY] EditCategoryById - 4"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionUpdate, entity.AuditEntityCategory, req.ID, categoryAuditState(before), categoryAuditState(after))
+	})
 	if err != nil {
-		code = "[REPOSITORY] EditCategoryById - 2"
-		log.Errorw(code, err)
-		return err
+		return translateError(err, ErrCategoryNotFound)
 	}
 
 	return nil
@@ -123,21 +161,14 @@ func (c *categoryRepository) EditCategoryById(ctx context.Context, req entity.Ca
 func (c *categoryRepository) GetCategories(ctx context.Context) ([]entity.CategoryEntity, error) {
 	var modelCategories []*model.Category
 
-	err = c.db.Order("created_at desc").Preload("User").Find(&modelCategories).Error
+	err := c.db.Order("created_at desc").Preload("User").Find(&modelCategories).Error
 	if err != nil {
-		code = "[REPOSITORY] GetCategories - 1"
-		log.Errorw(code, err)
-		return nil, err
-	}
-
-	if len(modelCategories) == 0 {
-		code = "[REPOSITORY] GetCategories - 2"
-		err = errors.New("data not found")
+		code := "[REPOSITORY] GetCategories - 1"
 		log.Errorw(code, err)
 		return nil, err
 	}
 
-	var resps []entity.CategoryEntity
+	resps := []entity.CategoryEntity{}
 	for _, v := range modelCategories {
 		resps = append(resps, entity.CategoryEntity{
 			ID:    v.ID,
@@ -159,11 +190,11 @@ func (c *categoryRepository) GetCategories(ctx context.Context) ([]entity.Catego
 func (c *categoryRepository) GetCategoryById(ctx context.Context, id int64) (*entity.CategoryEntity, error) {
 	var categoryModel model.Category
 
-	err = c.db.Where("id = ?", id).Preload("User").First(&categoryModel).Error
+	err := c.db.Where("id = ?", id).Preload("User").First(&categoryModel).Error
 	if err != nil {
-		code = "[REPOSITORY] GetCategoryById - 1"
+		code := "[REPOSITORY] GetCategoryById - 1"
 		log.Errorw(code, err)
-		return nil, err
+		return nil, translateError(err, ErrCategoryNotFound)
 	}
 
 	categoryEntity := &entity.CategoryEntity{
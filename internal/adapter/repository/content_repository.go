@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
-	"fmt"
 	"math"
 	"portal-blog/internal/core/domain/entity"
 	"portal-blog/internal/core/domain/model"
+	"portal-blog/lib/conv"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2/log"
 	"gorm.io/gorm"
@@ -16,34 +18,70 @@ import (
 type ContentRepository interface {
 	GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, int64, error)
 	GetContentByID(ctx context.Context, id int64) (*entity.ContentEntity, error)
+	GetContentBySlug(ctx context.Context, slug string) (*entity.ContentEntity, error)
+	GetContentIDBySlugRedirect(ctx context.Context, slug string) (int64, error)
+	GetTakenContentSlugs(ctx context.Context, base string, contentID int64) ([]string, error)
+	SyncSearchLanguage(ctx context.Context) (int64, error)
 	CreateContent(ctx context.Context, req entity.ContentEntity) error
-	UpdateContent(ctx context.Context, req entity.ContentEntity) error
+	UpdateContent(ctx context.Context, req entity.ContentEntity, editableStatuses []string) error
 	DeleteContent(ctx context.Context, id int64) error
+	UpdateContentStatus(ctx context.Context, id int64, from, to, rejectionReason string) error
+	GetContentsDueToPublish(ctx context.Context, now time.Time) ([]entity.ContentEntity, error)
+	GetContentsDueToUnpublish(ctx context.Context, now time.Time) ([]entity.ContentEntity, error)
+
+	// Revisions
+	GetContentRevisions(ctx context.Context, contentID int64) ([]entity.ContentRevisionEntity, error)
+	GetContentRevision(ctx context.Context, contentID int64, revision int) (*entity.ContentRevisionEntity, error)
+	RestoreContentRevision(ctx context.Context, contentID int64, revision int) (*entity.ContentRevisionEntity, error)
 }
 
 type contentRepository struct {
-	db *gorm.DB
+	db             *gorm.DB
+	searchLanguage string
 }
 
+// headlineOptions shape the search snippets: a few short fragments of the description with the
+// matches wrapped in <mark>.
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \""
+
 // CreateContent implements ContentRepository.
+// The new content is stored as its first revision.
 func (c *contentRepository) CreateContent(ctx context.Context, req entity.ContentEntity) error {
-	tags := strings.Join(req.Tags, ",")
 	modelContent := model.Content{
-		Title:       req.Title,
-		Excerpt:     req.Excerpt,
-		Description: req.Description,
-		Image:       req.Image,
-		Tags:        tags,
-		Status:      req.Status,
-		CategoryID:  req.CategoryID,
-		CreatedByID: req.CreatedByID,
+		Title:          req.Title,
+		Slug:           req.Slug,
+		Excerpt:        req.Excerpt,
+		Description:    req.Description,
+		Image:          req.Image,
+		Status:         req.Status,
+		PublishAt:      req.PublishAt,
+		SearchLanguage: c.searchLanguage,
+		UnpublishAt:    req.UnpublishAt,
+		CategoryID:     req.CategoryID,
+		CreatedByID:    req.CreatedByID,
 	}
 
-	err = c.db.Create(&modelContent).Error
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&modelContent).Error; err != nil {
+			code := "[REPOSITORY] CreateContent - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		tags, err := setContentTags(tx, modelContent.ID, req.Tags)
+		if err != nil {
+			return err
+		}
+		modelContent.Tags = tags
+
+		if _, err := writeContentRevision(ctx, tx, modelContent, nil); err != nil {
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionCreate, entity.AuditEntityContent, modelContent.ID, nil, contentAuditState(modelContent))
+	})
 	if err != nil {
-		code := "[REPOSITORY] CreateContent - 1"
-		log.Errorw(code, err)
-		return err
+		return translateError(err, nil)
 	}
 
 	return nil
@@ -51,11 +89,24 @@ func (c *contentRepository) CreateContent(ctx context.Context, req entity.Conten
 
 // DeleteContent implements ContentRepository.
 func (c *contentRepository) DeleteContent(ctx context.Context, id int64) error {
-	err = c.db.Where("id = ?", id).Delete(&model.Content{}).Error
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		var before model.Content
+		if err := tx.Where("id = ?", id).Take(&before).Error; err != nil {
+			code := "[REPOSITORY] DeleteContent - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := tx.Where("id = ?", id).Delete(&model.Content{}).Error; err != nil {
+			code := "[REPOSITORY] DeleteContent - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionDelete, entity.AuditEntityContent, id, contentAuditState(before), nil)
+	})
 	if err != nil {
-		code := "[REPOSITORY] DeleteContent - 1"
-		log.Errorw(code, err)
-		return err
+		return translateError(err, ErrContentNotFound)
 	}
 
 	return nil
@@ -65,59 +116,133 @@ func (c *contentRepository) DeleteContent(ctx context.Context, id int64) error {
 func (c *contentRepository) GetContentByID(ctx context.Context, id int64) (*entity.ContentEntity, error) {
 	var modelContent model.Content
 
-	err = c.db.Where("id = ?", id).Preload(clause.Associations).First(&modelContent).Error
+	err := c.db.Where("id = ?", id).Preload(clause.Associations).First(&modelContent).Error
 	if err != nil {
 		code := "[REPOSITORY] GetContentByID - 1"
 		log.Errorw(code, err)
-		return nil, err
+		return nil, translateError(err, ErrContentNotFound)
 	}
 
-	content := entity.ContentEntity{
-		ID:          modelContent.ID,
-		Title:       modelContent.Title,
-		Excerpt:     modelContent.Excerpt,
-		Description: modelContent.Description,
-		Image:       modelContent.Image,
-		Tags:        strings.Split(modelContent.Tags, ","),
-		Status:      modelContent.Status,
-		CategoryID:  modelContent.CategoryID,
-		CreatedByID: modelContent.CreatedByID,
-		CreatedAt:   modelContent.CreatedAt,
-		Category: entity.CategoryEntity{
-			ID:    modelContent.Category.ID,
-			Title: modelContent.Category.Title,
-			Slug:  modelContent.Category.Slug,
-		},
-		User: entity.UserEntity{
-			ID:   modelContent.User.ID,
-			Name: modelContent.User.Name,
-		},
+	content := contentEntity(modelContent)
+
+	return &content, nil
+}
+
+// GetContentBySlug implements ContentRepository.
+// Only current slugs are matched; see GetContentIDBySlugRedirect for earlier ones.
+func (c *contentRepository) GetContentBySlug(ctx context.Context, slug string) (*entity.ContentEntity, error) {
+	var modelContent model.Content
+
+	err := c.db.Where("slug = ?", slug).Preload(clause.Associations).Take(&modelContent).Error
+	if err != nil {
+		code := "[REPOSITORY] GetContentBySlug - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrContentNotFound)
 	}
 
+	content := contentEntity(modelContent)
+
 	return &content, nil
 }
 
+// GetContentIDBySlugRedirect implements ContentRepository.
+// It returns the ID of the content that used to have slug.
+func (c *contentRepository) GetContentIDBySlugRedirect(ctx context.Context, slug string) (int64, error) {
+	var redirect model.ContentSlugRedirect
+
+	err := c.db.Where("slug = ?", slug).Take(&redirect).Error
+	if err != nil {
+		code := "[REPOSITORY] GetContentIDBySlugRedirect - 1"
+		log.Errorw(code, err)
+		return 0, translateError(err, ErrContentNotFound)
+	}
+
+	return redirect.ContentID, nil
+}
+
+// GetTakenContentSlugs implements ContentRepository.
+// It returns the slugs starting with base that are in use by other contents than contentID,
+// either as their current slug or as a redirect.
+func (c *contentRepository) GetTakenContentSlugs(ctx context.Context, base string, contentID int64) ([]string, error) {
+	var slugs []string
+	pattern := escapeLike(base) + "%"
+
+	err := c.db.Raw(
+		"SELECT slug FROM contents WHERE slug LIKE ? AND id <> ? UNION SELECT slug FROM content_slug_redirects WHERE slug LIKE ? AND content_id <> ?",
+		pattern, contentID, pattern, contentID,
+	).Scan(&slugs).Error
+	if err != nil {
+		code := "[REPOSITORY] GetTakenContentSlugs - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return slugs, nil
+}
+
+// SyncSearchLanguage implements ContentRepository.
+// Contents indexed with another text search configuration than the configured one are
+// re-indexed. It returns how many were; an unknown configuration is an error.
+func (c *contentRepository) SyncSearchLanguage(ctx context.Context) (int64, error) {
+	result := c.db.Model(&model.Content{}).
+		Where("search_language <> ?::regconfig", c.searchLanguage).
+		UpdateColumn("search_language", gorm.Expr("?::regconfig", c.searchLanguage))
+	if result.Error != nil {
+		code := "[REPOSITORY] SyncSearchLanguage - 1"
+		log.Errorw(code, result.Error)
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
 // GetContents implements ContentRepository.
+// Search matches the full text index with websearch_to_tsquery syntax: words, "quoted phrases",
+// OR and -excluded words. Ordering by entity.OrderByRelevance ranks the matches, and with
+// Highlight each result carries a snippet of its description.
 func (c *contentRepository) GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, int64, error) {
 	var modelContents []*model.Content
 	var countData int64
 
-	order := fmt.Sprintf("%s %s", query.OrderBy, query.OrderType)
+	// OrderBy and OrderType are checked by the service; the column is still quoted.
+	var order interface{} = clause.OrderByColumn{Column: clause.Column{Name: query.OrderBy}, Desc: query.OrderType == "desc"}
 	offset := (query.Page - 1) * query.Limit
-	status := ""
-	if query.Status != "" {
-		status = query.Status
+
+	sqlMain := c.db.Preload(clause.Associations)
+
+	if query.Search != "" {
+		sqlMain = sqlMain.Where("search_vector @@ websearch_to_tsquery(?::regconfig, ?)", c.searchLanguage, query.Search)
+
+		if query.OrderBy == entity.OrderByRelevance {
+			order = clause.Expr{
+				SQL:  "ts_rank_cd(search_vector, websearch_to_tsquery(?::regconfig, ?)) DESC, created_at DESC",
+				Vars: []interface{}{c.searchLanguage, query.Search},
+			}
+		}
+	} else if query.OrderBy == entity.OrderByRelevance {
+		order = "created_at desc"
 	}
 
-	sqlMain := c.db.Preload(clause.Associations).
-		Where("title ILIKE ? or excerpt ILIKE ? OR description ILIKE ?", "%"+query.Search+"%", "%"+query.Search+"%", "%"+query.Search+"%").
-		Where("status LIKE ?", "%"+status+"%")
+	if query.Status != "" {
+		sqlMain = sqlMain.Where("status = ?", query.Status)
+	}
 
 	if query.CategoryID > 0 {
 		sqlMain = sqlMain.Where("category_id = ?", query.CategoryID)
 	}
 
-	err = sqlMain.Model(&modelContents).Count(&countData).Error
+	if query.Tag != "" {
+		sqlMain = sqlMain.Where(
+			"EXISTS (SELECT 1 FROM content_tags JOIN tags ON tags.id = content_tags.tag_id WHERE content_tags.content_id = contents.id AND tags.slug = ?)",
+			query.Tag,
+		)
+	}
+
+	if query.Live {
+		sqlMain = sqlMain.Scopes(liveContents(time.Now()))
+	}
+
+	err := sqlMain.Model(&modelContents).Count(&countData).Error
 	if err != nil {
 		code := "[REPOSITORY] GetContents - 2"
 		log.Errorw(code, err)
@@ -126,6 +251,13 @@ func (c *contentRepository) GetContents(ctx context.Context, query entity.QueryS
 
 	totalPages := int(math.Ceil(float64(countData) / float64(query.Limit)))
 
+	if query.Search != "" && query.Highlight {
+		sqlMain = sqlMain.Select(
+			"contents.*, ts_headline(?::regconfig, regexp_replace(description, '<[^>]*>', ' ', 'g'), websearch_to_tsquery(?::regconfig, ?), ?) AS headline",
+			c.searchLanguage, c.searchLanguage, query.Search, headlineOptions,
+		)
+	}
+
 	err = sqlMain.
 		Order(order).
 		Limit(query.Limit).
@@ -141,27 +273,7 @@ func (c *contentRepository) GetContents(ctx context.Context, query entity.QueryS
 	var contentsEntity []entity.ContentEntity
 
 	for _, v := range modelContents {
-		content := entity.ContentEntity{
-			ID:          v.ID,
-			Title:       v.Title,
-			Excerpt:     v.Excerpt,
-			Description: v.Description,
-			Image:       v.Image,
-			Tags:        strings.Split(v.Tags, ","),
-			Status:      v.Status,
-			CategoryID:  v.CategoryID,
-			CreatedByID: v.CreatedByID,
-			CreatedAt:   v.CreatedAt,
-			Category: entity.CategoryEntity{
-				ID:    v.Category.ID,
-				Title: v.Category.Title,
-				Slug:  v.Category.Slug,
-			},
-			User: entity.UserEntity{
-				ID:   v.User.ID,
-				Name: v.User.Name,
-			},
-		}
+		content := contentEntity(*v)
 		contentsEntity = append(contentsEntity, content)
 	}
 
@@ -170,22 +282,231 @@ func (c *contentRepository) GetContents(ctx context.Context, query entity.QueryS
 }
 
 // UpdateContent implements ContentRepository.
-func (c *contentRepository) UpdateContent(ctx context.Context, req entity.ContentEntity) error {
-	tags := strings.Join(req.Tags, ",")
+// Every update is stored as a new revision of the content. With editableStatuses the locked
+// row must be in one of them, otherwise ErrContentStatusChanged is returned; nil allows any.
+func (c *contentRepository) UpdateContent(ctx context.Context, req entity.ContentEntity, editableStatuses []string) error {
 	modelContent := model.Content{
-		Title:       req.Title,
-		Excerpt:     req.Excerpt,
-		Description: req.Description,
-		Image:       req.Image,
-		Tags:        tags,
-		Status:      req.Status,
-		CategoryID:  req.CategoryID,
-		CreatedByID: req.CreatedByID,
+		Title:          req.Title,
+		Slug:           req.Slug,
+		Excerpt:        req.Excerpt,
+		Description:    req.Description,
+		Image:          req.Image,
+		Status:         req.Status,
+		CategoryID:     req.CategoryID,
+		CreatedByID:    req.CreatedByID,
+		SearchLanguage: c.searchLanguage,
 	}
 
-	err = c.db.Where("id = ?", req.ID).Updates(&modelContent).Error
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		var before, after model.Content
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", req.ID).Take(&before).Error; err != nil {
+			code := "[REPOSITORY] UpdateContent - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if editableStatuses != nil && !slices.Contains(editableStatuses, before.Status) {
+			return ErrContentStatusChanged
+		}
+
+		if err := tx.Where("id = ?", req.ID).Updates(&modelContent).Error; err != nil {
+			code := "[REPOSITORY] UpdateContent - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		// Updates skips nil fields, so the schedule is written separately to allow clearing it.
+		err := tx.Model(&model.Content{}).Where("id = ?", req.ID).Updates(map[string]interface{}{
+			"publish_at":   req.PublishAt,
+			"unpublish_at": req.UnpublishAt,
+		}).Error
+		if err != nil {
+			code := "[REPOSITORY] UpdateContent - 3"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if _, err := setContentTags(tx, req.ID, req.Tags); err != nil {
+			return err
+		}
+
+		if err := tx.Where("id = ?", req.ID).Take(&after).Error; err != nil {
+			code := "[REPOSITORY] UpdateContent - 4"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if after.Slug != before.Slug {
+			if err := moveContentSlug(tx, req.ID, before.Slug, after.Slug); err != nil {
+				return err
+			}
+		}
+
+		if _, err := writeContentRevision(ctx, tx, after, nil); err != nil {
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionUpdate, entity.AuditEntityContent, req.ID, contentAuditState(before), contentAuditState(after))
+	})
 	if err != nil {
-		code := "[REPOSITORY] UpdateContent - 1"
+		return translateError(err, ErrContentNotFound)
+	}
+
+	return nil
+}
+
+// UpdateContentStatus moves a content from one workflow status to another and records the
+// change in the audit log. The row is locked while its status is compared with from, so of two
+// concurrent transitions only the first succeeds; the second gets ErrContentStatusChanged.
+// An empty rejectionReason clears the stored reason.
+func (c *contentRepository) UpdateContentStatus(ctx context.Context, id int64, from, to, rejectionReason string) error {
+	var reason *string
+	if rejectionReason != "" {
+		reason = &rejectionReason
+	}
+
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		var before, after model.Content
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Take(&before).Error
+		if err != nil {
+			code := "[REPOSITORY] UpdateContentStatus - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if before.Status != from {
+			return ErrContentStatusChanged
+		}
+
+		err = tx.Model(&model.Content{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":           to,
+			"rejection_reason": reason,
+			"updated_at":       time.Now(),
+		}).Error
+		if err != nil {
+			code := "[REPOSITORY] UpdateContentStatus - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		after = before
+		after.Status = to
+		after.RejectionReason = reason
+
+		return writeAuditLog(ctx, tx, entity.AuditActionStatusChange, entity.AuditEntityContent, id, contentAuditState(before), contentAuditState(after))
+	})
+	if err != nil {
+		return translateError(err, ErrContentNotFound)
+	}
+
+	return nil
+}
+
+// GetContentsDueToPublish implements ContentRepository.
+// It returns the scheduled contents whose publish_at is not after now, or that have no
+// publish_at and are published as soon as they are approved.
+func (c *contentRepository) GetContentsDueToPublish(ctx context.Context, now time.Time) ([]entity.ContentEntity, error) {
+	var modelContents []model.Content
+
+	err := c.db.Where("status = ? AND (publish_at IS NULL OR publish_at <= ?)", entity.ContentStatusScheduled, now).
+		Order("publish_at NULLS FIRST").
+		Find(&modelContents).Error
+	if err != nil {
+		code := "[REPOSITORY] GetContentsDueToPublish - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	contents := make([]entity.ContentEntity, 0, len(modelContents))
+	for _, modelContent := range modelContents {
+		contents = append(contents, contentEntity(modelContent))
+	}
+
+	return contents, nil
+}
+
+// GetContentsDueToUnpublish implements ContentRepository.
+// It returns the published contents whose unpublish_at is not after now.
+func (c *contentRepository) GetContentsDueToUnpublish(ctx context.Context, now time.Time) ([]entity.ContentEntity, error) {
+	var modelContents []model.Content
+
+	err := c.db.Where("status = ? AND unpublish_at <= ?", entity.ContentStatusPublished, now).
+		Order("unpublish_at").
+		Find(&modelContents).Error
+	if err != nil {
+		code := "[REPOSITORY] GetContentsDueToUnpublish - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	contents := make([]entity.ContentEntity, 0, len(modelContents))
+	for _, modelContent := range modelContents {
+		contents = append(contents, contentEntity(modelContent))
+	}
+
+	return contents, nil
+}
+
+// contentEntity maps a content row, with its category and author when they were preloaded.
+func contentEntity(modelContent model.Content) entity.ContentEntity {
+	return entity.ContentEntity{
+		ID:              modelContent.ID,
+		Title:           modelContent.Title,
+		Slug:            modelContent.Slug,
+		Excerpt:         modelContent.Excerpt,
+		Description:     modelContent.Description,
+		Image:           modelContent.Image,
+		Tags:            conv.SplitList(modelContent.Tags),
+		Status:          modelContent.Status,
+		Headline:        modelContent.Headline,
+		RejectionReason: stringValue(modelContent.RejectionReason),
+		PublishAt:       modelContent.PublishAt,
+		UnpublishAt:     modelContent.UnpublishAt,
+		CategoryID:      modelContent.CategoryID,
+		CreatedByID:     modelContent.CreatedByID,
+		CreatedAt:       modelContent.CreatedAt,
+		Category: entity.CategoryEntity{
+			ID:    modelContent.Category.ID,
+			Title: modelContent.Category.Title,
+			Slug:  modelContent.Category.Slug,
+		},
+		User: entity.UserEntity{
+			ID:   modelContent.User.ID,
+			Name: modelContent.User.Name,
+		},
+	}
+}
+
+// liveContents limits a query on contents to those the public can read at now, see
+// entity.ContentEntity.IsLive.
+func liveContents(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("contents.status = ?", entity.ContentStatusPublished).
+			Where("contents.publish_at IS NULL OR contents.publish_at <= ?", now).
+			Where("contents.unpublish_at IS NULL OR contents.unpublish_at > ?", now)
+	}
+}
+
+// moveContentSlug keeps the old slug of a content as a redirect to it and drops the redirect
+// for the new slug, in case the content is given back a slug it had before.
+func moveContentSlug(tx *gorm.DB, contentID int64, oldSlug, newSlug string) error {
+	err := tx.Where("slug = ? AND content_id = ?", newSlug, contentID).Delete(&model.ContentSlugRedirect{}).Error
+	if err != nil {
+		code := "[REPOSITORY] moveContentSlug - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if oldSlug == "" {
+		return nil
+	}
+
+	err = tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"content_id": contentID, "created_at": time.Now()}),
+	}).Create(&model.ContentSlugRedirect{Slug: oldSlug, ContentID: contentID}).Error
+	if err != nil {
+		code := "[REPOSITORY] moveContentSlug - 2"
 		log.Errorw(code, err)
 		return err
 	}
@@ -193,6 +514,22 @@ func (c *contentRepository) UpdateContent(ctx context.Context, req entity.Conten
 	return nil
 }
 
-func NewContentRepository(db *gorm.DB) ContentRepository {
-	return &contentRepository{db: db}
+// escapeLike escapes the LIKE wildcards in s, so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// stringValue returns the string s points to, or "" for nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// NewContentRepository creates a ContentRepository that searches contents with the Postgres text
+// search configuration named by searchLanguage, such as "simple" or "english".
+func NewContentRepository(db *gorm.DB, searchLanguage string) ContentRepository {
+	return &contentRepository{db: db, searchLanguage: searchLanguage}
 }
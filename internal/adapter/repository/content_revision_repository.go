@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/domain/model"
+	"portal-blog/lib/conv"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetContentRevisions implements ContentRepository.
+// Revisions are returned newest first.
+func (c *contentRepository) GetContentRevisions(ctx context.Context, contentID int64) ([]entity.ContentRevisionEntity, error) {
+	var modelRevisions []model.ContentRevision
+
+	err := c.db.Where("content_id = ?", contentID).
+		Preload("Author").
+		Order("revision desc").
+		Find(&modelRevisions).Error
+	if err != nil {
+		code := "[REPOSITORY] GetContentRevisions - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	revisions := make([]entity.ContentRevisionEntity, 0, len(modelRevisions))
+	for _, modelRevision := range modelRevisions {
+		revisions = append(revisions, contentRevisionEntity(modelRevision))
+	}
+
+	return revisions, nil
+}
+
+// GetContentRevision implements ContentRepository.
+func (c *contentRepository) GetContentRevision(ctx context.Context, contentID int64, revision int) (*entity.ContentRevisionEntity, error) {
+	var modelRevision model.ContentRevision
+
+	err := c.db.Where("content_id = ? AND revision = ?", contentID, revision).
+		Preload("Author").
+		Take(&modelRevision).Error
+	if err != nil {
+		code := "[REPOSITORY] GetContentRevision - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrContentRevisionNotFound)
+	}
+
+	result := contentRevisionEntity(modelRevision)
+
+	return &result, nil
+}
+
+// RestoreContentRevision implements ContentRepository.
+// The content is given the fields of the revision and the result is stored as a new revision,
+// so the history in between is kept. The status and schedule of the content are left alone.
+func (c *contentRepository) RestoreContentRevision(ctx context.Context, contentID int64, revision int) (*entity.ContentRevisionEntity, error) {
+	var restored model.ContentRevision
+
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		var before, after model.Content
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", contentID).Take(&before).Error
+		if err != nil {
+			code := "[REPOSITORY] RestoreContentRevision - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		var source model.ContentRevision
+		err = tx.Where("content_id = ? AND revision = ?", contentID, revision).Take(&source).Error
+		if err != nil {
+			code := "[REPOSITORY] RestoreContentRevision - 2"
+			log.Errorw(code, err)
+			return translateError(err, ErrContentRevisionNotFound)
+		}
+
+		err = tx.Model(&model.Content{}).Where("id = ?", contentID).Updates(map[string]interface{}{
+			"title":       source.Title,
+			"excerpt":     source.Excerpt,
+			"description": source.Description,
+			"image":       source.Image,
+			"category_id": source.CategoryID,
+			"updated_at":  time.Now(),
+		}).Error
+		if err != nil {
+			code := "[REPOSITORY] RestoreContentRevision - 3"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if _, err := setContentTags(tx, contentID, conv.SplitList(source.Tags)); err != nil {
+			return err
+		}
+
+		if err := tx.Where("id = ?", contentID).Take(&after).Error; err != nil {
+			code := "[REPOSITORY] RestoreContentRevision - 4"
+			log.Errorw(code, err)
+			return err
+		}
+
+		restored, err = writeContentRevision(ctx, tx, after, &source.Revision)
+		if err != nil {
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionRestore, entity.AuditEntityContent, contentID, contentAuditState(before), contentAuditState(after))
+	})
+	if err != nil {
+		return nil, translateError(err, ErrContentNotFound)
+	}
+
+	result := contentRevisionEntity(restored)
+
+	return &result, nil
+}
+
+// writeContentRevision stores the state of content as its next revision using tx. The content
+// row must be locked by tx, or have just been created by it, so that two saves cannot take the
+// same revision number. The actor in ctx is recorded as the author.
+func writeContentRevision(ctx context.Context, tx *gorm.DB, content model.Content, restoredFrom *int) (model.ContentRevision, error) {
+	var last int
+	err := tx.Model(&model.ContentRevision{}).
+		Where("content_id = ?", content.ID).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&last).Error
+	if err != nil {
+		code := "[REPOSITORY] writeContentRevision - 1"
+		log.Errorw(code, err)
+		return model.ContentRevision{}, err
+	}
+
+	modelRevision := model.ContentRevision{
+		ContentID:    content.ID,
+		Revision:     last + 1,
+		Title:        content.Title,
+		Excerpt:      content.Excerpt,
+		Description:  content.Description,
+		Image:        content.Image,
+		Tags:         content.Tags,
+		RestoredFrom: restoredFrom,
+	}
+
+	if content.CategoryID > 0 {
+		modelRevision.CategoryID = &content.CategoryID
+	}
+
+	if actor := entity.AuditActorFromContext(ctx); actor.UserID > 0 {
+		modelRevision.AuthorID = &actor.UserID
+	}
+
+	if err := tx.Omit(clause.Associations).Create(&modelRevision).Error; err != nil {
+		code := "[REPOSITORY] writeContentRevision - 2"
+		log.Errorw(code, err)
+		return model.ContentRevision{}, err
+	}
+
+	return modelRevision, nil
+}
+
+func contentRevisionEntity(modelRevision model.ContentRevision) entity.ContentRevisionEntity {
+	revision := entity.ContentRevisionEntity{
+		ID:           modelRevision.ID,
+		ContentID:    modelRevision.ContentID,
+		Revision:     modelRevision.Revision,
+		Title:        modelRevision.Title,
+		Excerpt:      modelRevision.Excerpt,
+		Description:  modelRevision.Description,
+		Image:        modelRevision.Image,
+		Tags:         conv.SplitList(modelRevision.Tags),
+		AuthorID:     modelRevision.AuthorID,
+		RestoredFrom: modelRevision.RestoredFrom,
+		CreatedAt:    modelRevision.CreatedAt,
+	}
+
+	if modelRevision.CategoryID != nil {
+		revision.CategoryID = *modelRevision.CategoryID
+	}
+
+	if modelRevision.Author != nil {
+		revision.AuthorName = modelRevision.Author.Name
+	}
+
+	return revision
+}
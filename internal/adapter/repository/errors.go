@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+	"portal-blog/internal/core/domain/apperror"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrContentNotFound         = apperror.NotFound("content_not_found", "content not found")
+	ErrCategoryNotFound        = apperror.NotFound("category_not_found", "category not found")
+	ErrUserNotFound            = apperror.NotFound("user_not_found", "user not found")
+	ErrContentRevisionNotFound = apperror.NotFound("content_revision_not_found", "content revision not found")
+	ErrTagNotFound             = apperror.NotFound("tag_not_found", "tag not found")
+	ErrRefreshTokenNotFound    = apperror.NotFound("refresh_token_not_found", "refresh token not found")
+	ErrSessionNotFound         = apperror.NotFound("session_not_found", "session not found")
+	ErrInvitationNotFound      = apperror.NotFound("invitation_not_found", "invitation not found")
+	ErrAPIKeyNotFound          = apperror.NotFound("api_key_not_found", "api key not found")
+	ErrMfaNotFound             = apperror.NotFound("mfa_not_found", "mfa enrolment not found")
+	ErrMfaChallengeNotFound    = apperror.NotFound("mfa_challenge_not_found", "mfa challenge not found")
+	ErrOidcStateNotFound       = apperror.NotFound("oidc_state_not_found", "oidc state not found")
+	ErrOidcIdentityNotFound    = apperror.NotFound("oidc_identity_not_found", "oidc identity not found")
+	ErrPasswordResetNotFound   = apperror.NotFound("password_reset_not_found", "password reset token not found")
+	ErrRoleNotFound            = apperror.NotFound("role_not_found", "role not found")
+	ErrCategoryHasContents     = apperror.Conflict("category_has_contents", "cannot delete a category that has associate contents")
+	ErrContentStatusChanged    = apperror.Conflict("content_status_changed", "the content status was changed in the meantime, reload it and try again")
+
+	ErrDuplicateRecord  = apperror.Conflict("duplicate_record", "a record with the same unique value already exists")
+	ErrRecordReferenced = apperror.Conflict("record_referenced", "the record is referenced by or refers to another record")
+	ErrInvalidRecord    = apperror.Validation("invalid_record", "the record violates a database constraint")
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgNotNullViolation    = "23502"
+	pgCheckViolation      = "23514"
+	pgStringTooLong       = "22001"
+)
+
+// translateError turns gorm and Postgres errors into domain errors that keep the original as
+// their cause. gorm.ErrRecordNotFound becomes notFound; errors it does not recognise are
+// returned unchanged.
+func translateError(err error, notFound *apperror.Error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := apperror.As(err); ok {
+		return err
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) && notFound != nil {
+		return notFound.Wrap(err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return ErrDuplicateRecord.Wrap(err)
+		case pgForeignKeyViolation:
+			return ErrRecordReferenced.Wrap(err)
+		case pgNotNullViolation, pgCheckViolation, pgStringTooLong:
+			return ErrInvalidRecord.Wrap(err)
+		}
+	}
+
+	return err
+}
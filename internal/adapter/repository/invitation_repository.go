@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/domain/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+// ErrInvitationClosed is returned when an invitation was accepted, revoked or, for
+// AcceptInvitation, has expired by the time it is changed.
+var ErrInvitationClosed = errors.New("invitation is no longer pending")
+
+type InvitationRepository interface {
+	CreateInvitation(ctx context.Context, req entity.InvitationEntity) (*entity.InvitationEntity, error)
+	GetInvitations(ctx context.Context) ([]entity.InvitationEntity, error)
+	GetInvitationByID(ctx context.Context, id int64) (*entity.InvitationEntity, error)
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*entity.InvitationEntity, error)
+	CountOpenInvitationsByEmail(ctx context.Context, email string) (int64, error)
+	RenewInvitation(ctx context.Context, id int64, tokenHash string, expiresAt time.Time) error
+	RevokeInvitation(ctx context.Context, id int64) error
+	AcceptInvitation(ctx context.Context, id int64, user entity.UserEntity) (int64, error)
+}
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+// CreateInvitation implements InvitationRepository.
+func (i *invitationRepository) CreateInvitation(ctx context.Context, req entity.InvitationEntity) (*entity.InvitationEntity, error) {
+	now := time.Now()
+	modelInvitation := model.Invitation{
+		Email:     req.Email,
+		Role:      req.Role,
+		TokenHash: req.TokenHash,
+		InvitedBy: req.InvitedBy,
+		ExpiresAt: req.ExpiresAt,
+		SentAt:    now,
+		CreatedAt: now,
+	}
+
+	err := i.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&modelInvitation).Error; err != nil {
+			code := "[REPOSITORY] CreateInvitation - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionCreate, entity.AuditEntityInvitation, modelInvitation.ID, nil, invitationAuditState(modelInvitation))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toInvitationEntity(modelInvitation)
+
+	return &resp, nil
+}
+
+// GetInvitations implements InvitationRepository.
+// Invitations are returned newest first, whatever their status.
+func (i *invitationRepository) GetInvitations(ctx context.Context) ([]entity.InvitationEntity, error) {
+	var modelInvitations []model.Invitation
+
+	err := i.db.Order("created_at DESC").Find(&modelInvitations).Error
+	if err != nil {
+		code := "[REPOSITORY] GetInvitations - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := []entity.InvitationEntity{}
+	for _, v := range modelInvitations {
+		resps = append(resps, toInvitationEntity(v))
+	}
+
+	return resps, nil
+}
+
+// GetInvitationByID implements InvitationRepository.
+func (i *invitationRepository) GetInvitationByID(ctx context.Context, id int64) (*entity.InvitationEntity, error) {
+	var modelInvitation model.Invitation
+
+	err := i.db.Where("id = ?", id).First(&modelInvitation).Error
+	if err != nil {
+		code := "[REPOSITORY] GetInvitationByID - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrInvitationNotFound)
+	}
+
+	resp := toInvitationEntity(modelInvitation)
+
+	return &resp, nil
+}
+
+// GetInvitationByTokenHash implements InvitationRepository.
+func (i *invitationRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*entity.InvitationEntity, error) {
+	var modelInvitation model.Invitation
+
+	err := i.db.Where("token_hash = ?", tokenHash).First(&modelInvitation).Error
+	if err != nil {
+		code := "[REPOSITORY] GetInvitationByTokenHash - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrInvitationNotFound)
+	}
+
+	resp := toInvitationEntity(modelInvitation)
+
+	return &resp, nil
+}
+
+// CountOpenInvitationsByEmail implements InvitationRepository.
+// Expired invitations that were neither accepted nor revoked are counted too, since they can
+// still be resent.
+func (i *invitationRepository) CountOpenInvitationsByEmail(ctx context.Context, email string) (int64, error) {
+	var count int64
+
+	err := i.db.Model(&model.Invitation{}).
+		Where("LOWER(email) = LOWER(?) AND accepted_at IS NULL AND revoked_at IS NULL", email).
+		Count(&count).Error
+	if err != nil {
+		code := "[REPOSITORY] CountOpenInvitationsByEmail - 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// RenewInvitation implements InvitationRepository.
+// The token is replaced, so links sent earlier stop working.
+func (i *invitationRepository) RenewInvitation(ctx context.Context, id int64, tokenHash string, expiresAt time.Time) error {
+	return i.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Invitation{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+			Updates(map[string]interface{}{"token_hash": tokenHash, "expires_at": expiresAt, "sent_at": time.Now()})
+		if result.Error != nil {
+			code := "[REPOSITORY] RenewInvitation - 1"
+			log.Errorw(code, result.Error)
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrInvitationClosed
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionResend, entity.AuditEntityInvitation, id, nil, auditState{"expires_at": expiresAt.UTC().Format(time.RFC3339)})
+	})
+}
+
+// RevokeInvitation implements InvitationRepository.
+func (i *invitationRepository) RevokeInvitation(ctx context.Context, id int64) error {
+	return i.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.Invitation{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+			Update("revoked_at", now)
+		if result.Error != nil {
+			code := "[REPOSITORY] RevokeInvitation - 1"
+			log.Errorw(code, result.Error)
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrInvitationClosed
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionRevoke, entity.AuditEntityInvitation, id, nil, auditState{"revoked_at": now.UTC().Format(time.RFC3339)})
+	})
+}
+
+// AcceptInvitation implements InvitationRepository.
+// The invitation is closed and the user is created in one transaction, so an invitation
+// creates at most one user. It returns the new user's ID.
+func (i *invitationRepository) AcceptInvitation(ctx context.Context, id int64, user entity.UserEntity) (int64, error) {
+	modelUser := model.User{
+		Name:     user.Name,
+		Email:    user.Email,
+		Password: user.Password,
+		Role:     user.Role,
+		IsActive: true,
+	}
+
+	err := i.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.Invitation{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", id, now).
+			Update("accepted_at", now)
+		if result.Error != nil {
+			code := "[REPOSITORY] AcceptInvitation - 1"
+			log.Errorw(code, result.Error)
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrInvitationClosed
+		}
+
+		if err := tx.Create(&modelUser).Error; err != nil {
+			code := "[REPOSITORY] AcceptInvitation - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := tx.Model(&model.Invitation{}).Where("id = ?", id).Update("user_id", modelUser.ID).Error; err != nil {
+			code := "[REPOSITORY] AcceptInvitation - 3"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := writeAuditLog(ctx, tx, entity.AuditActionCreate, entity.AuditEntityUser, modelUser.ID, nil, userAuditState(modelUser)); err != nil {
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionAccept, entity.AuditEntityInvitation, id, nil, auditState{"user_id": modelUser.ID})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return modelUser.ID, nil
+}
+
+// toInvitationEntity maps an invitation row to its entity.
+func toInvitationEntity(modelInvitation model.Invitation) entity.InvitationEntity {
+	return entity.InvitationEntity{
+		ID:         modelInvitation.ID,
+		Email:      modelInvitation.Email,
+		Role:       modelInvitation.Role,
+		TokenHash:  modelInvitation.TokenHash,
+		InvitedBy:  modelInvitation.InvitedBy,
+		UserID:     modelInvitation.UserID,
+		ExpiresAt:  modelInvitation.ExpiresAt,
+		AcceptedAt: modelInvitation.AcceptedAt,
+		RevokedAt:  modelInvitation.RevokedAt,
+		SentAt:     modelInvitation.SentAt,
+		CreatedAt:  modelInvitation.CreatedAt,
+	}
+}
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"portal-blog/internal/core/domain/model"
+	"portal-blog/lib/auth"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// Get implements auth.LoginAttemptStore.
+func (l *loginAttemptRepository) Get(ctx context.Context, key string) (*auth.LoginAttempt, error) {
+	var modelAttempt model.LoginAttempt
+
+	err := l.db.Where("key = ?", key).First(&modelAttempt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		code := "[REPOSITORY] Get - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return &auth.LoginAttempt{
+		Failures:      modelAttempt.Failures,
+		LastFailureAt: modelAttempt.LastFailureAt,
+		LockedUntil:   modelAttempt.LockedUntil,
+	}, nil
+}
+
+// RegisterFailure implements auth.LoginAttemptStore.
+// The counter is incremented in a single upsert so concurrent failures are all counted.
+func (l *loginAttemptRepository) RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	var failures int
+	now := time.Now()
+
+	err := l.db.Raw(`INSERT INTO login_attempts (key, failures, last_failure_at) VALUES (?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failure_at < ? THEN 1 ELSE login_attempts.failures + 1 END,
+			locked_until = CASE WHEN login_attempts.last_failure_at < ? THEN NULL ELSE login_attempts.locked_until END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING failures`, key, now, now.Add(-window), now.Add(-window)).Scan(&failures).Error
+	if err != nil {
+		code := "[REPOSITORY] RegisterFailure - 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+// Lock implements auth.LoginAttemptStore.
+func (l *loginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	err := l.db.Model(&model.LoginAttempt{}).Where("key = ?", key).Update("locked_until", until).Error
+	if err != nil {
+		code := "[REPOSITORY] Lock - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// Reset implements auth.LoginAttemptStore.
+func (l *loginAttemptRepository) Reset(ctx context.Context, key string) error {
+	err := l.db.Where("key = ?", key).Delete(&model.LoginAttempt{}).Error
+	if err != nil {
+		code := "[REPOSITORY] Reset - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExpired implements auth.LoginAttemptStore.
+func (l *loginAttemptRepository) DeleteExpired(ctx context.Context, window time.Duration) (int64, error) {
+	now := time.Now()
+	result := l.db.Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until <= ?)", now.Add(-window), now).
+		Delete(&model.LoginAttempt{})
+	if result.Error != nil {
+		code := "[REPOSITORY] DeleteExpired - 1"
+		log.Errorw(code, result.Error)
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// NewLoginAttemptRepository creates a Postgres backed auth.LoginAttemptStore.
+//
+// Parameters:
+//   - db: A pointer to a gorm.DB instance representing the database connection.
+//
+// Returns:
+//   - auth.LoginAttemptStore: A store persisting failed login counters in the login_attempts table.
+func NewLoginAttemptRepository(db *gorm.DB) auth.LoginAttemptStore {
+	return &loginAttemptRepository{db: db}
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/domain/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrMfaAlreadyEnabled is returned when enrolment is attempted for a user whose MFA is already enabled.
+	ErrMfaAlreadyEnabled = errors.New("mfa already enabled")
+	// ErrTOTPStepUsed is returned when a TOTP code of an already used time step is presented again.
+	ErrTOTPStepUsed = errors.New("totp code already used")
+	// ErrRecoveryCodeNotFound is returned when a recovery code does not exist or was already used.
+	ErrRecoveryCodeNotFound = errors.New("recovery code not found")
+	// ErrMfaChallengeUnavailable is returned when a challenge was used or ran out of attempts.
+	ErrMfaChallengeUnavailable = errors.New("mfa challenge unavailable")
+)
+
+type MfaRepository interface {
+	GetUserMfa(ctx context.Context, userID int64) (*entity.UserMfaEntity, error)
+	SaveUserMfaSecret(ctx context.Context, userID int64, secret string) error
+	EnableUserMfa(ctx context.Context, userID, step int64, recoveryCodeHashes []string) error
+	DisableUserMfa(ctx context.Context, userID int64) error
+	MarkTOTPStepUsed(ctx context.Context, userID, step int64) error
+
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error
+
+	CreateMfaChallenge(ctx context.Context, req entity.MfaChallengeEntity) error
+	GetMfaChallengeByHash(ctx context.Context, tokenHash string) (*entity.MfaChallengeEntity, error)
+	IncrementMfaChallengeAttempts(ctx context.Context, id int64, maxAttempts int) error
+	ConsumeMfaChallenge(ctx context.Context, id int64) error
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+// GetUserMfa implements MfaRepository.
+// ErrMfaNotFound is returned when the user never started an enrolment.
+func (m *mfaRepository) GetUserMfa(ctx context.Context, userID int64) (*entity.UserMfaEntity, error) {
+	var modelMfa model.UserMfa
+
+	err := m.db.Where("user_id = ?", userID).First(&modelMfa).Error
+	if err != nil {
+		code := "[REPOSITORY] GetUserMfa - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrMfaNotFound)
+	}
+
+	return &entity.UserMfaEntity{
+		UserID:       modelMfa.UserID,
+		Secret:       modelMfa.Secret,
+		Enabled:      modelMfa.Enabled,
+		LastUsedStep: modelMfa.LastUsedStep,
+	}, nil
+}
+
+// SaveUserMfaSecret implements MfaRepository.
+// It stores a pending secret, replacing an earlier unconfirmed one. An enabled secret is never replaced.
+func (m *mfaRepository) SaveUserMfaSecret(ctx context.Context, userID int64, secret string) error {
+	now := time.Now()
+	modelMfa := model.UserMfa{
+		UserID:    userID,
+		Secret:    secret,
+		UpdatedAt: &now,
+	}
+
+	result := m.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"secret": secret, "last_used_step": 0, "updated_at": now}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "user_mfa.enabled", Value: false}}},
+	}).Create(&modelMfa)
+	if result.Error != nil {
+		code := "[REPOSITORY] SaveUserMfaSecret - 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrMfaAlreadyEnabled
+	}
+
+	return nil
+}
+
+// EnableUserMfa implements MfaRepository.
+// The pending secret is confirmed, the step of the confirming code is recorded and the
+// recovery codes are replaced in the same transaction.
+func (m *mfaRepository) EnableUserMfa(ctx context.Context, userID, step int64, recoveryCodeHashes []string) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.UserMfa{}).
+			Where("user_id = ? AND enabled = ?", userID, false).
+			Updates(map[string]interface{}{"enabled": true, "last_used_step": step, "enabled_at": now, "updated_at": now})
+		if result.Error != nil {
+			code := "[REPOSITORY] EnableUserMfa - 1"
+			log.Errorw(code, result.Error)
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrMfaAlreadyEnabled
+		}
+
+		if err := replaceRecoveryCodes(tx, userID, recoveryCodeHashes); err != nil {
+			code := "[REPOSITORY] EnableUserMfa - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// DisableUserMfa implements MfaRepository.
+func (m *mfaRepository) DisableUserMfa(ctx context.Context, userID int64) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.MfaRecoveryCode{}).Error; err != nil {
+			code := "[REPOSITORY] DisableUserMfa - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserMfa{}).Error; err != nil {
+			code := "[REPOSITORY] DisableUserMfa - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionMfaDisable, entity.AuditEntityUser, userID, nil, nil)
+	})
+}
+
+// MarkTOTPStepUsed implements MfaRepository.
+// The step is only recorded when it is newer than the last used one, so each code works once.
+func (m *mfaRepository) MarkTOTPStepUsed(ctx context.Context, userID, step int64) error {
+	result := m.db.Model(&model.UserMfa{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		code := "[REPOSITORY] MarkTOTPStepUsed - 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTOTPStepUsed
+	}
+
+	return nil
+}
+
+// UseRecoveryCode implements MfaRepository.
+func (m *mfaRepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error {
+	result := m.db.Model(&model.MfaRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		code := "[REPOSITORY] UseRecoveryCode - 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrRecoveryCodeNotFound
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes implements MfaRepository.
+func (m *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error {
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+	if err != nil {
+		code := "[REPOSITORY] ReplaceRecoveryCodes - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes deletes every recovery code of the user and stores the given hashes.
+func replaceRecoveryCodes(tx *gorm.DB, userID int64, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.MfaRecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]model.MfaRecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, model.MfaRecoveryCode{UserID: userID, CodeHash: hash})
+	}
+
+	if len(codes) == 0 {
+		return nil
+	}
+
+	return tx.Create(&codes).Error
+}
+
+// CreateMfaChallenge implements MfaRepository.
+func (m *mfaRepository) CreateMfaChallenge(ctx context.Context, req entity.MfaChallengeEntity) error {
+	modelChallenge := model.MfaChallenge{
+		UserID:    req.UserID,
+		TokenHash: req.TokenHash,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	err := m.db.Create(&modelChallenge).Error
+	if err != nil {
+		code := "[REPOSITORY] CreateMfaChallenge - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetMfaChallengeByHash implements MfaRepository.
+func (m *mfaRepository) GetMfaChallengeByHash(ctx context.Context, tokenHash string) (*entity.MfaChallengeEntity, error) {
+	var modelChallenge model.MfaChallenge
+
+	err := m.db.Where("token_hash = ?", tokenHash).First(&modelChallenge).Error
+	if err != nil {
+		code := "[REPOSITORY] GetMfaChallengeByHash - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrMfaChallengeNotFound)
+	}
+
+	return &entity.MfaChallengeEntity{
+		ID:        modelChallenge.ID,
+		UserID:    modelChallenge.UserID,
+		TokenHash: modelChallenge.TokenHash,
+		Attempts:  modelChallenge.Attempts,
+		ExpiresAt: modelChallenge.ExpiresAt,
+		UsedAt:    modelChallenge.UsedAt,
+	}, nil
+}
+
+// IncrementMfaChallengeAttempts implements MfaRepository.
+// An attempt is only counted while the challenge is unused and below maxAttempts, which caps
+// how many codes can be guessed per challenge even under concurrent requests.
+func (m *mfaRepository) IncrementMfaChallengeAttempts(ctx context.Context, id int64, maxAttempts int) error {
+	result := m.db.Model(&model.MfaChallenge{}).
+		Where("id = ? AND used_at IS NULL AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		code := "[REPOSITORY] IncrementMfaChallengeAttempts - 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrMfaChallengeUnavailable
+	}
+
+	return nil
+}
+
+// ConsumeMfaChallenge implements MfaRepository.
+func (m *mfaRepository) ConsumeMfaChallenge(ctx context.Context, id int64) error {
+	result := m.db.Model(&model.MfaChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		code := "[REPOSITORY] ConsumeMfaChallenge - 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrMfaChallengeUnavailable
+	}
+
+	return nil
+}
+
+func NewMfaRepository(db *gorm.DB) MfaRepository {
+	return &mfaRepository{db: db}
+}
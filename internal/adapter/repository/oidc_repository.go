@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/domain/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OidcRepository interface {
+	CreateOidcState(ctx context.Context, req entity.OidcStateEntity) error
+	ConsumeOidcState(ctx context.Context, stateHash string) (*entity.OidcStateEntity, error)
+
+	GetUserIDByIdentity(ctx context.Context, issuer, subject string) (int64, error)
+	LinkUserIdentity(ctx context.Context, userID int64, identity entity.OidcIdentityEntity) error
+	CreateUserWithIdentity(ctx context.Context, user entity.UserEntity, identity entity.OidcIdentityEntity) (int64, error)
+	TouchUserIdentity(ctx context.Context, issuer, subject string) error
+}
+
+type oidcRepository struct {
+	db *gorm.DB
+}
+
+// CreateOidcState implements OidcRepository.
+// Expired states are pruned on the way, so abandoned sign-ins do not pile up.
+func (o *oidcRepository) CreateOidcState(ctx context.Context, req entity.OidcStateEntity) error {
+	err := o.db.Where("expires_at < ?", time.Now()).Delete(&model.OidcState{}).Error
+	if err != nil {
+		code := "[REPOSITORY] CreateOidcState - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	modelState := model.OidcState{
+		StateHash:    req.StateHash,
+		Nonce:        req.Nonce,
+		CodeVerifier: req.CodeVerifier,
+		ExpiresAt:    req.ExpiresAt,
+	}
+
+	err = o.db.Create(&modelState).Error
+	if err != nil {
+		code := "[REPOSITORY] CreateOidcState - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// ConsumeOidcState implements OidcRepository.
+// The state is marked as used in the same statement that reads it, so a callback can be
+// completed at most once. ErrOidcStateNotFound is returned for unknown, used or expired states.
+func (o *oidcRepository) ConsumeOidcState(ctx context.Context, stateHash string) (*entity.OidcStateEntity, error) {
+	var modelState model.OidcState
+	now := time.Now()
+
+	result := o.db.Raw(`UPDATE oidc_states SET used_at = ?
+		WHERE state_hash = ? AND used_at IS NULL AND expires_at > ?
+		RETURNING id, state_hash, nonce, code_verifier, expires_at, used_at`, now, stateHash, now).Scan(&modelState)
+	if result.Error != nil {
+		code := "[REPOSITORY] ConsumeOidcState - 1"
+		log.Errorw(code, result.Error)
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, ErrOidcStateNotFound
+	}
+
+	return &entity.OidcStateEntity{
+		ID:           modelState.ID,
+		StateHash:    modelState.StateHash,
+		Nonce:        modelState.Nonce,
+		CodeVerifier: modelState.CodeVerifier,
+		ExpiresAt:    modelState.ExpiresAt,
+		UsedAt:       modelState.UsedAt,
+	}, nil
+}
+
+// GetUserIDByIdentity implements OidcRepository.
+func (o *oidcRepository) GetUserIDByIdentity(ctx context.Context, issuer, subject string) (int64, error) {
+	var modelIdentity model.UserIdentity
+
+	err := o.db.Where("issuer = ? AND subject = ?", issuer, subject).First(&modelIdentity).Error
+	if err != nil {
+		code := "[REPOSITORY] GetUserIDByIdentity - 1"
+		log.Errorw(code, err)
+		return 0, translateError(err, ErrOidcIdentityNotFound)
+	}
+
+	return modelIdentity.UserID, nil
+}
+
+// LinkUserIdentity implements OidcRepository.
+// Linking an identity that is already linked is a no-op.
+func (o *oidcRepository) LinkUserIdentity(ctx context.Context, userID int64, identity entity.OidcIdentityEntity) error {
+	err := createUserIdentity(o.db, userID, identity)
+	if err != nil {
+		code := "[REPOSITORY] LinkUserIdentity - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// CreateUserWithIdentity implements OidcRepository.
+// The user and its identity are created in one transaction and the new user ID is returned.
+func (o *oidcRepository) CreateUserWithIdentity(ctx context.Context, user entity.UserEntity, identity entity.OidcIdentityEntity) (int64, error) {
+	modelUser := model.User{
+		Name:     user.Name,
+		Email:    user.Email,
+		Password: user.Password,
+		Role:     user.Role,
+		IsActive: true,
+	}
+
+	err := o.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&modelUser).Error; err != nil {
+			code := "[REPOSITORY] CreateUserWithIdentity - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := createUserIdentity(tx, modelUser.ID, identity); err != nil {
+			code := "[REPOSITORY] CreateUserWithIdentity - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionCreate, entity.AuditEntityUser, modelUser.ID, nil, userAuditState(modelUser))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return modelUser.ID, nil
+}
+
+// TouchUserIdentity implements OidcRepository.
+func (o *oidcRepository) TouchUserIdentity(ctx context.Context, issuer, subject string) error {
+	err := o.db.Model(&model.UserIdentity{}).
+		Where("issuer = ? AND subject = ?", issuer, subject).
+		Update("last_login_at", time.Now()).Error
+	if err != nil {
+		code := "[REPOSITORY] TouchUserIdentity - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+func createUserIdentity(db *gorm.DB, userID int64, identity entity.OidcIdentityEntity) error {
+	modelIdentity := model.UserIdentity{
+		UserID:  userID,
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+		Email:   identity.Email,
+	}
+
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&modelIdentity).Error
+}
+
+func NewOidcRepository(db *gorm.DB) OidcRepository {
+	return &oidcRepository{db: db}
+}
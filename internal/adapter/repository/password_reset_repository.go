@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/domain/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+// ErrPasswordResetTokenUsed is returned when a reset token was consumed by another request.
+var ErrPasswordResetTokenUsed = errors.New("password reset token already used")
+
+type PasswordResetRepository interface {
+	CreatePasswordResetToken(ctx context.Context, req entity.PasswordResetTokenEntity) error
+	GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*entity.PasswordResetTokenEntity, error)
+	ConsumePasswordResetToken(ctx context.Context, id int64) error
+	ReleasePasswordResetToken(ctx context.Context, id int64) error
+	InvalidateUserPasswordResetTokens(ctx context.Context, userID int64) error
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+// CreatePasswordResetToken implements PasswordResetRepository.
+func (p *passwordResetRepository) CreatePasswordResetToken(ctx context.Context, req entity.PasswordResetTokenEntity) error {
+	modelToken := model.PasswordResetToken{
+		UserID:    req.UserID,
+		TokenHash: req.TokenHash,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	err := p.db.Create(&modelToken).Error
+	if err != nil {
+		code := "[REPOSITORY] CreatePasswordResetToken - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetPasswordResetTokenByHash implements PasswordResetRepository.
+func (p *passwordResetRepository) GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*entity.PasswordResetTokenEntity, error) {
+	var modelToken model.PasswordResetToken
+
+	err := p.db.Where("token_hash = ?", tokenHash).First(&modelToken).Error
+	if err != nil {
+		code := "[REPOSITORY] GetPasswordResetTokenByHash - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrPasswordResetNotFound)
+	}
+
+	return &entity.PasswordResetTokenEntity{
+		ID:        modelToken.ID,
+		UserID:    modelToken.UserID,
+		TokenHash: modelToken.TokenHash,
+		ExpiresAt: modelToken.ExpiresAt,
+		UsedAt:    modelToken.UsedAt,
+	}, nil
+}
+
+// ConsumePasswordResetToken implements PasswordResetRepository.
+// The token is only marked as used when it has not been used yet, so it can be consumed at most once.
+func (p *passwordResetRepository) ConsumePasswordResetToken(ctx context.Context, id int64) error {
+	result := p.db.Model(&model.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		code := "[REPOSITORY] ConsumePasswordResetToken - 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrPasswordResetTokenUsed
+	}
+
+	return nil
+}
+
+// ReleasePasswordResetToken implements PasswordResetRepository.
+// It makes a consumed token usable again when the reset it was consumed for failed.
+func (p *passwordResetRepository) ReleasePasswordResetToken(ctx context.Context, id int64) error {
+	err := p.db.Model(&model.PasswordResetToken{}).Where("id = ?", id).Update("used_at", nil).Error
+	if err != nil {
+		code := "[REPOSITORY] ReleasePasswordResetToken - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// InvalidateUserPasswordResetTokens implements PasswordResetRepository.
+func (p *passwordResetRepository) InvalidateUserPasswordResetTokens(ctx context.Context, userID int64) error {
+	err := p.db.Model(&model.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+	if err != nil {
+		code := "[REPOSITORY] InvalidateUserPasswordResetTokens - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
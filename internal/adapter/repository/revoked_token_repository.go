@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"portal-blog/internal/core/domain/model"
+	"portal-blog/lib/auth"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type revokedTokenRepository struct {
+	db *gorm.DB
+}
+
+// Revoke implements auth.RevocationStore.
+// Revoking an already revoked jti is a no-op.
+func (r *revokedTokenRepository) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	modelToken := model.RevokedToken{
+		JTI:       jti,
+		ExpiresAt: expiresAt,
+	}
+
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&modelToken).Error
+	if err != nil {
+		code := "[REPOSITORY] Revoke - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// IsRevoked implements auth.RevocationStore.
+func (r *revokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+
+	err := r.db.Model(&model.RevokedToken{}).Where("jti = ? AND expires_at > ?", jti, time.Now()).Count(&count).Error
+	if err != nil {
+		code := "[REPOSITORY] IsRevoked - 1"
+		log.Errorw(code, err)
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// DeleteExpired implements auth.RevocationStore.
+func (r *revokedTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result := r.db.Where("expires_at <= ?", time.Now()).Delete(&model.RevokedToken{})
+	if result.Error != nil {
+		code := "[REPOSITORY] DeleteExpired - 1"
+		log.Errorw(code, result.Error)
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// NewRevokedTokenRepository creates a Postgres backed auth.RevocationStore.
+//
+// Parameters:
+//   - db: A pointer to a gorm.DB instance representing the database connection.
+//
+// Returns:
+//   - auth.RevocationStore: A store persisting revoked token IDs in the revoked_tokens table.
+func NewRevokedTokenRepository(db *gorm.DB) auth.RevocationStore {
+	return &revokedTokenRepository{db: db}
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/domain/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	GetRoles(ctx context.Context) ([]entity.RoleEntity, error)
+	GetRoleByName(ctx context.Context, name string) (*entity.RoleEntity, error)
+	UpdateRoleMfaRequired(ctx context.Context, name string, required bool) error
+	GetRolePermissions(ctx context.Context) (map[string][]string, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// GetRoles implements RoleRepository.
+func (r *roleRepository) GetRoles(ctx context.Context) ([]entity.RoleEntity, error) {
+	var modelRoles []model.Role
+
+	err := r.db.Order("name asc").Find(&modelRoles).Error
+	if err != nil {
+		code := "[REPOSITORY] GetRoles - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := []entity.RoleEntity{}
+	for _, val := range modelRoles {
+		resps = append(resps, entity.RoleEntity{
+			Name:        val.Name,
+			Description: val.Description,
+			MfaRequired: val.MfaRequired,
+		})
+	}
+
+	return resps, nil
+}
+
+// GetRoleByName implements RoleRepository.
+func (r *roleRepository) GetRoleByName(ctx context.Context, name string) (*entity.RoleEntity, error) {
+	var modelRole model.Role
+
+	err := r.db.Where("name = ?", name).First(&modelRole).Error
+	if err != nil {
+		code := "[REPOSITORY] GetRoleByName - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrRoleNotFound)
+	}
+
+	return &entity.RoleEntity{
+		Name:        modelRole.Name,
+		Description: modelRole.Description,
+		MfaRequired: modelRole.MfaRequired,
+	}, nil
+}
+
+// UpdateRoleMfaRequired implements RoleRepository.
+func (r *roleRepository) UpdateRoleMfaRequired(ctx context.Context, name string, required bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before model.Role
+		if err := tx.Where("name = ?", name).Take(&before).Error; err != nil {
+			code := "[REPOSITORY] UpdateRoleMfaRequired - 1"
+			log.Errorw(code, err)
+			return translateError(err, ErrRoleNotFound)
+		}
+
+		err := tx.Model(&model.Role{}).
+			Where("name = ?", name).
+			Updates(map[string]interface{}{"mfa_required": required, "updated_at": time.Now()}).Error
+		if err != nil {
+			code := "[REPOSITORY] UpdateRoleMfaRequired - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		after := before
+		after.MfaRequired = required
+
+		return writeAuditLogByKey(ctx, tx, entity.AuditActionUpdate, entity.AuditEntityRole, name, roleAuditState(before), roleAuditState(after))
+	})
+}
+
+// GetRolePermissions implements RoleRepository.
+// Every role is a key of the result, including roles without any permission.
+func (r *roleRepository) GetRolePermissions(ctx context.Context) (map[string][]string, error) {
+	var rows []model.RolePermission
+
+	err := r.db.Table("roles").
+		Select("roles.name AS role, COALESCE(role_permissions.permission, '') AS permission").
+		Joins("LEFT JOIN role_permissions ON role_permissions.role = roles.name").
+		Order("roles.name asc, role_permissions.permission asc").
+		Scan(&rows).Error
+	if err != nil {
+		code := "[REPOSITORY] GetRolePermissions - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := map[string][]string{}
+	for _, row := range rows {
+		permissions := resps[row.Role]
+		if row.Permission != "" {
+			permissions = append(permissions, row.Permission)
+		}
+		resps[row.Role] = permissions
+	}
+
+	return resps, nil
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
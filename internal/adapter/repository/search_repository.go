@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/domain/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type SearchRepository interface {
+	SuggestContents(ctx context.Context, q string, limit int) ([]entity.ContentEntity, error)
+	SuggestCategories(ctx context.Context, q string, limit int) ([]entity.CategoryEntity, error)
+	SuggestTags(ctx context.Context, q string, limit int) ([]string, error)
+	GetClosestSearchTerm(ctx context.Context, word string) (string, error)
+	RefreshSearchTerms(ctx context.Context) error
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+// SuggestContents implements SearchRepository.
+// Live contents whose title contains q, or a word similar to it, are returned best match first.
+func (s *searchRepository) SuggestContents(ctx context.Context, q string, limit int) ([]entity.ContentEntity, error) {
+	var modelContents []model.Content
+
+	err := s.db.Select("id", "title", "slug").
+		Scopes(liveContents(time.Now())).
+		Where("title ILIKE ? OR ? <% title", "%"+escapeLike(q)+"%", q).
+		Order(gorm.Expr("word_similarity(?, title) DESC, created_at DESC", q)).
+		Limit(limit).
+		Find(&modelContents).Error
+	if err != nil {
+		code := "[REPOSITORY] SuggestContents - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	contents := make([]entity.ContentEntity, 0, len(modelContents))
+	for _, modelContent := range modelContents {
+		contents = append(contents, entity.ContentEntity{
+			ID:    modelContent.ID,
+			Title: modelContent.Title,
+			Slug:  modelContent.Slug,
+		})
+	}
+
+	return contents, nil
+}
+
+// SuggestCategories implements SearchRepository.
+func (s *searchRepository) SuggestCategories(ctx context.Context, q string, limit int) ([]entity.CategoryEntity, error) {
+	var modelCategories []model.Category
+
+	err := s.db.Where("title ILIKE ? OR ? <% title", "%"+escapeLike(q)+"%", q).
+		Order(gorm.Expr("word_similarity(?, title) DESC, title", q)).
+		Limit(limit).
+		Find(&modelCategories).Error
+	if err != nil {
+		code := "[REPOSITORY] SuggestCategories - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	categories := make([]entity.CategoryEntity, 0, len(modelCategories))
+	for _, modelCategory := range modelCategories {
+		categories = append(categories, entity.CategoryEntity{
+			ID:    modelCategory.ID,
+			Title: modelCategory.Title,
+			Slug:  modelCategory.Slug,
+		})
+	}
+
+	return categories, nil
+}
+
+// SuggestTags implements SearchRepository.
+// Only tags of live contents are suggested, the most used first among equally good matches.
+func (s *searchRepository) SuggestTags(ctx context.Context, q string, limit int) ([]string, error) {
+	var tags []string
+
+	err := s.db.Model(&model.Tag{}).
+		Joins("JOIN content_tags ON content_tags.tag_id = tags.id").
+		Joins("JOIN contents ON contents.id = content_tags.content_id").
+		Scopes(liveContents(time.Now())).
+		Where("tags.title ILIKE ? OR ? <% tags.title", "%"+escapeLike(q)+"%", q).
+		Group("tags.id").
+		Order(gorm.Expr("word_similarity(?, tags.title) DESC, count(*) DESC, tags.title", q)).
+		Limit(limit).
+		Pluck("tags.title", &tags).Error
+	if err != nil {
+		code := "[REPOSITORY] SuggestTags - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// GetClosestSearchTerm implements SearchRepository.
+// It returns word itself when live contents use it, otherwise the most similar word they
+// use, or "" when none is similar enough.
+func (s *searchRepository) GetClosestSearchTerm(ctx context.Context, word string) (string, error) {
+	var terms []string
+
+	err := s.db.Table("content_search_terms").
+		Where("word % ?", word).
+		Order(gorm.Expr("word = ? DESC, similarity(word, ?) DESC, ndoc DESC", word, word)).
+		Limit(1).
+		Pluck("word", &terms).Error
+	if err != nil {
+		code := "[REPOSITORY] GetClosestSearchTerm - 1"
+		log.Errorw(code, err)
+		return "", err
+	}
+
+	if len(terms) == 0 {
+		return "", nil
+	}
+
+	return terms[0], nil
+}
+
+// RefreshSearchTerms implements SearchRepository.
+// Searches keep using the previous terms while they are collected.
+func (s *searchRepository) RefreshSearchTerms(ctx context.Context) error {
+	err := s.db.Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY content_search_terms").Error
+	if err != nil {
+		code := "[REPOSITORY] RefreshSearchTerms - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/domain/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, req entity.SessionEntity) error
+	GetActiveSessionsByUserID(ctx context.Context, userID int64) ([]entity.SessionEntity, error)
+	GetSessionByAccessTokenJTI(ctx context.Context, jti string) (*entity.SessionEntity, error)
+	TouchSession(ctx context.Context, id int64, minInterval time.Duration) error
+	ExtendSession(ctx context.Context, familyID string, expiresAt time.Time) error
+	RevokeSession(ctx context.Context, id, userID int64) error
+	RevokeUserSessions(ctx context.Context, userID int64, exceptFamilyID string) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// CreateSession implements SessionRepository.
+func (s *sessionRepository) CreateSession(ctx context.Context, req entity.SessionEntity) error {
+	now := time.Now()
+	modelSession := model.Session{
+		UserID:     req.UserID,
+		FamilyID:   req.FamilyID,
+		UserAgent:  req.UserAgent,
+		IP:         req.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  req.ExpiresAt,
+	}
+
+	err := s.db.Create(&modelSession).Error
+	if err != nil {
+		code := "[REPOSITORY] CreateSession - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetActiveSessionsByUserID implements SessionRepository.
+// Signed out and expired sessions are left out, the most recently seen session comes first.
+func (s *sessionRepository) GetActiveSessionsByUserID(ctx context.Context, userID int64) ([]entity.SessionEntity, error) {
+	var modelSessions []model.Session
+
+	err := s.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&modelSessions).Error
+	if err != nil {
+		code := "[REPOSITORY] GetActiveSessionsByUserID - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := []entity.SessionEntity{}
+	for _, v := range modelSessions {
+		resps = append(resps, toSessionEntity(v))
+	}
+
+	return resps, nil
+}
+
+// GetSessionByAccessTokenJTI implements SessionRepository.
+// The session is found through the refresh token the access token was issued with.
+func (s *sessionRepository) GetSessionByAccessTokenJTI(ctx context.Context, jti string) (*entity.SessionEntity, error) {
+	var modelSession model.Session
+
+	err := s.db.Joins("JOIN refresh_tokens ON refresh_tokens.family_id = sessions.family_id").
+		Where("refresh_tokens.access_token_jti = ?", jti).
+		First(&modelSession).Error
+	if err != nil {
+		code := "[REPOSITORY] GetSessionByAccessTokenJTI - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrSessionNotFound)
+	}
+
+	resp := toSessionEntity(modelSession)
+
+	return &resp, nil
+}
+
+// TouchSession implements SessionRepository.
+// last_seen_at is written at most once per minInterval to keep authenticated requests cheap.
+func (s *sessionRepository) TouchSession(ctx context.Context, id int64, minInterval time.Duration) error {
+	now := time.Now()
+	err := s.db.Model(&model.Session{}).
+		Where("id = ? AND last_seen_at < ?", id, now.Add(-minInterval)).
+		Update("last_seen_at", now).Error
+	if err != nil {
+		code := "[REPOSITORY] TouchSession - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// ExtendSession implements SessionRepository.
+// It is called when the session's refresh token is rotated.
+func (s *sessionRepository) ExtendSession(ctx context.Context, familyID string, expiresAt time.Time) error {
+	err := s.db.Model(&model.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Updates(map[string]interface{}{"expires_at": expiresAt, "last_seen_at": time.Now()}).Error
+	if err != nil {
+		code := "[REPOSITORY] ExtendSession - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeSession implements SessionRepository.
+// The session and its refresh tokens are revoked in one transaction. ErrSessionNotFound
+// is returned when the user has no active session with that ID.
+func (s *sessionRepository) RevokeSession(ctx context.Context, id, userID int64) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var modelSession model.Session
+		now := time.Now()
+
+		result := tx.Raw(`UPDATE sessions SET revoked_at = ?
+			WHERE id = ? AND user_id = ? AND revoked_at IS NULL
+			RETURNING id, family_id`, now, id, userID).Scan(&modelSession)
+		if result.Error != nil {
+			code := "[REPOSITORY] RevokeSession - 1"
+			log.Errorw(code, result.Error)
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrSessionNotFound
+		}
+
+		err := tx.Model(&model.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", modelSession.FamilyID).
+			Update("revoked_at", now).Error
+		if err != nil {
+			code := "[REPOSITORY] RevokeSession - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// RevokeUserSessions implements SessionRepository.
+// Every active session of the user and its refresh tokens are revoked, except the session of
+// exceptFamilyID when it is not empty.
+func (s *sessionRepository) RevokeUserSessions(ctx context.Context, userID int64, exceptFamilyID string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		sessions := tx.Model(&model.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+		tokens := tx.Model(&model.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+		if exceptFamilyID != "" {
+			sessions = sessions.Where("family_id <> ?", exceptFamilyID)
+			tokens = tokens.Where("family_id <> ?", exceptFamilyID)
+		}
+
+		if err := sessions.Update("revoked_at", now).Error; err != nil {
+			code := "[REPOSITORY] RevokeUserSessions - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := tokens.Update("revoked_at", now).Error; err != nil {
+			code := "[REPOSITORY] RevokeUserSessions - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// toSessionEntity maps a session row to its entity.
+func toSessionEntity(modelSession model.Session) entity.SessionEntity {
+	return entity.SessionEntity{
+		ID:         modelSession.ID,
+		UserID:     modelSession.UserID,
+		FamilyID:   modelSession.FamilyID,
+		UserAgent:  modelSession.UserAgent,
+		IP:         modelSession.IP,
+		CreatedAt:  modelSession.CreatedAt,
+		LastSeenAt: modelSession.LastSeenAt,
+		ExpiresAt:  modelSession.ExpiresAt,
+		RevokedAt:  modelSession.RevokedAt,
+	}
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/domain/model"
+	"portal-blog/lib/conv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository interface {
+	GetTags(ctx context.Context, live bool) ([]entity.TagEntity, error)
+	GetTagByID(ctx context.Context, id int64) (*entity.TagEntity, error)
+	GetTagBySlug(ctx context.Context, slug string) (*entity.TagEntity, error)
+	CreateTag(ctx context.Context, req entity.TagEntity) (*entity.TagEntity, error)
+	UpdateTag(ctx context.Context, req entity.TagEntity) error
+	DeleteTag(ctx context.Context, id int64) error
+	MergeTags(ctx context.Context, sourceID, targetID int64) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// tagColumns selects a tag with the number of contents that have it.
+const tagColumns = "tags.*, (SELECT COUNT(*) FROM content_tags WHERE content_tags.tag_id = tags.id) AS content_count"
+
+// insertTagSQL inserts a tag with the title and slug given as parameters. A tag whose title has
+// nothing to transliterate comes with an empty slug and gets one made from its ID instead.
+const insertTagSQL = "WITH next AS (SELECT nextval(pg_get_serial_sequence('tags', 'id')) AS id) " +
+	"INSERT INTO tags (id, title, slug) SELECT id, ?, COALESCE(NULLIF(?, ''), 'tag-' || id) FROM next"
+
+// GetTags implements TagRepository.
+// With live only the tags of contents the public can read are returned, the most used first,
+// and only those contents are counted. Otherwise every tag is returned by title.
+func (t *tagRepository) GetTags(ctx context.Context, live bool) ([]entity.TagEntity, error) {
+	var modelTags []model.Tag
+
+	query := t.db.Model(&model.Tag{})
+	if live {
+		query = query.Select("tags.*, COUNT(*) AS content_count").
+			Joins("JOIN content_tags ON content_tags.tag_id = tags.id").
+			Joins("JOIN contents ON contents.id = content_tags.content_id").
+			Scopes(liveContents(time.Now())).
+			Group("tags.id").
+			Order("content_count DESC, tags.title")
+	} else {
+		query = query.Select(tagColumns).Order("tags.title")
+	}
+
+	err := query.Find(&modelTags).Error
+	if err != nil {
+		code := "[REPOSITORY] GetTags - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	tags := make([]entity.TagEntity, 0, len(modelTags))
+	for _, modelTag := range modelTags {
+		tags = append(tags, tagEntity(modelTag))
+	}
+
+	return tags, nil
+}
+
+// GetTagByID implements TagRepository.
+func (t *tagRepository) GetTagByID(ctx context.Context, id int64) (*entity.TagEntity, error) {
+	var modelTag model.Tag
+
+	err := t.db.Select(tagColumns).Where("id = ?", id).Take(&modelTag).Error
+	if err != nil {
+		code := "[REPOSITORY] GetTagByID - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrTagNotFound)
+	}
+
+	tag := tagEntity(modelTag)
+
+	return &tag, nil
+}
+
+// GetTagBySlug implements TagRepository.
+func (t *tagRepository) GetTagBySlug(ctx context.Context, slug string) (*entity.TagEntity, error) {
+	var modelTag model.Tag
+
+	err := t.db.Select(tagColumns).Where("slug = ?", slug).Take(&modelTag).Error
+	if err != nil {
+		code := "[REPOSITORY] GetTagBySlug - 1"
+		log.Errorw(code, err)
+		return nil, translateError(err, ErrTagNotFound)
+	}
+
+	tag := tagEntity(modelTag)
+
+	return &tag, nil
+}
+
+// CreateTag implements TagRepository.
+func (t *tagRepository) CreateTag(ctx context.Context, req entity.TagEntity) (*entity.TagEntity, error) {
+	var modelTag model.Tag
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(insertTagSQL+" RETURNING *", req.Title, req.Slug).Scan(&modelTag).Error; err != nil {
+			code := "[REPOSITORY] CreateTag - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionCreate, entity.AuditEntityTag, modelTag.ID, nil, tagAuditState(modelTag))
+	})
+	if err != nil {
+		return nil, translateError(err, nil)
+	}
+
+	tag := tagEntity(modelTag)
+
+	return &tag, nil
+}
+
+// UpdateTag implements TagRepository.
+// Renaming a tag renames it in the tags of every content that has it.
+func (t *tagRepository) UpdateTag(ctx context.Context, req entity.TagEntity) error {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var before model.Tag
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", req.ID).Take(&before).Error; err != nil {
+			code := "[REPOSITORY] UpdateTag - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		err := tx.Model(&model.Tag{}).Where("id = ?", req.ID).Updates(map[string]interface{}{
+			"title":      req.Title,
+			"slug":       req.Slug,
+			"updated_at": time.Now(),
+		}).Error
+		if err != nil {
+			code := "[REPOSITORY] UpdateTag - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		contentIDs, err := taggedContentIDs(tx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := syncContentTagsColumn(tx, contentIDs); err != nil {
+			return err
+		}
+
+		after := before
+		after.Title = req.Title
+		after.Slug = req.Slug
+
+		return writeAuditLog(ctx, tx, entity.AuditActionUpdate, entity.AuditEntityTag, req.ID, tagAuditState(before), tagAuditState(after))
+	})
+	if err != nil {
+		return translateError(err, ErrTagNotFound)
+	}
+
+	return nil
+}
+
+// DeleteTag implements TagRepository.
+// The tag is removed from every content that has it.
+func (t *tagRepository) DeleteTag(ctx context.Context, id int64) error {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var before model.Tag
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Take(&before).Error; err != nil {
+			code := "[REPOSITORY] DeleteTag - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		contentIDs, err := taggedContentIDs(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("id = ?", id).Delete(&model.Tag{}).Error; err != nil {
+			code := "[REPOSITORY] DeleteTag - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := syncContentTagsColumn(tx, contentIDs); err != nil {
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionDelete, entity.AuditEntityTag, id, tagAuditState(before), nil)
+	})
+	if err != nil {
+		return translateError(err, ErrTagNotFound)
+	}
+
+	return nil
+}
+
+// MergeTags implements TagRepository.
+// The contents tagged with the source tag get the target tag in its place, where they do not
+// have it already, and the source tag is deleted.
+func (t *tagRepository) MergeTags(ctx context.Context, sourceID, targetID int64) error {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var tags []model.Tag
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", []int64{sourceID, targetID}).Order("id").Find(&tags).Error
+		if err != nil {
+			code := "[REPOSITORY] MergeTags - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if len(tags) != 2 {
+			return ErrTagNotFound
+		}
+
+		source := tags[0]
+		if source.ID != sourceID {
+			source = tags[1]
+		}
+
+		contentIDs, err := taggedContentIDs(tx, sourceID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(
+			"INSERT INTO content_tags (content_id, tag_id, position) SELECT content_id, ?, position FROM content_tags WHERE tag_id = ? "+
+				"ON CONFLICT (content_id, tag_id) DO UPDATE SET position = LEAST(content_tags.position, EXCLUDED.position)",
+			targetID, sourceID,
+		).Error
+		if err != nil {
+			code := "[REPOSITORY] MergeTags - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := tx.Where("id = ?", sourceID).Delete(&model.Tag{}).Error; err != nil {
+			code := "[REPOSITORY] MergeTags - 3"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := syncContentTagsColumn(tx, contentIDs); err != nil {
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionMerge, entity.AuditEntityTag, sourceID, tagAuditState(source), auditState{"merged_into_id": targetID})
+	})
+	if err != nil {
+		return translateError(err, ErrTagNotFound)
+	}
+
+	return nil
+}
+
+// setContentTags gives a content the tags titled titles, in that order, creating the tags that
+// do not exist yet. A title matches the tag with the same slug or, for titles without one, the
+// same title in any case; a tag named twice is kept once. It returns the new contents.tags.
+func setContentTags(tx *gorm.DB, contentID int64, titles []string) (string, error) {
+	contentTags := []model.ContentTag{}
+	tagTitles := []string{}
+	seen := map[int64]bool{}
+	for _, title := range titles {
+		slug := conv.Slugify(title)
+
+		var tag model.Tag
+		err := tx.Where("slug = ? OR lower(title) = lower(?)", slug, title).
+			Order(gorm.Expr("slug = ? DESC", slug)).
+			Take(&tag).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// A tag created concurrently with the same slug is taken instead.
+			err = tx.Raw(insertTagSQL+" ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug RETURNING *", title, slug).Scan(&tag).Error
+		}
+		if err != nil {
+			code := "[REPOSITORY] setContentTags - 1"
+			log.Errorw(code, err)
+			return "", err
+		}
+
+		if seen[tag.ID] {
+			continue
+		}
+		seen[tag.ID] = true
+		contentTags = append(contentTags, model.ContentTag{ContentID: contentID, TagID: tag.ID, Position: len(contentTags) + 1})
+		tagTitles = append(tagTitles, tag.Title)
+	}
+
+	if err := tx.Where("content_id = ?", contentID).Delete(&model.ContentTag{}).Error; err != nil {
+		code := "[REPOSITORY] setContentTags - 2"
+		log.Errorw(code, err)
+		return "", err
+	}
+
+	if len(contentTags) > 0 {
+		if err := tx.Create(&contentTags).Error; err != nil {
+			code := "[REPOSITORY] setContentTags - 3"
+			log.Errorw(code, err)
+			return "", err
+		}
+	}
+
+	tags := strings.Join(tagTitles, ",")
+	if err := tx.Model(&model.Content{}).Where("id = ?", contentID).UpdateColumn("tags", tags).Error; err != nil {
+		code := "[REPOSITORY] setContentTags - 4"
+		log.Errorw(code, err)
+		return "", err
+	}
+
+	return tags, nil
+}
+
+// syncContentTagsColumn stores the titles of the tags of the given contents, comma-joined, in
+// contents.tags, which the full text index and the search terms are built from.
+func syncContentTagsColumn(tx *gorm.DB, contentIDs []int64) error {
+	if len(contentIDs) == 0 {
+		return nil
+	}
+
+	err := tx.Exec(
+		"UPDATE contents SET tags = COALESCE((SELECT string_agg(tags.title, ',' ORDER BY content_tags.position) "+
+			"FROM content_tags JOIN tags ON tags.id = content_tags.tag_id WHERE content_tags.content_id = contents.id), '') "+
+			"WHERE id IN ?",
+		contentIDs,
+	).Error
+	if err != nil {
+		code := "[REPOSITORY] syncContentTagsColumn - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// taggedContentIDs returns the IDs of the contents that have the tag.
+func taggedContentIDs(tx *gorm.DB, tagID int64) ([]int64, error) {
+	var contentIDs []int64
+
+	err := tx.Model(&model.ContentTag{}).Where("tag_id = ?", tagID).Pluck("content_id", &contentIDs).Error
+	if err != nil {
+		code := "[REPOSITORY] taggedContentIDs - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return contentIDs, nil
+}
+
+func tagEntity(modelTag model.Tag) entity.TagEntity {
+	return entity.TagEntity{
+		ID:           modelTag.ID,
+		Title:        modelTag.Title,
+		Slug:         modelTag.Slug,
+		ContentCount: modelTag.ContentCount,
+		CreatedAt:    modelTag.CreatedAt,
+	}
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
@@ -2,16 +2,27 @@ package repository
 
 import (
 	"context"
+	"math"
 	"portal-blog/internal/core/domain/entity"
 	"portal-blog/internal/core/domain/model"
+	"time"
 
 	"github.com/gofiber/fiber/v2/log"
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
-	UpdatePassword(ctx context.Context, newPass string, id int64) error
+	UpdatePassword(ctx context.Context, newPass string, id int64, historySize int) error
 	GetUserByID(ctx context.Context, id int64) (*entity.UserEntity, error)
+	GetPasswordHistories(ctx context.Context, id int64, limit int) ([]string, error)
+
+	GetUsers(ctx context.Context, query entity.UserQueryString) ([]entity.UserEntity, int64, int64, error)
+	CountUsersByEmail(ctx context.Context, email string, excludeID int64) (int64, error)
+	CountUserOwnedRecords(ctx context.Context, id int64) (int64, error)
+	CreateUser(ctx context.Context, req entity.UserEntity) error
+	UpdateUser(ctx context.Context, req entity.UserEntity) error
+	UpdateUserStatus(ctx context.Context, id int64, isActive bool) error
+	DeleteUser(ctx context.Context, id int64, reassignTo int64) error
 }
 
 type userRepository struct {
@@ -29,38 +40,374 @@ type userRepository struct {
 //   - An error if the user is not found or if there is an issue with the database query.
 func (u *userRepository) GetUserByID(ctx context.Context, id int64) (*entity.UserEntity, error) {
 	var modelUser model.User
-	err = u.db.Where("id = ?", id).First(&modelUser).Error
+	err := u.db.Where("id = ?", id).First(&modelUser).Error
 	if err != nil {
 		code := "[REPOSITORY] GetUserByID - 1"
 		log.Errorw(code, err)
-		return nil, err
+		return nil, translateError(err, ErrUserNotFound)
 	}
 
 	return &entity.UserEntity{
-		ID:    id,
-		Name:  modelUser.Name,
-		Email: modelUser.Email,
+		ID:        id,
+		Name:      modelUser.Name,
+		Email:     modelUser.Email,
+		Password:  modelUser.Password,
+		Role:      modelUser.Role,
+		IsActive:  modelUser.IsActive,
+		CreatedAt: modelUser.CreatedAt,
 	}, nil
 }
 
 // UpdatePassword updates the password of a user in the database.
+// The new password is also recorded in the user's password history, which is trimmed
+// to the most recent historySize entries, and the change is audited, in the same transaction.
 //
 // Parameters:
 //   - ctx: The context for handling request cancellations and timeouts.
 //   - newPass: The new password to be set.
 //   - id: The unique identifier of the user whose password is being updated.
+//   - historySize: The number of password history entries to keep.
 //
 // Returns:
 //   - An error if the update fails.
-func (u *userRepository) UpdatePassword(ctx context.Context, newPass string, id int64) error {
-	err = u.db.Model(&model.User{}).Where("id = ?", id).Update("password", newPass).Error
+func (u *userRepository) UpdatePassword(ctx context.Context, newPass string, id int64, historySize int) error {
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.User{}).Where("id = ?", id).Update("password", newPass).Error
+		if err != nil {
+			code := "[REPOSITORY] UpdatePassword - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		err = tx.Create(&model.PasswordHistory{UserID: id, Password: newPass}).Error
+		if err != nil {
+			code := "[REPOSITORY] UpdatePassword - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		keep := tx.Model(&model.PasswordHistory{}).
+			Select("id").
+			Where("user_id = ?", id).
+			Order("created_at desc, id desc").
+			Limit(historySize)
+
+		err = tx.Where("user_id = ? AND id NOT IN (?)", id, keep).Delete(&model.PasswordHistory{}).Error
+		if err != nil {
+			code := "[REPOSITORY] UpdatePassword - 3"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionPasswordChange, entity.AuditEntityUser, id, nil, nil)
+	})
+
+	return translateError(err, ErrUserNotFound)
+}
+
+// GetPasswordHistories returns the most recent password hashes of a user, newest first.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - id: The unique identifier of the user.
+//   - limit: The maximum number of hashes to return.
+//
+// Returns:
+//   - The password hashes.
+//   - An error if the query fails.
+func (u *userRepository) GetPasswordHistories(ctx context.Context, id int64, limit int) ([]string, error) {
+	var passwords []string
+
+	err := u.db.Model(&model.PasswordHistory{}).
+		Where("user_id = ?", id).
+		Order("created_at desc, id desc").
+		Limit(limit).
+		Pluck("password", &passwords).Error
+	if err != nil {
+		code := "[REPOSITORY] GetPasswordHistories - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return passwords, nil
+}
+
+// GetUsers retrieves a page of users, optionally filtered by a name/email search, role and active flag.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - query: The pagination and filter options.
+//
+// Returns:
+//   - The users on the requested page.
+//   - The total number of users matching the filters.
+//   - The total number of pages.
+//   - An error if the query fails.
+func (u *userRepository) GetUsers(ctx context.Context, query entity.UserQueryString) ([]entity.UserEntity, int64, int64, error) {
+	var modelUsers []*model.User
+	var countData int64
+
+	offset := (query.Page - 1) * query.Limit
+
+	sqlMain := u.db.Model(&model.User{}).
+		Where("name ILIKE ? OR email ILIKE ?", "%"+query.Search+"%", "%"+query.Search+"%")
+
+	if query.Role != "" {
+		sqlMain = sqlMain.Where("role = ?", query.Role)
+	}
+
+	if query.IsActive != nil {
+		sqlMain = sqlMain.Where("is_active = ?", *query.IsActive)
+	}
+
+	err := sqlMain.Count(&countData).Error
+	if err != nil {
+		code := "[REPOSITORY] GetUsers - 1"
+		log.Errorw(code, err)
+		return nil, 0, 0, err
+	}
+
+	totalPages := int(math.Ceil(float64(countData) / float64(query.Limit)))
+
+	err = sqlMain.
+		Order("created_at desc").
+		Limit(query.Limit).
+		Offset(offset).
+		Find(&modelUsers).Error
+	if err != nil {
+		code := "[REPOSITORY] GetUsers - 2"
+		log.Errorw(code, err)
+		return nil, 0, 0, err
+	}
+
+	var users []entity.UserEntity
+	for _, v := range modelUsers {
+		users = append(users, entity.UserEntity{
+			ID:        v.ID,
+			Name:      v.Name,
+			Email:     v.Email,
+			Role:      v.Role,
+			IsActive:  v.IsActive,
+			CreatedAt: v.CreatedAt,
+		})
+	}
+
+	return users, countData, int64(totalPages), nil
+}
+
+// CountUsersByEmail counts the users using the given email, ignoring the user with excludeID.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - email: The email address to look up.
+//   - excludeID: A user ID to leave out of the count, or 0 to count every user.
+//
+// Returns:
+//   - The number of matching users.
+//   - An error if the query fails.
+func (u *userRepository) CountUsersByEmail(ctx context.Context, email string, excludeID int64) (int64, error) {
+	var count int64
+	err := u.db.Model(&model.User{}).Where("email = ? AND id <> ?", email, excludeID).Count(&count).Error
+	if err != nil {
+		code := "[REPOSITORY] CountUsersByEmail - 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountUserOwnedRecords counts the contents and categories created by the given user.
+// These rows are removed together with the user unless they are reassigned first.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - id: The unique identifier of the user.
+//
+// Returns:
+//   - The number of contents and categories owned by the user.
+//   - An error if the query fails.
+func (u *userRepository) CountUserOwnedRecords(ctx context.Context, id int64) (int64, error) {
+	var countContents, countCategories int64
+	err := u.db.Model(&model.Content{}).Where("created_by_id = ?", id).Count(&countContents).Error
 	if err != nil {
-		code := "[REPOSITORY] UpdatePassword - 1"
+		code := "[REPOSITORY] CountUserOwnedRecords - 1"
 		log.Errorw(code, err)
-		return err
+		return 0, err
+	}
+
+	err = u.db.Model(&model.Category{}).Where("created_by_id = ?", id).Count(&countCategories).Error
+	if err != nil {
+		code := "[REPOSITORY] CountUserOwnedRecords - 2"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return countContents + countCategories, nil
+}
+
+// CreateUser inserts a new active user. The password must already be hashed.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - req: The user to create.
+//
+// Returns:
+//   - An error if the insert fails.
+func (u *userRepository) CreateUser(ctx context.Context, req entity.UserEntity) error {
+	modelUser := model.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+		Role:     req.Role,
+		IsActive: true,
+	}
+
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&modelUser).Error; err != nil {
+			code := "[REPOSITORY] CreateUser - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionCreate, entity.AuditEntityUser, modelUser.ID, nil, userAuditState(modelUser))
+	})
+
+	return translateError(err, ErrUserNotFound)
+}
+
+// UpdateUser updates the name, email and, when provided, the role of a user.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - req: The user ID and the new values.
+//
+// Returns:
+//   - ErrUserNotFound if the user does not exist, or any database error.
+func (u *userRepository) UpdateUser(ctx context.Context, req entity.UserEntity) error {
+	modelUser := model.User{
+		Name:  req.Name,
+		Email: req.Email,
+		Role:  req.Role,
 	}
 
-	return nil
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		var before, after model.User
+		if err := tx.Where("id = ?", req.ID).Take(&before).Error; err != nil {
+			code := "[REPOSITORY] UpdateUser - 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := tx.Where("id = ?", req.ID).Updates(&modelUser).Error; err != nil {
+			code := "[REPOSITORY] UpdateUser - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := tx.Where("id = ?", req.ID).Take(&after).Error; err != nil {
+			code := "[REPOSITORY] UpdateUser - 3"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionUpdate, entity.AuditEntityUser, req.ID, userAuditState(before), userAuditState(after))
+	})
+
+	return translateError(err, ErrUserNotFound)
+}
+
+// UpdateUserStatus activates or deactivates a user.
+// Deactivating a user also revokes all of their refresh tokens so no session can be renewed.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - id: The unique identifier of the user.
+//   - isActive: The new value of the active flag.
+//
+// Returns:
+//   - ErrUserNotFound if the user does not exist, or any database error.
+func (u *userRepository) UpdateUserStatus(ctx context.Context, id int64, isActive bool) error {
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).Where("id = ?", id).Update("is_active", isActive)
+		if result.Error != nil {
+			code := "[REPOSITORY] UpdateUserStatus - 1"
+			log.Errorw(code, result.Error)
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if isActive {
+			return writeAuditLog(ctx, tx, entity.AuditActionActivate, entity.AuditEntityUser, id, nil, auditState{"is_active": true})
+		}
+
+		err := tx.Model(&model.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", time.Now()).Error
+		if err != nil {
+			code := "[REPOSITORY] UpdateUserStatus - 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionDeactivate, entity.AuditEntityUser, id, nil, auditState{"is_active": false})
+	})
+
+	return translateError(err, ErrUserNotFound)
+}
+
+// DeleteUser deletes a user after moving their contents and categories to another user.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - id: The unique identifier of the user to delete.
+//   - reassignTo: The user receiving the deleted user's contents and categories, or 0 to skip reassignment.
+//
+// Returns:
+//   - ErrUserNotFound if the user does not exist, or any database error.
+func (u *userRepository) DeleteUser(ctx context.Context, id int64, reassignTo int64) error {
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		if reassignTo > 0 {
+			err := tx.Model(&model.Content{}).Where("created_by_id = ?", id).Update("created_by_id", reassignTo).Error
+			if err != nil {
+				code := "[REPOSITORY] DeleteUser - 1"
+				log.Errorw(code, err)
+				return err
+			}
+
+			err = tx.Model(&model.Category{}).Where("created_by_id = ?", id).Update("created_by_id", reassignTo).Error
+			if err != nil {
+				code := "[REPOSITORY] DeleteUser - 2"
+				log.Errorw(code, err)
+				return err
+			}
+		}
+
+		var before model.User
+		if err := tx.Where("id = ?", id).Take(&before).Error; err != nil {
+			code := "[REPOSITORY] DeleteUser - 3"
+			log.Errorw(code, err)
+			return err
+		}
+
+		if err := tx.Where("id = ?", id).Delete(&model.User{}).Error; err != nil {
+			code := "[REPOSITORY] DeleteUser - 4"
+			log.Errorw(code, err)
+			return err
+		}
+
+		state := userAuditState(before)
+		if reassignTo > 0 {
+			state["reassigned_to"] = reassignTo
+		}
+
+		return writeAuditLog(ctx, tx, entity.AuditActionDelete, entity.AuditEntityUser, id, state, nil)
+	})
+
+	return translateError(err, ErrUserNotFound)
 }
 
 // NewUserRepository creates a new instance of userRepository.
@@ -6,12 +6,19 @@ import (
 	"os/signal"
 	"portal-blog/config"
 	"portal-blog/internal/adapter/cloudflare"
+	"portal-blog/internal/adapter/event"
 	"portal-blog/internal/adapter/handler"
+	"portal-blog/internal/adapter/mailer"
+	"portal-blog/internal/adapter/oidc"
 	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/port"
 	"portal-blog/internal/core/service"
 	"portal-blog/lib/auth"
 	"portal-blog/lib/middleware"
 	"portal-blog/lib/pagination"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -21,6 +28,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/rs/zerolog/log"
 )
 
@@ -28,7 +36,11 @@ import (
 // It sets up the configuration, establishes a database connection,
 // and handles any errors that occur during the process.
 func RunServer() {
-	cfg := config.NewConfig()
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatal().Msgf("Error loading configuration: %v", err)
+		return
+	}
 
 	db, err := cfg.ConnectionPostgres()
 
@@ -48,33 +60,195 @@ func RunServer() {
 	s3Client := s3.NewFromConfig(cdfR2)
 	r2Adapter := cloudflare.NewCloudflareR2Adapter(s3Client, cfg)
 
-	jwt := auth.NewJwt(cfg)
-	middlewareAuth := middleware.NewMiddleware(cfg)
+	jwt, err := auth.NewJwt(cfg)
+	if err != nil {
+		log.Fatal().Msgf("Error loading JWT signing keys: %v", err)
+		return
+	}
+
+	// Token revocation
+	var revocationStore auth.RevocationStore
+	switch cfg.App.TokenRevocationStore {
+	case "memory":
+		revocationStore = auth.NewMemoryRevocationStore()
+	default:
+		revocationStore = repository.NewRevokedTokenRepository(db.DB)
+	}
+
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	// background tracks the goroutines started with runBackground so shutdown can wait for
+	// them to return instead of exiting in the middle of a cleanup or scheduler run.
+	var background sync.WaitGroup
+	runBackground := func(run func()) {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			run()
+		}()
+	}
+
+	runBackground(func() {
+		auth.RunRevocationCleanup(bgCtx, revocationStore, cfg.App.TokenRevocationCleanupInterval)
+	})
+
+	// Mailer
+	var mail port.Mailer
+	switch cfg.Mail.Driver {
+	case "smtp":
+		mail = mailer.NewSMTPMailer(cfg)
+	default:
+		mail, err = mailer.NewLogMailer(cfg.Mail.LogDir, cfg.Mail.From)
+		if err != nil {
+			log.Fatal().Msgf("Error creating mail log directory: %v", err)
+			return
+		}
+	}
+
+	// Events
+	var events port.EventPublisher
+	switch cfg.Event.Driver {
+	case "webhook":
+		if cfg.Event.WebhookURL == "" {
+			log.Fatal().Msg("EVENT_WEBHOOK_URL is required when EVENT_DRIVER is webhook")
+			return
+		}
+		asyncEvents := event.NewAsyncPublisher(event.NewWebhookPublisher(cfg), cfg)
+		runBackground(func() {
+			asyncEvents.Run(bgCtx)
+		})
+		events = asyncEvents
+	default:
+		events = event.NewLogPublisher()
+	}
+
+	// Login brute-force protection
+	var loginAttemptStore auth.LoginAttemptStore
+	switch cfg.App.LoginAttemptStore {
+	case "memory":
+		loginAttemptStore = auth.NewMemoryLoginAttemptStore()
+	default:
+		loginAttemptStore = repository.NewLoginAttemptRepository(db.DB)
+	}
+	loginThrottle := auth.NewLoginThrottle(loginAttemptStore, cfg)
+	runBackground(func() {
+		auth.RunLoginAttemptCleanup(bgCtx, loginAttemptStore, cfg.App.LoginAttemptWindow, cfg.App.LoginAttemptCleanupInterval)
+	})
+
+	// Single sign-on
+	var identityProvider port.IdentityProvider
+	if cfg.App.OidcIssuerURL != "" {
+		identityProvider = oidc.NewProvider(cfg)
+	}
 
 	_ = pagination.NewPagination()
 
 	// Repository
 	authRepo := repository.NewAuthRepository(db.DB)
 	categoryRepo := repository.NewCategoryRepository(db.DB)
-	contentRepo := repository.NewContentRepository(db.DB)
+	contentRepo := repository.NewContentRepository(db.DB, cfg.App.SearchLanguage)
 	userRepo := repository.NewUserRepository(db.DB)
+	passwordResetRepo := repository.NewPasswordResetRepository(db.DB)
+	mfaRepo := repository.NewMfaRepository(db.DB)
+	roleRepo := repository.NewRoleRepository(db.DB)
+	apiKeyRepo := repository.NewAPIKeyRepository(db.DB)
+	oidcRepo := repository.NewOidcRepository(db.DB)
+	sessionRepo := repository.NewSessionRepository(db.DB)
+	invitationRepo := repository.NewInvitationRepository(db.DB)
+	auditLogRepo := repository.NewAuditLogRepository(db.DB)
+	searchRepo := repository.NewSearchRepository(db.DB)
+	tagRepo := repository.NewTagRepository(db.DB)
+
+	// Search index
+	reindexed, err := contentRepo.SyncSearchLanguage(context.Background())
+	if err != nil {
+		log.Fatal().Msgf("Error applying search language %q: %v", cfg.App.SearchLanguage, err)
+		return
+	}
+	if reindexed > 0 {
+		log.Info().Msgf("Re-indexed %d contents for search language %s", reindexed, cfg.App.SearchLanguage)
+	}
 
 	// Service
-	authService := service.NewAuthService(authRepo, cfg, jwt)
+	mfaService := service.NewMfaService(mfaRepo, userRepo, roleRepo, cfg)
+	roleService := service.NewRoleService(roleRepo)
+	err = roleService.RefreshRolePermissions(context.Background())
+	if err != nil {
+		log.Fatal().Msgf("Error loading role permissions: %v", err)
+		return
+	}
+	runBackground(func() {
+		service.RunRolePermissionsRefresh(bgCtx, roleService, cfg.App.RolePermissionsRefreshInterval)
+	})
+	authService := service.NewAuthService(authRepo, sessionRepo, cfg, jwt, revocationStore, mfaService, loginThrottle)
 	categoryService := service.NewCategoryService(categoryRepo)
-	contentService := service.NewContentService(contentRepo, cfg, r2Adapter)
-	userService := service.NewUserService(userRepo)
+	contentService := service.NewContentService(contentRepo, cfg, r2Adapter, events)
+	userService := service.NewUserService(userRepo, authService, cfg)
+	passwordResetService := service.NewPasswordResetService(passwordResetRepo, authRepo, userService, mail, loginThrottle, cfg)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, authRepo)
+	sessionService := service.NewSessionService(sessionRepo, authService)
+	invitationService := service.NewInvitationService(invitationRepo, userRepo, mail, cfg)
+	auditLogService := service.NewAuditLogService(auditLogRepo)
+	searchService := service.NewSearchService(searchRepo)
+	tagService := service.NewTagService(tagRepo)
+	oidcService, err := service.NewOidcService(oidcRepo, authRepo, userRepo, authService, mfaService, identityProvider, cfg)
+	if err != nil {
+		log.Fatal().Msgf("Error configuring single sign-on: %v", err)
+		return
+	}
+
+	runBackground(func() {
+		service.RunContentScheduler(bgCtx, contentService, cfg.App.ContentSchedulerInterval)
+	})
+	runBackground(func() {
+		service.RunSearchTermsRefresh(bgCtx, searchService, cfg.App.SearchTermsRefreshInterval)
+	})
+
+	middlewareAuth := middleware.NewMiddleware(jwt, revocationStore, apiKeyService, sessionService)
 
 	// Handler
 	authHandler := handler.NewAuthHandler(authService)
 	categoryHandler := handler.NewCategoryHandler(categoryService)
-	contentHandler := handler.NewContentHandler(contentService)
+	contentHandler := handler.NewContentHandler(contentService, searchService)
+	searchHandler := handler.NewSearchHandler(searchService)
+	tagHandler := handler.NewTagHandler(tagService)
 	userHandler := handler.NewUserHandler(userService)
+	passwordResetHandler := handler.NewPasswordResetHandler(passwordResetService)
+	mfaHandler := handler.NewMfaHandler(mfaService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	jwksHandler := handler.NewJwksHandler(jwt)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	oidcHandler := handler.NewOidcHandler(oidcService)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	invitationHandler := handler.NewInvitationHandler(invitationService)
+	auditLogHandler := handler.NewAuditLogHandler(auditLogService)
 
 	// Fiber App
-	app := fiber.New()
+	fiberConfig := fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	}
+	if cfg.App.ProxyHeader != "" {
+		var trustedProxies []string
+		for _, proxy := range strings.Split(cfg.App.TrustedProxies, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				trustedProxies = append(trustedProxies, proxy)
+			}
+		}
+		if len(trustedProxies) == 0 {
+			log.Fatal().Msg("APP_TRUSTED_PROXIES is required when APP_PROXY_HEADER is set")
+			return
+		}
+
+		fiberConfig.ProxyHeader = cfg.App.ProxyHeader
+		fiberConfig.EnableTrustedProxyCheck = true
+		fiberConfig.TrustedProxies = trustedProxies
+	}
+
+	app := fiber.New(fiberConfig)
 	app.Use(cors.New())
 	app.Use(recover.New())
+	app.Use(requestid.New())
 	app.Use(logger.New(logger.Config{
 		Format: "[${time}] ${ip} ${status} - ${latency} ${method} ${path}\n",
 	}))
@@ -92,41 +266,119 @@ func RunServer() {
 
 	}
 
+	// Public verification keys for services that validate access tokens
+	app.Get("/.well-known/jwks.json", jwksHandler.GetJwks)
+
 	// Group API
 	api := app.Group("/api")
 	api.Post("/login", authHandler.Login)
+	api.Post("/login/mfa", authHandler.LoginMfa)
+	api.Post("/login/mfa/setup", mfaHandler.SetupChallenge)
+	api.Post("/refresh", authHandler.RefreshToken)
+	api.Post("/password/forgot", passwordResetHandler.ForgotPassword)
+	api.Post("/password/reset", middlewareAuth.AuditContext(), passwordResetHandler.ResetPassword)
+	api.Get("/oidc/login", oidcHandler.Login)
+	api.Post("/oidc/callback", middlewareAuth.AuditContext(), oidcHandler.Callback)
+	api.Post("/invitations/accept", middlewareAuth.AuditContext(), invitationHandler.AcceptInvitation)
 
 	// Group Admin
+	// Changes made under /admin are written to the audit log with the actor recorded by AuditContext.
 	adminApp := api.Group("/admin")
-	adminApp.Use(middlewareAuth.CheckToken())
+	adminApp.Use(middlewareAuth.CheckToken(), middlewareAuth.AuditContext())
+	adminApp.Post("/logout", middlewareAuth.RequireAccessToken(), authHandler.Logout)
 
 	// Group Category
 	categoryApp := adminApp.Group("/category")
-	categoryApp.Get("/", categoryHandler.GetCategories)
-	categoryApp.Post("/", categoryHandler.CreateCategory)
-	categoryApp.Get("/:categoryID", categoryHandler.GetCategoryById)
-	categoryApp.Put("/:categoryID", categoryHandler.EditCategoryById)
-	categoryApp.Delete("/:categoryID", categoryHandler.DeleteCategoryById)
+	categoryApp.Get("/", middlewareAuth.RequirePermission(entity.PermissionCategoryRead), categoryHandler.GetCategories)
+	categoryApp.Post("/", middlewareAuth.RequirePermission(entity.PermissionCategoryWrite), categoryHandler.CreateCategory)
+	categoryApp.Get("/:categoryID", middlewareAuth.RequirePermission(entity.PermissionCategoryRead), categoryHandler.GetCategoryById)
+	categoryApp.Put("/:categoryID", middlewareAuth.RequirePermission(entity.PermissionCategoryWrite), categoryHandler.EditCategoryById)
+	categoryApp.Delete("/:categoryID", middlewareAuth.RequirePermission(entity.PermissionCategoryDelete), categoryHandler.DeleteCategoryById)
+
+	// Group Tag
+	// Writers create tags by using them on contents; renaming, merging and deleting them is editorial.
+	tagApp := adminApp.Group("/tags")
+	tagApp.Get("/", middlewareAuth.RequirePermission(entity.PermissionTagRead), tagHandler.GetTags)
+	tagApp.Post("/", middlewareAuth.RequirePermission(entity.PermissionTagWrite), tagHandler.CreateTag)
+	tagApp.Get("/:tagID", middlewareAuth.RequirePermission(entity.PermissionTagRead), tagHandler.GetTagByID)
+	tagApp.Put("/:tagID", middlewareAuth.RequirePermission(entity.PermissionTagWrite), tagHandler.UpdateTag)
+	tagApp.Delete("/:tagID", middlewareAuth.RequirePermission(entity.PermissionTagDelete), tagHandler.DeleteTag)
+	tagApp.Post("/:tagID/merge", middlewareAuth.RequirePermission(entity.PermissionTagDelete), tagHandler.MergeTags)
 
 	// Group Content
+	// Roles without content:manage_all may only update or delete their own contents, which contentService enforces.
 	contentApp := adminApp.Group("/content")
-	contentApp.Get("/", contentHandler.GetContents)
-	contentApp.Get("/:contentID", contentHandler.GetContentByID)
-	contentApp.Post("/", contentHandler.CreateContent)
-	contentApp.Put("/:contentID", contentHandler.UpdateContent)
-	contentApp.Post("/upload-image", contentHandler.UploadImageR2)
-	contentApp.Delete("/:contentID", contentHandler.DeleteContent)
+	contentApp.Get("/", middlewareAuth.RequirePermission(entity.PermissionContentRead), contentHandler.GetContents)
+	contentApp.Get("/:contentID", middlewareAuth.RequirePermission(entity.PermissionContentRead), contentHandler.GetContentByID)
+	contentApp.Post("/", middlewareAuth.RequirePermission(entity.PermissionContentWrite), contentHandler.CreateContent)
+	contentApp.Put("/:contentID", middlewareAuth.RequirePermission(entity.PermissionContentWrite), contentHandler.UpdateContent)
+	contentApp.Post("/upload-image", middlewareAuth.RequirePermission(entity.PermissionContentUpload), contentHandler.UploadImageR2)
+	contentApp.Delete("/:contentID", middlewareAuth.RequirePermission(entity.PermissionContentDelete), contentHandler.DeleteContent)
+
+	// Content workflow. Submitting is open to writers for their own contents; the review steps need content:publish.
+	contentApp.Post("/:contentID/submit", middlewareAuth.RequirePermission(entity.PermissionContentWrite), contentHandler.SubmitContent)
+	contentApp.Post("/:contentID/approve", middlewareAuth.RequirePermission(entity.PermissionContentPublish), contentHandler.ApproveContent)
+	contentApp.Post("/:contentID/reject", middlewareAuth.RequirePermission(entity.PermissionContentPublish), contentHandler.RejectContent)
+	contentApp.Post("/:contentID/publish", middlewareAuth.RequirePermission(entity.PermissionContentPublish), contentHandler.PublishContent)
+	contentApp.Post("/:contentID/archive", middlewareAuth.RequirePermission(entity.PermissionContentPublish), contentHandler.ArchiveContent)
+
+	// Content revisions. Every save is kept; restoring one saves it again as the newest revision.
+	contentApp.Get("/:contentID/revisions", middlewareAuth.RequirePermission(entity.PermissionContentRead), contentHandler.GetContentRevisions)
+	contentApp.Get("/:contentID/revisions/diff", middlewareAuth.RequirePermission(entity.PermissionContentRead), contentHandler.GetContentRevisionDiff)
+	contentApp.Post("/:contentID/revisions/:revision/restore", middlewareAuth.RequirePermission(entity.PermissionContentWrite), contentHandler.RestoreContentRevision)
 
 	// User
-	userApp := adminApp.Group("/user")
+	// Requests made with an API key may use the permissioned routes above but not manage the account.
+	userApp := adminApp.Group("/user", middlewareAuth.RequireAccessToken())
 	userApp.Get("/profile", userHandler.GetUserByID)
 	userApp.Put("/update-password", userHandler.UpdatePassword)
+	userApp.Get("/mfa", mfaHandler.GetStatus)
+	userApp.Post("/mfa/setup", mfaHandler.Setup)
+	userApp.Post("/mfa/enable", mfaHandler.Enable)
+	userApp.Post("/mfa/disable", mfaHandler.Disable)
+	userApp.Post("/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+	userApp.Get("/api-keys", apiKeyHandler.GetAPIKeys)
+	userApp.Post("/api-keys", apiKeyHandler.CreateAPIKey)
+	userApp.Delete("/api-keys/:apiKeyID", apiKeyHandler.RevokeAPIKey)
+	userApp.Get("/sessions", sessionHandler.GetSessions)
+	userApp.Delete("/sessions", sessionHandler.RevokeAllSessions)
+	userApp.Delete("/sessions/:sessionID", sessionHandler.RevokeSession)
+
+	// Users (admin)
+	usersApp := adminApp.Group("/users", middlewareAuth.RequireAccessToken(), middlewareAuth.RequirePermission(entity.PermissionUserManage))
+	usersApp.Get("/", userHandler.GetUsers)
+	usersApp.Post("/", userHandler.CreateUser)
+	usersApp.Get("/:userID", userHandler.GetUserDetail)
+	usersApp.Put("/:userID", userHandler.UpdateUser)
+	usersApp.Put("/:userID/activate", userHandler.ActivateUser)
+	usersApp.Put("/:userID/deactivate", userHandler.DeactivateUser)
+	usersApp.Delete("/:userID", userHandler.DeleteUser)
+	usersApp.Delete("/:userID/mfa", mfaHandler.ResetUserMfa)
+	usersApp.Put("/:userID/unlock", authHandler.UnlockAccount)
+
+	// Roles (admin)
+	rolesApp := adminApp.Group("/roles", middlewareAuth.RequireAccessToken(), middlewareAuth.RequirePermission(entity.PermissionUserManage))
+	rolesApp.Get("/", roleHandler.GetRoles)
+	rolesApp.Put("/:roleName/mfa", roleHandler.UpdateRoleMfa)
+
+	// Invitations (admin)
+	invitationsApp := adminApp.Group("/invitations", middlewareAuth.RequireAccessToken(), middlewareAuth.RequirePermission(entity.PermissionUserManage))
+	invitationsApp.Get("/", invitationHandler.GetInvitations)
+	invitationsApp.Post("/", invitationHandler.CreateInvitation)
+	invitationsApp.Post("/:invitationID/resend", invitationHandler.ResendInvitation)
+	invitationsApp.Delete("/:invitationID", invitationHandler.RevokeInvitation)
+
+	// Audit log (admin)
+	adminApp.Get("/audit-logs", middlewareAuth.RequirePermission(entity.PermissionAuditRead), auditLogHandler.GetAuditLogs)
 
 	// FE
 	feApp := api.Group("/fe")
 	feApp.Get("/category", categoryHandler.GetCategoryFE)
 	feApp.Get("/content", contentHandler.GetContentWithQuery)
 	feApp.Get("/content/:contentID", contentHandler.GetContentDetail)
+	feApp.Get("/content/slug/:slug", contentHandler.GetContentDetailBySlug)
+	feApp.Get("/search/suggest", searchHandler.Suggest)
+	feApp.Get("/tags", tagHandler.GetTagsFE)
 
 	go func() {
 		if cfg.App.AppPort == "" {
@@ -149,5 +401,20 @@ func RunServer() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Stop serving requests before the background jobs, so events published by the last
+	// requests are still delivered by the event queue.
 	app.ShutdownWithContext(ctx)
+	stopBackground()
+
+	backgroundDone := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(backgroundDone)
+	}()
+
+	select {
+	case <-backgroundDone:
+	case <-ctx.Done():
+		log.Warn().Msg("Background jobs did not stop before the shutdown timeout")
+	}
 }
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+type APIKeyEntity struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+
+	// Key is the plain API key. It is only set right after the key is created.
+	Key string
+}
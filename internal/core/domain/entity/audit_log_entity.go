@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	AuditActionCreate         = "create"
+	AuditActionUpdate         = "update"
+	AuditActionDelete         = "delete"
+	AuditActionPasswordChange = "password_change"
+	AuditActionActivate       = "activate"
+	AuditActionDeactivate     = "deactivate"
+	AuditActionMfaDisable     = "mfa_disable"
+	AuditActionResend         = "resend"
+	AuditActionRevoke         = "revoke"
+	AuditActionAccept         = "accept"
+	AuditActionStatusChange   = "status_change"
+	AuditActionRestore        = "restore"
+	AuditActionMerge          = "merge"
+)
+
+const (
+	AuditEntityCategory   = "category"
+	AuditEntityContent    = "content"
+	AuditEntityUser       = "user"
+	AuditEntityRole       = "role"
+	AuditEntityInvitation = "invitation"
+	AuditEntityTag        = "tag"
+)
+
+// AuditLogEntity is one recorded change. Before and After hold only the fields that changed,
+// as JSON objects; Before is empty for creations and After for deletions.
+type AuditLogEntity struct {
+	ID         int64
+	ActorID    *int64
+	APIKeyID   *int64
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     []byte
+	After      []byte
+	IP         string
+	RequestID  string
+	CreatedAt  time.Time
+}
+
+type AuditLogQueryString struct {
+	Limit      int
+	Page       int
+	ActorID    int64
+	Action     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+// AuditActor describes who made a change and from which request. A zero UserID means the
+// change was not made by a logged in user, for example a password reset.
+type AuditActor struct {
+	UserID    int64
+	APIKeyID  int64
+	IP        string
+	RequestID string
+}
+
+type auditActorKey struct{}
+
+// AuditActorContextKey is the key the AuditActor is stored under, both in a context.Context
+// and in Fiber's Locals, which the request context passed to services reads from.
+var AuditActorContextKey = auditActorKey{}
+
+// ContextWithAuditActor returns a copy of ctx carrying the actor, for changes made outside
+// an HTTP request.
+func ContextWithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, AuditActorContextKey, actor)
+}
+
+// AuditActorFromContext returns the actor stored in ctx, or a zero AuditActor.
+func AuditActorFromContext(ctx context.Context) AuditActor {
+	actor, _ := ctx.Value(AuditActorContextKey).(AuditActor)
+	return actor
+}
@@ -1,11 +1,34 @@
 package entity
 
+import "time"
+
 type LoginRequest struct {
-	Email    string
-	Password string
+	Email     string
+	Password  string
+	IP        string
+	UserAgent string
 }
 
 type AccessToken struct {
-	AccessToken string
-	ExpiredAt int64
-}
\ No newline at end of file
+	AccessToken      string
+	ExpiredAt        int64
+	RefreshToken     string
+	RefreshExpiredAt int64
+
+	// MfaChallenge is set instead of the tokens when the login still needs a second factor.
+	MfaChallenge *MfaChallengeEntity
+	// RecoveryCodes is set when MFA enrolment was completed during this login.
+	RecoveryCodes []string
+}
+
+type RefreshTokenEntity struct {
+	ID             int64
+	UserID         int64
+	TokenHash      string
+	FamilyID       string
+	ExpiresAt      time.Time
+	RevokedAt      *time.Time
+	ReplacedByID   *int64
+	AccessTokenJTI string
+	CreatedAt      time.Time
+}
@@ -2,19 +2,48 @@ package entity
 
 import "time"
 
+const (
+	ContentStatusDraft     = "DRAFT"
+	ContentStatusInReview  = "IN_REVIEW"
+	ContentStatusScheduled = "SCHEDULED"
+	ContentStatusPublished = "PUBLISHED"
+	ContentStatusArchived  = "ARCHIVED"
+)
+
+// ContentStatuses lists the statuses of the content workflow.
+var ContentStatuses = []string{
+	ContentStatusDraft, ContentStatusInReview, ContentStatusScheduled, ContentStatusPublished, ContentStatusArchived,
+}
+
+// Content transitions move a content between statuses; contentService decides which are allowed.
+const (
+	ContentTransitionSubmit  = "submit"
+	ContentTransitionApprove = "approve"
+	ContentTransitionReject  = "reject"
+	ContentTransitionPublish = "publish"
+	ContentTransitionArchive = "archive"
+)
+
 type ContentEntity struct {
-	ID          int64
-	Title       string
-	Excerpt     string
-	Description string
-	Image       string
-	Tags        []string
-	Status      string
-	CategoryID  int64
-	CreatedByID int64
-	CreatedAt   time.Time
-	Category    CategoryEntity
-	User        UserEntity
+	ID              int64
+	Title           string
+	Slug            string
+	Excerpt         string
+	Description     string
+	Image           string
+	Tags            []string
+	Status          string
+	RejectionReason string
+	PublishAt       *time.Time
+	UnpublishAt     *time.Time
+	CategoryID      int64
+	CreatedByID     int64
+	CreatedAt       time.Time
+	Category        CategoryEntity
+	User            UserEntity
+	// Headline is a snippet of the description around the search matches, set by searches
+	// that ask for it.
+	Headline string
 }
 
 type QueryString struct {
@@ -25,4 +54,31 @@ type QueryString struct {
 	Search     string
 	CategoryID int64
 	Status     string
+	// Tag limits the result to contents with the tag of this slug.
+	Tag string
+	// Live limits the result to contents the public can read at the time of the query.
+	Live bool
+	// Highlight adds a Headline to every result of a search.
+	Highlight bool
+}
+
+// OrderByRelevance orders search results by how well they match, best first; without a
+// search it falls back to the newest first.
+const OrderByRelevance = "relevance"
+
+// ContentOrderColumns are the columns contents can be ordered by besides OrderByRelevance.
+var ContentOrderColumns = []string{"created_at", "title", "publish_at"}
+
+// IsLive reports whether the public can read the content at the given time: it is published,
+// its publish_at has come and its unpublish_at has not.
+func (c ContentEntity) IsLive(now time.Time) bool {
+	if c.Status != ContentStatusPublished {
+		return false
+	}
+
+	if c.PublishAt != nil && c.PublishAt.After(now) {
+		return false
+	}
+
+	return c.UnpublishAt == nil || c.UnpublishAt.After(now)
 }
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// ContentRevisionEntity is the state of a content after one save. Revision numbers count up
+// from 1 per content; RestoredFrom is set on revisions created by restoring an older one.
+type ContentRevisionEntity struct {
+	ID           int64
+	ContentID    int64
+	Revision     int
+	Title        string
+	Excerpt      string
+	Description  string
+	Image        string
+	Tags         []string
+	CategoryID   int64
+	AuthorID     *int64
+	AuthorName   string
+	RestoredFrom *int
+	CreatedAt    time.Time
+}
+
+// FieldChangeEntity is one field that differs between two revisions.
+type FieldChangeEntity struct {
+	Field string
+	From  interface{}
+	To    interface{}
+}
+
+// ContentRevisionDiffEntity lists the fields that changed from one revision to another.
+type ContentRevisionDiffEntity struct {
+	ContentID int64
+	From      ContentRevisionEntity
+	To        ContentRevisionEntity
+	Changes   []FieldChangeEntity
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Event types emitted through port.EventPublisher.
+const (
+	// EventContentPublished is emitted when a content goes live, either because an editor
+	// published it or because its publish_at came.
+	EventContentPublished = "content.published"
+	// EventContentUnpublished is emitted when a published content is taken down.
+	EventContentUnpublished = "content.unpublished"
+)
+
+// EventEntity is something that happened in the application that other systems may want to
+// react to, such as a cache or a newsletter.
+type EventEntity struct {
+	Type       string                 `json:"type"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
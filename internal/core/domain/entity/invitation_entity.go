@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusRevoked  = "revoked"
+	InvitationStatusExpired  = "expired"
+)
+
+type InvitationEntity struct {
+	ID         int64
+	Email      string
+	Role       string
+	TokenHash  string
+	InvitedBy  *int64
+	UserID     *int64
+	ExpiresAt  time.Time
+	AcceptedAt *time.Time
+	RevokedAt  *time.Time
+	SentAt     time.Time
+	CreatedAt  time.Time
+}
+
+// Status derives the state of the invitation at the given time.
+func (i InvitationEntity) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationStatusAccepted
+	case i.RevokedAt != nil:
+		return InvitationStatusRevoked
+	case !i.ExpiresAt.After(now):
+		return InvitationStatusExpired
+	default:
+		return InvitationStatusPending
+	}
+}
+
+// AcceptInvitationEntity is what an invitee submits to create their account.
+type AcceptInvitationEntity struct {
+	Token    string
+	Name     string
+	Password string
+}
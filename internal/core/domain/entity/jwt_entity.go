@@ -3,6 +3,35 @@ package entity
 import "github.com/golang-jwt/jwt/v5"
 
 type JwtData struct {
-	UserID    float64  `json:"user_id"`
+	UserID float64 `json:"user_id"`
+	Role   string  `json:"role"`
 	jwt.RegisteredClaims
-}
\ No newline at end of file
+
+	// APIKeyID and Scopes are set when the request authenticated with a personal API key
+	// instead of an access token. Empty Scopes means every permission of the role.
+	APIKeyID int64    `json:"-"`
+	Scopes   []string `json:"-"`
+
+	// SessionID is the login session of an access token, set by the middleware.
+	SessionID int64 `json:"-"`
+}
+
+// Can reports whether the caller's role grants the permission and, for API keys with
+// scopes, whether the key was given that scope.
+func (j *JwtData) Can(permission string) bool {
+	if !HasPermission(j.Role, permission) {
+		return false
+	}
+
+	if len(j.Scopes) == 0 {
+		return true
+	}
+
+	for _, scope := range j.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+type UserMfaEntity struct {
+	UserID       int64
+	Secret       string
+	Enabled      bool
+	LastUsedStep int64
+}
+
+type MfaSetupEntity struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+type MfaStatusEntity struct {
+	Enabled  bool
+	Required bool
+}
+
+// MfaChallengeEntity is the pending second step of a login. Token is only set right after
+// the challenge is created; the database keeps its hash.
+type MfaChallengeEntity struct {
+	ID                 int64
+	UserID             int64
+	Token              string
+	TokenHash          string
+	Attempts           int
+	ExpiresAt          time.Time
+	UsedAt             *time.Time
+	EnrollmentRequired bool
+}
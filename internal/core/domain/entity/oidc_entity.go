@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+type OidcStateEntity struct {
+	ID           int64
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	UsedAt       *time.Time
+}
+
+// OidcLoginEntity is where the browser is sent to sign in with the identity provider.
+// State must be kept by the client and sent back with the authorization code.
+type OidcLoginEntity struct {
+	AuthorizationURL string
+	State            string
+	ExpiresAt        time.Time
+}
+
+// OidcIdentityEntity is the identity asserted by a validated ID token.
+type OidcIdentityEntity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Claims        map[string]interface{}
+}
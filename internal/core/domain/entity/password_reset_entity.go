@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+type PasswordResetTokenEntity struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
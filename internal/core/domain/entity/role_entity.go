@@ -0,0 +1,74 @@
+package entity
+
+import "sync/atomic"
+
+type RoleEntity struct {
+	Name        string
+	Description string
+	MfaRequired bool
+}
+
+const (
+	RoleAdmin       = "admin"
+	RoleEditor      = "editor"
+	RoleAuthor      = "author"
+	RoleContributor = "contributor"
+)
+
+const (
+	PermissionCategoryRead   = "category:read"
+	PermissionCategoryWrite  = "category:write"
+	PermissionCategoryDelete = "category:delete"
+
+	PermissionTagRead   = "tag:read"
+	PermissionTagWrite  = "tag:write"
+	PermissionTagDelete = "tag:delete"
+
+	PermissionContentRead      = "content:read"
+	PermissionContentWrite     = "content:write"
+	PermissionContentDelete    = "content:delete"
+	PermissionContentManageAll = "content:manage_all"
+	PermissionContentUpload    = "content:upload"
+	PermissionContentPublish   = "content:publish"
+
+	PermissionUserManage = "user:manage"
+
+	PermissionAuditRead = "audit:read"
+)
+
+// rolePermissions holds what each role may do, as loaded from the role_permissions table by
+// SetRolePermissions. Roles without PermissionContentManageAll may only update or delete
+// contents they created. PermissionContentPublish covers the review steps of the content
+// workflow: approving, rejecting, publishing and archiving. Merging tags needs
+// PermissionTagDelete, as the merged tag is removed.
+var rolePermissions atomic.Value
+
+// SetRolePermissions replaces the permissions of every role. permissions maps each known
+// role, even one without any permission, to what it may do. It is safe to call while other
+// goroutines check permissions.
+func SetRolePermissions(permissions map[string][]string) {
+	sets := make(map[string]map[string]bool, len(permissions))
+	for role, granted := range permissions {
+		set := make(map[string]bool, len(granted))
+		for _, permission := range granted {
+			set[permission] = true
+		}
+		sets[role] = set
+	}
+
+	rolePermissions.Store(sets)
+}
+
+// IsRole reports whether role is one of the roles loaded with SetRolePermissions.
+func IsRole(role string) bool {
+	sets, _ := rolePermissions.Load().(map[string]map[string]bool)
+	_, ok := sets[role]
+	return ok
+}
+
+// HasPermission reports whether the given role grants the given permission. Nothing is
+// granted before SetRolePermissions is first called.
+func HasPermission(role, permission string) bool {
+	sets, _ := rolePermissions.Load().(map[string]map[string]bool)
+	return sets[role][permission]
+}
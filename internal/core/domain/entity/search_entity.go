@@ -0,0 +1,8 @@
+package entity
+
+// SearchSuggestionEntity holds the type-ahead matches for a partial search query.
+type SearchSuggestionEntity struct {
+	Contents   []ContentEntity
+	Categories []CategoryEntity
+	Tags       []string
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// ClientInfo describes the client a login was made from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionEntity is one login of a user, covering every access and refresh token rotated
+// from it. Its refresh token family is FamilyID.
+type SessionEntity struct {
+	ID         int64
+	UserID     int64
+	FamilyID   string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+type TagEntity struct {
+	ID    int64
+	Title string
+	Slug  string
+	// ContentCount is how many contents have the tag; in public lists only live contents count.
+	ContentCount int64
+	CreatedAt    time.Time
+}
@@ -1,8 +1,21 @@
 package entity
 
+import "time"
+
 type UserEntity struct {
-	ID       int64
-	Name     string
-	Email    string
-	Password string
+	ID        int64
+	Name      string
+	Email     string
+	Password  string
+	Role      string
+	IsActive  bool
+	CreatedAt time.Time
+}
+
+type UserQueryString struct {
+	Limit    int
+	Page     int
+	Search   string
+	Role     string
+	IsActive *bool
 }
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type APIKey struct {
+	ID         int64      `gorm:"id"`
+	UserID     int64      `gorm:"user_id"`
+	Name       string     `gorm:"name"`
+	Prefix     string     `gorm:"prefix"`
+	KeyHash    string     `gorm:"key_hash"`
+	Scopes     string     `gorm:"scopes"`
+	ExpiresAt  *time.Time `gorm:"expires_at"`
+	LastUsedAt *time.Time `gorm:"last_used_at"`
+	RevokedAt  *time.Time `gorm:"revoked_at"`
+	CreatedAt  time.Time  `gorm:"created_at"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type AuditLog struct {
+	ID         int64     `gorm:"id"`
+	ActorID    *int64    `gorm:"actor_id"`
+	APIKeyID   *int64    `gorm:"api_key_id"`
+	Action     string    `gorm:"action"`
+	EntityType string    `gorm:"entity_type"`
+	EntityID   string    `gorm:"entity_id"`
+	Before     *string   `gorm:"column:before;type:jsonb"`
+	After      *string   `gorm:"column:after;type:jsonb"`
+	IP         string    `gorm:"ip"`
+	RequestID  string    `gorm:"request_id"`
+	CreatedAt  time.Time `gorm:"created_at"`
+}
@@ -5,17 +5,23 @@ import (
 )
 
 type Content struct {
-	ID          int64      `gorm:"id"`
-	Title       string     `gorm:"title"`
-	Excerpt     string     `gorm:"excerpt"`
-	Description string     `gorm:"description"`
-	Image       string     `gorm:"image"`
-	Tags        string     `gorm:"tags"`
-	Status      string     `gorm:"status"`
-	CategoryID  int64      `gorm:"category_id"`
-	CreatedByID int64      `gorm:"created_by_id"`
-	User        User       `gorm:"foreignKey:CreatedByID"`
-	Category    Category   `gorm:"foreignKey:CategoryID"`
-	CreatedAt   time.Time  `gorm:"created_at"`
-	UpdatedAt   *time.Time `gorm:"updated_at"`
+	ID              int64      `gorm:"id"`
+	Title           string     `gorm:"title"`
+	Slug            string     `gorm:"slug"`
+	Excerpt         string     `gorm:"excerpt"`
+	Description     string     `gorm:"description"`
+	Image           string     `gorm:"image"`
+	Tags            string     `gorm:"tags"`
+	Status          string     `gorm:"status"`
+	RejectionReason *string    `gorm:"rejection_reason"`
+	PublishAt       *time.Time `gorm:"publish_at"`
+	UnpublishAt     *time.Time `gorm:"unpublish_at"`
+	CategoryID      int64      `gorm:"category_id"`
+	CreatedByID     int64      `gorm:"created_by_id"`
+	User            User       `gorm:"foreignKey:CreatedByID"`
+	Category        Category   `gorm:"foreignKey:CategoryID"`
+	CreatedAt       time.Time  `gorm:"created_at"`
+	UpdatedAt       *time.Time `gorm:"updated_at"`
+	SearchLanguage  string     `gorm:"search_language"`
+	Headline        string     `gorm:"->"`
 }
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type ContentRevision struct {
+	ID           int64     `gorm:"id"`
+	ContentID    int64     `gorm:"content_id"`
+	Revision     int       `gorm:"revision"`
+	Title        string    `gorm:"title"`
+	Excerpt      string    `gorm:"excerpt"`
+	Description  string    `gorm:"description"`
+	Image        string    `gorm:"image"`
+	Tags         string    `gorm:"tags"`
+	CategoryID   *int64    `gorm:"category_id"`
+	AuthorID     *int64    `gorm:"author_id"`
+	Author       *User     `gorm:"foreignKey:AuthorID"`
+	RestoredFrom *int      `gorm:"restored_from"`
+	CreatedAt    time.Time `gorm:"created_at"`
+}
@@ -0,0 +1,9 @@
+package model
+
+import "time"
+
+type ContentSlugRedirect struct {
+	Slug      string    `gorm:"primaryKey;column:slug"`
+	ContentID int64     `gorm:"content_id"`
+	CreatedAt time.Time `gorm:"created_at"`
+}
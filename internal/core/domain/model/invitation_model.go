@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type Invitation struct {
+	ID         int64      `gorm:"id"`
+	Email      string     `gorm:"email"`
+	Role       string     `gorm:"role"`
+	TokenHash  string     `gorm:"token_hash"`
+	InvitedBy  *int64     `gorm:"invited_by"`
+	UserID     *int64     `gorm:"user_id"`
+	ExpiresAt  time.Time  `gorm:"expires_at"`
+	AcceptedAt *time.Time `gorm:"accepted_at"`
+	RevokedAt  *time.Time `gorm:"revoked_at"`
+	SentAt     time.Time  `gorm:"sent_at"`
+	CreatedAt  time.Time  `gorm:"created_at"`
+}
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type LoginAttempt struct {
+	Key           string     `gorm:"column:key;primaryKey"`
+	Failures      int        `gorm:"failures"`
+	LastFailureAt time.Time  `gorm:"last_failure_at"`
+	LockedUntil   *time.Time `gorm:"locked_until"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+type UserMfa struct {
+	UserID       int64      `gorm:"column:user_id;primaryKey"`
+	Secret       string     `gorm:"secret"`
+	Enabled      bool       `gorm:"enabled"`
+	LastUsedStep int64      `gorm:"last_used_step"`
+	EnabledAt    *time.Time `gorm:"enabled_at"`
+	CreatedAt    time.Time  `gorm:"created_at"`
+	UpdatedAt    *time.Time `gorm:"updated_at"`
+}
+
+func (UserMfa) TableName() string {
+	return "user_mfa"
+}
+
+type MfaRecoveryCode struct {
+	ID        int64      `gorm:"id"`
+	UserID    int64      `gorm:"user_id"`
+	CodeHash  string     `gorm:"code_hash"`
+	UsedAt    *time.Time `gorm:"used_at"`
+	CreatedAt time.Time  `gorm:"created_at"`
+}
+
+type MfaChallenge struct {
+	ID        int64      `gorm:"id"`
+	UserID    int64      `gorm:"user_id"`
+	TokenHash string     `gorm:"token_hash"`
+	Attempts  int        `gorm:"attempts"`
+	ExpiresAt time.Time  `gorm:"expires_at"`
+	UsedAt    *time.Time `gorm:"used_at"`
+	CreatedAt time.Time  `gorm:"created_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type OidcState struct {
+	ID           int64      `gorm:"id"`
+	StateHash    string     `gorm:"state_hash"`
+	Nonce        string     `gorm:"nonce"`
+	CodeVerifier string     `gorm:"code_verifier"`
+	ExpiresAt    time.Time  `gorm:"expires_at"`
+	UsedAt       *time.Time `gorm:"used_at"`
+	CreatedAt    time.Time  `gorm:"created_at"`
+}
+
+type UserIdentity struct {
+	ID          int64      `gorm:"id"`
+	UserID      int64      `gorm:"user_id"`
+	Issuer      string     `gorm:"issuer"`
+	Subject     string     `gorm:"subject"`
+	Email       string     `gorm:"email"`
+	LastLoginAt *time.Time `gorm:"last_login_at"`
+	CreatedAt   time.Time  `gorm:"created_at"`
+}
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type PasswordHistory struct {
+	ID        int64     `gorm:"id"`
+	UserID    int64     `gorm:"user_id"`
+	Password  string    `gorm:"password"`
+	CreatedAt time.Time `gorm:"created_at"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type PasswordResetToken struct {
+	ID        int64      `gorm:"id"`
+	UserID    int64      `gorm:"user_id"`
+	TokenHash string     `gorm:"token_hash"`
+	ExpiresAt time.Time  `gorm:"expires_at"`
+	UsedAt    *time.Time `gorm:"used_at"`
+	CreatedAt time.Time  `gorm:"created_at"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type RefreshToken struct {
+	ID             int64      `gorm:"id"`
+	UserID         int64      `gorm:"user_id"`
+	TokenHash      string     `gorm:"token_hash"`
+	FamilyID       string     `gorm:"family_id"`
+	ExpiresAt      time.Time  `gorm:"expires_at"`
+	RevokedAt      *time.Time `gorm:"revoked_at"`
+	ReplacedByID   *int64     `gorm:"replaced_by_id"`
+	AccessTokenJTI string     `gorm:"access_token_jti"`
+	CreatedAt      time.Time  `gorm:"created_at"`
+	UpdatedAt      *time.Time `gorm:"updated_at"`
+}
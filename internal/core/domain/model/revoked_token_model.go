@@ -0,0 +1,9 @@
+package model
+
+import "time"
+
+type RevokedToken struct {
+	JTI       string    `gorm:"column:jti;primaryKey"`
+	ExpiresAt time.Time `gorm:"expires_at"`
+	CreatedAt time.Time `gorm:"created_at"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type Role struct {
+	Name        string     `gorm:"column:name;primaryKey"`
+	Description string     `gorm:"description"`
+	MfaRequired bool       `gorm:"mfa_required"`
+	CreatedAt   time.Time  `gorm:"created_at"`
+	UpdatedAt   *time.Time `gorm:"updated_at"`
+}
+
+type RolePermission struct {
+	Role       string `gorm:"primaryKey;column:role"`
+	Permission string `gorm:"primaryKey;column:permission"`
+}
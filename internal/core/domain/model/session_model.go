@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type Session struct {
+	ID         int64      `gorm:"id"`
+	UserID     int64      `gorm:"user_id"`
+	FamilyID   string     `gorm:"family_id"`
+	UserAgent  string     `gorm:"user_agent"`
+	IP         string     `gorm:"ip"`
+	CreatedAt  time.Time  `gorm:"created_at"`
+	LastSeenAt time.Time  `gorm:"last_seen_at"`
+	ExpiresAt  time.Time  `gorm:"expires_at"`
+	RevokedAt  *time.Time `gorm:"revoked_at"`
+}
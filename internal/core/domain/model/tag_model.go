@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type Tag struct {
+	ID           int64      `gorm:"id"`
+	Title        string     `gorm:"title"`
+	Slug         string     `gorm:"slug"`
+	CreatedAt    time.Time  `gorm:"created_at"`
+	UpdatedAt    *time.Time `gorm:"updated_at"`
+	ContentCount int64      `gorm:"->"`
+}
+
+type ContentTag struct {
+	ContentID int64 `gorm:"primaryKey;column:content_id"`
+	TagID     int64 `gorm:"primaryKey;column:tag_id"`
+	Position  int   `gorm:"position"`
+}
@@ -7,6 +7,8 @@ type User struct {
 	Name      string     `gorm:"name"`
 	Email     string     `gorm:"email"`
 	Password  string     `gorm:"password"`
+	Role      string     `gorm:"role"`
+	IsActive  bool       `gorm:"is_active"`
 	CreatedAt time.Time  `gorm:"created_at"`
 	UpdatedAt *time.Time `gorm:"updated_at"`
 }
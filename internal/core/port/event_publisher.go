@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"portal-blog/internal/core/domain/entity"
+)
+
+// EventPublisher hands application events to whoever listens to them.
+// Implementations live in internal/adapter/event.
+type EventPublisher interface {
+	Publish(ctx context.Context, event entity.EventEntity) error
+}
@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+	"portal-blog/internal/core/domain/entity"
+)
+
+// IdentityProvider runs the OpenID Connect authorization code flow with PKCE against an
+// external issuer. Implementations live in internal/adapter/oidc.
+type IdentityProvider interface {
+	// AuthCodeURL returns the authorization endpoint URL the browser is redirected to.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems an authorization code and returns the identity of its validated ID token.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entity.OidcIdentityEntity, error)
+}
@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"portal-blog/internal/core/domain/entity"
+)
+
+// Mailer sends transactional emails such as password reset links.
+// Implementations live in internal/adapter/mailer.
+type Mailer interface {
+	Send(ctx context.Context, mail entity.MailEntity) error
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/apperror"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/lib/auth"
+	"portal-blog/lib/conv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var (
+	ErrInvalidAPIKey       = apperror.Unauthorized("invalid_api_key", "invalid or expired api key")
+	ErrAPIKeyNotFound      = apperror.NotFound("api_key_not_found", "api key not found")
+	ErrInvalidAPIKeyScope  = apperror.Validation("invalid_api_key_scope", "api key scope is not granted by your role")
+	ErrInvalidAPIKeyExpiry = apperror.Validation("invalid_api_key_expiry", "api key expiry must be in the future")
+)
+
+const (
+	// apiKeyPrefixBytes is the size of the random lookup prefix, hex encoded into the key.
+	apiKeyPrefixBytes = 6
+	// apiKeyTouchInterval limits how often last_used_at is written for a key.
+	apiKeyTouchInterval = time.Minute
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, req entity.APIKeyEntity, actor *entity.JwtData) (*entity.APIKeyEntity, error)
+	GetAPIKeys(ctx context.Context, userID int64) ([]entity.APIKeyEntity, error)
+	RevokeAPIKey(ctx context.Context, id, userID int64) error
+	VerifyAPIKey(ctx context.Context, key string) (*entity.JwtData, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepository repository.APIKeyRepository
+	authRepository   repository.AuthRepository
+}
+
+// CreateAPIKey implements APIKeyService.
+//
+// The key is returned in plain text only once; the database keeps its lookup prefix and hash.
+// Scopes must be permissions of the actor's role. Without scopes the key can do everything
+// the owner's role allows.
+func (a *apiKeyService) CreateAPIKey(ctx context.Context, req entity.APIKeyEntity, actor *entity.JwtData) (*entity.APIKeyEntity, error) {
+	for _, scope := range req.Scopes {
+		if !entity.HasPermission(actor.Role, scope) {
+			code := "[SERVICE] CreateAPIKey - 1"
+			log.Errorw(code, ErrInvalidAPIKeyScope)
+			return nil, ErrInvalidAPIKeyScope
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		code := "[SERVICE] CreateAPIKey - 2"
+		log.Errorw(code, ErrInvalidAPIKeyExpiry)
+		return nil, ErrInvalidAPIKeyExpiry
+	}
+
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		code := "[SERVICE] CreateAPIKey - 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	secret, err := conv.GenerateRandomToken(32)
+	if err != nil {
+		code := "[SERVICE] CreateAPIKey - 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	req.UserID = int64(actor.UserID)
+	req.Prefix = hex.EncodeToString(prefixBytes)
+	key := auth.APIKeyPrefix + req.Prefix + "_" + secret
+	req.KeyHash = conv.HashToken(key)
+
+	result, err := a.apiKeyRepository.CreateAPIKey(ctx, req)
+	if err != nil {
+		code := "[SERVICE] CreateAPIKey - 5"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	result.Key = key
+
+	return result, nil
+}
+
+// GetAPIKeys implements APIKeyService.
+func (a *apiKeyService) GetAPIKeys(ctx context.Context, userID int64) ([]entity.APIKeyEntity, error) {
+	results, err := a.apiKeyRepository.GetAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] GetAPIKeys - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// RevokeAPIKey implements APIKeyService.
+func (a *apiKeyService) RevokeAPIKey(ctx context.Context, id, userID int64) error {
+	err := a.apiKeyRepository.RevokeAPIKey(ctx, id, userID)
+	if err != nil {
+		code := "[SERVICE] RevokeAPIKey - 1"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// VerifyAPIKey implements APIKeyService and auth.APIKeyVerifier.
+//
+// It returns the claims of the key's owner, with their current role and the key's scopes.
+// Revoked or expired keys and keys of deactivated users are rejected with ErrInvalidAPIKey.
+func (a *apiKeyService) VerifyAPIKey(ctx context.Context, key string) (*entity.JwtData, error) {
+	prefix, _, ok := strings.Cut(strings.TrimPrefix(key, auth.APIKeyPrefix), "_")
+	if !auth.IsAPIKey(key) || !ok || prefix == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	stored, err := a.apiKeyRepository.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored.KeyHash), []byte(conv.HashToken(key))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if stored.RevokedAt != nil || (stored.ExpiresAt != nil && time.Now().After(*stored.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := a.authRepository.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if err := a.apiKeyRepository.TouchAPIKey(ctx, stored.ID, apiKeyTouchInterval); err != nil {
+		code := "[SERVICE] VerifyAPIKey - 1"
+		log.Errorw(code, err)
+	}
+
+	return &entity.JwtData{
+		UserID:   float64(user.ID),
+		Role:     user.Role,
+		APIKeyID: stored.ID,
+		Scopes:   stored.Scopes,
+	}, nil
+}
+
+func NewAPIKeyService(apiKeyRepository repository.APIKeyRepository, authRepository repository.AuthRepository) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepository: apiKeyRepository,
+		authRepository:   authRepository,
+	}
+}
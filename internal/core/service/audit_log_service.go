@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/entity"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type AuditLogService interface {
+	GetAuditLogs(ctx context.Context, query entity.AuditLogQueryString) ([]entity.AuditLogEntity, int64, int64, error)
+}
+
+type auditLogService struct {
+	auditLogRepository repository.AuditLogRepository
+}
+
+// GetAuditLogs implements AuditLogService.
+// Entries are only ever written by the repositories, in the transaction of the change they record.
+func (a *auditLogService) GetAuditLogs(ctx context.Context, query entity.AuditLogQueryString) ([]entity.AuditLogEntity, int64, int64, error) {
+	results, totalData, totalPages, err := a.auditLogRepository.GetAuditLogs(ctx, query)
+	if err != nil {
+		code := "[SERVICE] GetAuditLogs - 1"
+		log.Errorw(code, err)
+		return nil, 0, 0, err
+	}
+
+	return results, totalData, totalPages, nil
+}
+
+func NewAuditLogService(auditLogRepository repository.AuditLogRepository) AuditLogService {
+	return &auditLogService{auditLogRepository: auditLogRepository}
+}
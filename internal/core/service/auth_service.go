@@ -5,72 +5,542 @@ import (
 	"errors"
 	"portal-blog/config"
 	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/apperror"
 	"portal-blog/internal/core/domain/entity"
 	"portal-blog/lib/auth"
 	"portal-blog/lib/conv"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2/log"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-var err error
-var code string
+var (
+	ErrInvalidCredentials  = apperror.Unauthorized("invalid_credentials", "invalid email or password")
+	ErrInvalidRefreshToken = apperror.Unauthorized("invalid_refresh_token", "invalid refresh token")
+	ErrRefreshTokenReused  = apperror.Unauthorized("refresh_token_reused", "refresh token reuse detected")
+	ErrUserInactive        = apperror.Forbidden("user_inactive", "user account is deactivated")
+)
+
+// TooManyLoginAttemptsError is returned while the client IP or the account is locked out
+// after repeated failed logins.
+type TooManyLoginAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyLoginAttemptsError) Error() string {
+	return "too many failed login attempts, retry after " + strconv.Itoa(e.RetryAfterSeconds()) + " seconds"
+}
+
+// RetryAfterSeconds rounds RetryAfter up to whole seconds, as used by the Retry-After header.
+func (e *TooManyLoginAttemptsError) RetryAfterSeconds() int {
+	seconds := int(e.RetryAfter / time.Second)
+	if e.RetryAfter%time.Second != 0 {
+		seconds++
+	}
+
+	return seconds
+}
 
 type AuthService interface {
 	GetUserByEmail(ctx context.Context, req entity.LoginRequest) (*entity.AccessToken, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*entity.AccessToken, error)
+	Logout(ctx context.Context, claims *entity.JwtData, refreshToken string) error
+	RevokeOtherSessions(ctx context.Context, userID int64, currentJTI string) error
+	LoginMfa(ctx context.Context, challengeToken, code string, client entity.ClientInfo) (*entity.AccessToken, error)
+	UnlockAccount(ctx context.Context, userID int64) error
+	LoginExternal(ctx context.Context, userID int64, client entity.ClientInfo) (*entity.AccessToken, error)
 }
 
 type authService struct {
-	authRepository repository.AuthRepository
-	cfg            *config.Config
-	jtwToken       auth.Jwt
+	authRepository    repository.AuthRepository
+	sessionRepository repository.SessionRepository
+	cfg               *config.Config
+	jtwToken          auth.Jwt
+	revocationStore   auth.RevocationStore
+	mfaService        MfaService
+	loginThrottle     auth.LoginThrottle
 }
 
+// dummyPasswordHash is a bcrypt hash, with the cost conv.HashPassword uses, that logins for
+// unknown emails are checked against so they take as long as those for existing accounts.
+const dummyPasswordHash = "$2a$14$vdocAvXbKTaERROnWUfUDerXR0w34f.SilJVxcAaGW3aSwp9y0BPG"
+
 // GetUserByEmail implements AuthService.
+// When the user has to complete a second factor, only AccessToken.MfaChallenge is set and
+// the tokens are issued by LoginMfa instead.
+//
+// Logins from a client IP or for an account with too many recent failures are rejected with
+// a TooManyLoginAttemptsError before the password is checked. Unknown emails still cost a
+// password check, so the response time does not tell whether an account exists.
 func (a *authService) GetUserByEmail(ctx context.Context, req entity.LoginRequest) (*entity.AccessToken, error) {
+	ipKey, accountKey := loginAttemptKeys(req)
+
+	wait, err := a.loginThrottle.Check(ctx, ipKey, accountKey)
+	if err != nil {
+		code := "[SERVICE] GetUserByEmail - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if wait > 0 {
+		code := "[SERVICE] GetUserByEmail - 2"
+		err = &TooManyLoginAttemptsError{RetryAfter: wait}
+		log.Errorw(code, err)
+		return nil, err
+	}
+
 	result, err := a.authRepository.GetUserByEmail(ctx, req)
 	if err != nil {
-		code = "[SERVICE] GetUserByEmail - 1"
+		code := "[SERVICE] GetUserByEmail - 3"
 		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrUserNotFound) {
+			conv.CheckPasswordHash(req.Password, dummyPasswordHash)
+			a.registerLoginFailure(ctx, ipKey, accountKey)
+			return nil, ErrInvalidCredentials
+		}
 		return nil, err
 	}
 
 	if checkPass := conv.CheckPasswordHash(req.Password, result.Password); !checkPass {
-		code = "[SERVICE] GetUserByEmail - 2"
-		err = errors.New("invalid password")
+		code := "[SERVICE] GetUserByEmail - 4"
+		log.Errorw(code, ErrInvalidCredentials)
+		a.registerLoginFailure(ctx, ipKey, accountKey)
+		return nil, ErrInvalidCredentials
+	}
+
+	err = a.loginThrottle.Reset(ctx, accountKey)
+	if err != nil {
+		code := "[SERVICE] GetUserByEmail - 5"
+		log.Errorw(code, err)
+	}
+
+	if !result.IsActive {
+		code := "[SERVICE] GetUserByEmail - 6"
+		log.Errorw(code, ErrUserInactive)
+		return nil, ErrUserInactive
+	}
+
+	challenge, err := a.mfaService.StartChallenge(ctx, result)
+	if err != nil {
+		code := "[SERVICE] GetUserByEmail - 7"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if challenge != nil {
+		return &entity.AccessToken{MfaChallenge: challenge}, nil
+	}
+
+	resp, err := a.issueTokens(ctx, result, entity.ClientInfo{IP: req.IP, UserAgent: req.UserAgent})
+	if err != nil {
+		code := "[SERVICE] GetUserByEmail - 8"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return resp, nil
+
+}
+
+// UnlockAccount implements AuthService.
+// It clears the failed login counter of the user's account. Counters of client IPs are left as they are.
+func (a *authService) UnlockAccount(ctx context.Context, userID int64) error {
+	user, err := a.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] UnlockAccount - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	_, accountKey := loginAttemptKeys(entity.LoginRequest{Email: user.Email})
+	err = a.loginThrottle.Reset(ctx, accountKey)
+	if err != nil {
+		code := "[SERVICE] UnlockAccount - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// LoginMfa implements AuthService.
+// It completes a login that was answered with an MFA challenge and issues the tokens.
+// If the user enrolled during this login, their recovery codes are returned as well.
+func (a *authService) LoginMfa(ctx context.Context, challengeToken, mfaCode string, client entity.ClientInfo) (*entity.AccessToken, error) {
+	userID, recoveryCodes, err := a.mfaService.VerifyChallenge(ctx, challengeToken, mfaCode)
+	if err != nil {
+		code := "[SERVICE] LoginMfa - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	user, err := a.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] LoginMfa - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if !user.IsActive {
+		code := "[SERVICE] LoginMfa - 3"
+		log.Errorw(code, ErrUserInactive)
+		return nil, ErrUserInactive
+	}
+
+	resp, err := a.issueTokens(ctx, user, client)
+	if err != nil {
+		code := "[SERVICE] LoginMfa - 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resp.RecoveryCodes = recoveryCodes
+
+	return resp, nil
+}
+
+// LoginExternal implements AuthService.
+// It issues the tokens for a user that was authenticated by an external identity provider.
+// The local password is not checked, but users with MFA enabled or whose role requires it
+// are answered with an MFA challenge, as on a password login.
+func (a *authService) LoginExternal(ctx context.Context, userID int64, client entity.ClientInfo) (*entity.AccessToken, error) {
+	user, err := a.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] LoginExternal - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if !user.IsActive {
+		code := "[SERVICE] LoginExternal - 2"
+		log.Errorw(code, ErrUserInactive)
+		return nil, ErrUserInactive
+	}
+
+	challenge, err := a.mfaService.StartChallenge(ctx, user)
+	if err != nil {
+		code := "[SERVICE] LoginExternal - 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if challenge != nil {
+		return &entity.AccessToken{MfaChallenge: challenge}, nil
+	}
+
+	resp, err := a.issueTokens(ctx, user, client)
+	if err != nil {
+		code := "[SERVICE] LoginExternal - 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// RefreshToken implements AuthService.
+//
+// The presented refresh token is exchanged for a new access token and a new refresh token
+// in the same family. Presenting a token that was already rotated is treated as theft:
+// every token in its family is revoked and ErrRefreshTokenReused is returned.
+func (a *authService) RefreshToken(ctx context.Context, refreshToken string) (*entity.AccessToken, error) {
+	stored, err := a.authRepository.GetRefreshTokenByHash(ctx, conv.HashToken(refreshToken))
+	if err != nil {
+		code := "[SERVICE] RefreshToken - 1"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if stored.RevokedAt != nil {
+		code := "[SERVICE] RefreshToken - 2"
+		if stored.ReplacedByID == nil {
+			log.Errorw(code, ErrInvalidRefreshToken)
+			return nil, ErrInvalidRefreshToken
+		}
+
+		log.Errorw(code, ErrRefreshTokenReused)
+		return nil, a.revokeFamily(ctx, stored.FamilyID)
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		code := "[SERVICE] RefreshToken - 3"
+		log.Errorw(code, ErrInvalidRefreshToken)
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := a.authRepository.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		code := "[SERVICE] RefreshToken - 4"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if !user.IsActive {
+		code := "[SERVICE] RefreshToken - 5"
+		log.Errorw(code, ErrUserInactive)
+		return nil, ErrInvalidRefreshToken
+	}
+
+	accessToken, expiresAt, jti, err := a.generateAccessToken(user)
+	if err != nil {
+		code := "[SERVICE] RefreshToken - 6"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	newRefreshToken, refreshEntity, err := a.generateRefreshToken(user.ID, stored.FamilyID, jti)
+	if err != nil {
+		code := "[SERVICE] RefreshToken - 7"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	err = a.authRepository.RotateRefreshToken(ctx, stored.ID, *refreshEntity)
+	if err != nil {
+		code := "[SERVICE] RefreshToken - 8"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrRefreshTokenRevoked) {
+			return nil, a.revokeFamily(ctx, stored.FamilyID)
+		}
+		return nil, err
+	}
+
+	err = a.sessionRepository.ExtendSession(ctx, stored.FamilyID, refreshEntity.ExpiresAt)
+	if err != nil {
+		code := "[SERVICE] RefreshToken - 9"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return &entity.AccessToken{
+		AccessToken:      accessToken,
+		ExpiredAt:        expiresAt,
+		RefreshToken:     newRefreshToken,
+		RefreshExpiredAt: refreshEntity.ExpiresAt.Unix(),
+	}, nil
+}
+
+// Logout implements AuthService.
+//
+// The access token identified by claims is added to the revocation list until it expires
+// and its session is signed out together with the session's refresh tokens. A refresh token
+// sent by the caller has its family revoked as well.
+func (a *authService) Logout(ctx context.Context, claims *entity.JwtData, refreshToken string) error {
+	expiresAt := time.Now().Add(a.cfg.App.JwtAccessTokenTTL)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	err := a.revocationStore.Revoke(ctx, claims.ID, expiresAt)
+	if err != nil {
+		code := "[SERVICE] Logout - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if claims.SessionID != 0 {
+		err = a.sessionRepository.RevokeSession(ctx, claims.SessionID, int64(claims.UserID))
+		if err != nil && !errors.Is(err, repository.ErrSessionNotFound) {
+			code := "[SERVICE] Logout - 2"
+			log.Errorw(code, err)
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := a.authRepository.GetRefreshTokenByHash(ctx, conv.HashToken(refreshToken))
+	if err != nil {
+		code := "[SERVICE] Logout - 3"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if stored.UserID != int64(claims.UserID) {
+		return nil
+	}
+
+	err = a.authRepository.RevokeRefreshTokenFamily(ctx, stored.FamilyID)
+	if err != nil {
+		code := "[SERVICE] Logout - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions implements AuthService.
+//
+// Every session of the user and its refresh tokens are revoked except the session that issued
+// currentJTI, and every access token that may still be valid, other than currentJTI itself,
+// is added to the revocation list. Pass an empty currentJTI to end all sessions.
+func (a *authService) RevokeOtherSessions(ctx context.Context, userID int64, currentJTI string) error {
+	accessTTL := a.cfg.App.JwtAccessTokenTTL
+	issued, err := a.authRepository.GetRefreshTokensIssuedSince(ctx, userID, time.Now().Add(-accessTTL))
+	if err != nil {
+		code := "[SERVICE] RevokeOtherSessions - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	currentFamilyID := ""
+	for _, token := range issued {
+		if currentJTI != "" && token.AccessTokenJTI == currentJTI {
+			currentFamilyID = token.FamilyID
+		}
+	}
+
+	for _, token := range issued {
+		if token.AccessTokenJTI == "" || token.AccessTokenJTI == currentJTI {
+			continue
+		}
+
+		err = a.revocationStore.Revoke(ctx, token.AccessTokenJTI, token.CreatedAt.Add(accessTTL))
+		if err != nil {
+			code := "[SERVICE] RevokeOtherSessions - 2"
+			log.Errorw(code, err)
+			return err
+		}
+	}
+
+	err = a.sessionRepository.RevokeUserSessions(ctx, userID, currentFamilyID)
+	if err != nil {
+		code := "[SERVICE] RevokeOtherSessions - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// revokeFamily revokes a whole refresh token family after reuse was detected and
+// returns ErrRefreshTokenReused, or the database error if the revocation failed.
+func (a *authService) revokeFamily(ctx context.Context, familyID string) error {
+	err := a.authRepository.RevokeRefreshTokenFamily(ctx, familyID)
+	if err != nil {
+		code := "[SERVICE] revokeFamily - 1"
 		log.Errorw(code, err)
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
+
+// registerLoginFailure counts a failed login for the client IP and the account. Counter errors
+// are only logged so that they do not change the login response.
+func (a *authService) registerLoginFailure(ctx context.Context, ipKey, accountKey string) {
+	if _, err := a.loginThrottle.RegisterFailure(ctx, ipKey, a.cfg.App.LoginIPMaxFailures); err != nil {
+		log.Errorw("[SERVICE] registerLoginFailure - 1", err)
+	}
+
+	if _, err := a.loginThrottle.RegisterFailure(ctx, accountKey, a.cfg.App.LoginMaxFailures); err != nil {
+		log.Errorw("[SERVICE] registerLoginFailure - 2", err)
+	}
+}
+
+// loginAttemptKeys returns the failed login counter keys of the client IP and the account.
+func loginAttemptKeys(req entity.LoginRequest) (string, string) {
+	return "ip:" + req.IP, "account:" + strings.ToLower(strings.TrimSpace(req.Email))
+}
+
+// issueTokens starts a new session for the user with an access token and a refresh token
+// of a new family, recording the client it was started from.
+func (a *authService) issueTokens(ctx context.Context, user *entity.UserEntity, client entity.ClientInfo) (*entity.AccessToken, error) {
+	accessToken, expiresAt, jti, err := a.generateAccessToken(user)
+	if err != nil {
 		return nil, err
 	}
 
+	refreshToken, refreshEntity, err := a.generateRefreshToken(user.ID, uuid.NewString(), jti)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.sessionRepository.CreateSession(ctx, entity.SessionEntity{
+		UserID:    user.ID,
+		FamilyID:  refreshEntity.FamilyID,
+		UserAgent: truncate(client.UserAgent, maxSessionUserAgentLength),
+		IP:        client.IP,
+		ExpiresAt: refreshEntity.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.authRepository.CreateRefreshToken(ctx, *refreshEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.AccessToken{
+		AccessToken:      accessToken,
+		ExpiredAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiredAt: refreshEntity.ExpiresAt.Unix(),
+	}, nil
+}
+
+// generateAccessToken signs a short-lived access token for the given user.
+// It returns the token, its expiry as a Unix timestamp and its unique ID (jti).
+func (a *authService) generateAccessToken(user *entity.UserEntity) (string, int64, string, error) {
 	jwtData := entity.JwtData{
-		UserID: float64(result.ID),
+		UserID: float64(user.ID),
+		Role:   user.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			NotBefore: jwt.NewNumericDate(time.Now().Add(time.Hour * 2)),
-			ID:        string(rune(result.ID)),
+			ID:        uuid.NewString(),
 		},
 	}
 
 	accessToken, expiresAt, err := a.jtwToken.GenerateToken(&jwtData)
-
 	if err != nil {
-		code = "[SERVICE] GetUserByEmail - 3"
-		log.Errorw(code, err)
-		return nil, err
+		return "", 0, "", err
 	}
 
-	resp := entity.AccessToken{
-		AccessToken: accessToken,
-		ExpiredAt:   expiresAt,
-	}
+	return accessToken, expiresAt, jwtData.ID, nil
+}
 
-	return &resp, nil
+// generateRefreshToken creates a new opaque refresh token for the given user and family,
+// linked to the access token issued with it. It returns the plain token for the client and
+// the entity to persist, which only holds its hash.
+func (a *authService) generateRefreshToken(userID int64, familyID, accessTokenJTI string) (string, *entity.RefreshTokenEntity, error) {
+	token, err := conv.GenerateRandomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
 
+	return token, &entity.RefreshTokenEntity{
+		UserID:         userID,
+		TokenHash:      conv.HashToken(token),
+		FamilyID:       familyID,
+		ExpiresAt:      time.Now().Add(a.cfg.App.JwtRefreshTokenTTL),
+		AccessTokenJTI: accessTokenJTI,
+	}, nil
 }
 
-func NewAuthService(authRepository repository.AuthRepository, cfg *config.Config, jwtToken auth.Jwt) AuthService {
+func NewAuthService(authRepository repository.AuthRepository, sessionRepository repository.SessionRepository, cfg *config.Config, jwtToken auth.Jwt, revocationStore auth.RevocationStore, mfaService MfaService, loginThrottle auth.LoginThrottle) AuthService {
 	return &authService{authRepository: authRepository,
-		cfg:      cfg,
-		jtwToken: jwtToken,
+		sessionRepository: sessionRepository,
+		cfg:               cfg,
+		jtwToken:          jwtToken,
+		revocationStore:   revocationStore,
+		mfaService:        mfaService,
+		loginThrottle:     loginThrottle,
 	}
 }
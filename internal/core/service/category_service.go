@@ -26,10 +26,10 @@ func (c *categoryService) CreateCategory(ctx context.Context, req entity.Categor
 	slug := conv.GenerateSlug(req.Title)
 	req.Slug = slug
 
-	err = c.categoryRepository.CreateCategory(ctx, req)
+	err := c.categoryRepository.CreateCategory(ctx, req)
 
 	if err != nil {
-		code = "[SERVICE] CreateCategory - 1"
+		code := "[SERVICE] CreateCategory - 1"
 		log.Errorw(code, err)
 		return err
 	}
@@ -40,9 +40,9 @@ func (c *categoryService) CreateCategory(ctx context.Context, req entity.Categor
 
 // DeleteCategoryById implements CategoryService.
 func (c *categoryService) DeleteCategoryById(ctx context.Context, id int64) error {
-	err = c.categoryRepository.DeleteCategoryById(ctx, id)
+	err := c.categoryRepository.DeleteCategoryById(ctx, id)
 	if err != nil {
-		code = "[SERVICE] DeleteCategoryById - 1"
+		code := "[SERVICE] DeleteCategoryById - 1"
     log.Errorw(code, err)
     return err
 	}
@@ -54,7 +54,7 @@ func (c *categoryService) DeleteCategoryById(ctx context.Context, id int64) erro
 func (c *categoryService) EditCategoryById(ctx context.Context, req entity.CategoryEntity) error {
 	categoryData, err := c.categoryRepository.GetCategoryById(ctx, req.ID)
 	if err != nil {
-		code = "[SERVICE] EditCategoryById - 1"
+		code := "[SERVICE] EditCategoryById - 1"
 		log.Errorw(code, err)
 		return err
 	}
@@ -68,7 +68,7 @@ func (c *categoryService) EditCategoryById(ctx context.Context, req entity.Categ
 
 	err = c.categoryRepository.EditCategoryById(ctx, req)
 	if err != nil {
-		code = "[SERVICE] EditCategoryById - 2"
+		code := "[SERVICE] EditCategoryById - 2"
 		log.Errorw(code, err)
 		return err
 	}
@@ -81,7 +81,7 @@ func (c *categoryService) EditCategoryById(ctx context.Context, req entity.Categ
 func (c *categoryService) GetCategories(ctx context.Context) ([]entity.CategoryEntity, error) {
 	results, err := c.categoryRepository.GetCategories(ctx)
 	if err != nil {
-		code = "[SERVICE] GetCategories - 1"
+		code := "[SERVICE] GetCategories - 1"
 		log.Errorw(code, err)
 		return nil, err
 	}
@@ -93,7 +93,7 @@ func (c *categoryService) GetCategories(ctx context.Context) ([]entity.CategoryE
 func (c *categoryService) GetCategoryById(ctx context.Context, id int64) (*entity.CategoryEntity, error) {
 	result, err := c.categoryRepository.GetCategoryById(ctx, id)
 	if err != nil {
-		code = "[SERVICE] GetCategoryById - 1"
+		code := "[SERVICE] GetCategoryById - 1"
 		log.Errorw(code, err)
 		return nil, err
 	}
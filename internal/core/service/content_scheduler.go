@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// RunContentScheduler applies the publish_at and unpublish_at of contents every interval
+// until ctx is cancelled. It is meant to run in its own goroutine for the lifetime of the server.
+func RunContentScheduler(ctx context.Context, contentService ContentService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			published, unpublished, err := contentService.ApplySchedule(ctx, now)
+			if err != nil && ctx.Err() == nil {
+				log.Errorw("[SERVICE] RunContentScheduler - 1", err)
+			}
+
+			if published > 0 || unpublished > 0 {
+				log.Infof("Content scheduler published %d and unpublished %d contents", published, unpublished)
+			}
+		}
+	}
+}
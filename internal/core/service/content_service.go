@@ -2,20 +2,103 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"portal-blog/config"
 	"portal-blog/internal/adapter/cloudflare"
 	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/apperror"
 	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/port"
+	"portal-blog/lib/conv"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2/log"
 )
 
+var (
+	// ErrForbidden is returned when the acting user may not modify the requested content.
+	ErrForbidden                = apperror.Forbidden("content_forbidden", "you are not allowed to modify this content")
+	ErrInvalidContentTransition = apperror.Conflict("invalid_content_transition", "the content cannot make this transition from its current status")
+	ErrRejectionReasonRequired  = apperror.Validation("rejection_reason_required", "a reason is required to reject a content")
+	ErrInvalidContentSchedule   = apperror.Validation("invalid_content_schedule", "unpublish_at must be after publish_at")
+	ErrContentSlugTaken         = apperror.Conflict("content_slug_taken", "another content already uses this slug")
+	ErrContentUnpublishAtPassed = apperror.Conflict("content_unpublish_at_passed", "the content's unpublish_at has passed, move or clear it before publishing again")
+	ErrInvalidInitialStatus     = apperror.Validation("invalid_initial_status", "new contents must be DRAFT or IN_REVIEW")
+	ErrContentLocked            = apperror.Conflict("content_locked", "only drafts and contents in review can be edited without the content:publish permission")
+	ErrInvalidContentOrder      = apperror.Validation("invalid_content_order", "orderBy must be created_at, title, publish_at or relevance and orderType asc or desc")
+)
+
+// editableStatuses are the statuses in which contents can be edited by users who cannot
+// publish them.
+var editableStatuses = []string{entity.ContentStatusDraft, entity.ContentStatusInReview}
+
+// contentTransition is a step of the content workflow: the statuses it starts from, the status
+// it leads to and the permission it needs. Steps without a permission may be taken by the
+// creator of the content or by anyone allowed to manage all contents.
+type contentTransition struct {
+	from       []string
+	to         string
+	permission string
+}
+
+// contentTransitions is the content workflow. Contents are written as drafts, submitted for
+// review, approved into SCHEDULED or rejected back to DRAFT, published and finally archived.
+// Archived contents can be published again.
+var contentTransitions = map[string]contentTransition{
+	entity.ContentTransitionSubmit: {
+		from: []string{entity.ContentStatusDraft},
+		to:   entity.ContentStatusInReview,
+	},
+	entity.ContentTransitionApprove: {
+		from:       []string{entity.ContentStatusInReview},
+		to:         entity.ContentStatusScheduled,
+		permission: entity.PermissionContentPublish,
+	},
+	entity.ContentTransitionReject: {
+		from:       []string{entity.ContentStatusInReview, entity.ContentStatusScheduled},
+		to:         entity.ContentStatusDraft,
+		permission: entity.PermissionContentPublish,
+	},
+	entity.ContentTransitionPublish: {
+		from:       []string{entity.ContentStatusScheduled, entity.ContentStatusArchived},
+		to:         entity.ContentStatusPublished,
+		permission: entity.PermissionContentPublish,
+	},
+	entity.ContentTransitionArchive: {
+		from:       []string{entity.ContentStatusScheduled, entity.ContentStatusPublished},
+		to:         entity.ContentStatusArchived,
+		permission: entity.PermissionContentPublish,
+	},
+}
+
+func (t contentTransition) allowedFrom(status string) bool {
+	for _, from := range t.from {
+		if from == status {
+			return true
+		}
+	}
+
+	return false
+}
+
 type ContentService interface {
 	GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, int64, error)
 	GetContentByID(ctx context.Context, id int64) (*entity.ContentEntity, error)
+	GetPublishedContentByID(ctx context.Context, id int64) (*entity.ContentEntity, error)
+	GetPublishedContentBySlug(ctx context.Context, slug string) (*entity.ContentEntity, error)
 	CreateContent(ctx context.Context, req entity.ContentEntity) error
-	UpdateContent(ctx context.Context, req entity.ContentEntity) error
-	DeleteContent(ctx context.Context, id int64) error
+	UpdateContent(ctx context.Context, req entity.ContentEntity, actor *entity.JwtData) error
+	DeleteContent(ctx context.Context, id int64, actor *entity.JwtData) error
+	TransitionContent(ctx context.Context, id int64, transition, reason string, actor *entity.JwtData) (*entity.ContentEntity, error)
+	ApplySchedule(ctx context.Context, now time.Time) (published, unpublished int, err error)
+
+	// Revisions
+	GetContentRevisions(ctx context.Context, contentID int64) ([]entity.ContentRevisionEntity, error)
+	DiffContentRevisions(ctx context.Context, contentID int64, from, to int) (*entity.ContentRevisionDiffEntity, error)
+	RestoreContentRevision(ctx context.Context, contentID int64, revision int, actor *entity.JwtData) (*entity.ContentRevisionEntity, error)
 	UploadImageR2(ctx context.Context, req entity.FileUploadEntity) (string, error)
 }
 
@@ -23,29 +106,59 @@ type contentService struct {
 	contentRepository repository.ContentRepository
 	cfg               *config.Config
 	r2                cloudflare.CloudflareR2Adapter
+	events            port.EventPublisher
 }
 
 // CreateContent implements ContentService.
+// New contents start as drafts, or directly in review when created with that status.
+// Without a slug of its own the content gets one made from its title.
 func (c *contentService) CreateContent(ctx context.Context, req entity.ContentEntity) error {
-	err = c.contentRepository.CreateContent(ctx, req)
+	switch req.Status {
+	case "":
+		req.Status = entity.ContentStatusDraft
+	case entity.ContentStatusDraft, entity.ContentStatusInReview:
+	default:
+		return ErrInvalidInitialStatus
+	}
+
+	if err := checkSchedule(req); err != nil {
+		return err
+	}
+
+	slug, err := c.resolveSlug(ctx, req.Slug, req.Title, 0)
 	if err != nil {
 		code := "[SERVICE] CreateContent - 1"
 		log.Errorw(code, err)
 		return err
 	}
+	req.Slug = slug
+
+	err = c.contentRepository.CreateContent(ctx, req)
+	if err != nil {
+		code := "[SERVICE] CreateContent - 2"
+		log.Errorw(code, err)
+		return err
+	}
 
 	return nil
 }
 
 // DeleteContent implements ContentService.
-func (c *contentService) DeleteContent(ctx context.Context, id int64) error {
-	err = c.contentRepository.DeleteContent(ctx, id)
+func (c *contentService) DeleteContent(ctx context.Context, id int64, actor *entity.JwtData) error {
+	err := c.checkOwnership(ctx, id, actor)
 	if err != nil {
 		code := "[SERVICE] DeleteContent - 1"
 		log.Errorw(code, err)
 		return err
 	}
 
+	err = c.contentRepository.DeleteContent(ctx, id)
+	if err != nil {
+		code := "[SERVICE] DeleteContent - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
 	return nil
 }
 
@@ -54,19 +167,72 @@ func (c *contentService) GetContentByID(ctx context.Context, id int64) (*entity.
 	result, err := c.contentRepository.GetContentByID(ctx, id)
 
 	if err != nil {
-		code = "[SERVICE] GetContentByID - 1"
+		code := "[SERVICE] GetContentByID - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetPublishedContentByID implements ContentService.
+// Contents that are not live, because they are not published or outside their publish_at and
+// unpublish_at, are reported as not found.
+func (c *contentService) GetPublishedContentByID(ctx context.Context, id int64) (*entity.ContentEntity, error) {
+	result, err := c.contentRepository.GetContentByID(ctx, id)
+	if err != nil {
+		code := "[SERVICE] GetPublishedContentByID - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if !result.IsLive(time.Now()) {
+		return nil, repository.ErrContentNotFound
+	}
+
+	return result, nil
+}
+
+// GetPublishedContentBySlug implements ContentService.
+// A slug the content had before its current one also finds it; callers can tell by comparing
+// the slug of the result. Contents that are not live are reported as not found.
+func (c *contentService) GetPublishedContentBySlug(ctx context.Context, slug string) (*entity.ContentEntity, error) {
+	result, err := c.contentRepository.GetContentBySlug(ctx, slug)
+	if errors.Is(err, repository.ErrContentNotFound) {
+		var contentID int64
+		contentID, err = c.contentRepository.GetContentIDBySlugRedirect(ctx, slug)
+		if err == nil {
+			result, err = c.contentRepository.GetContentByID(ctx, contentID)
+		}
+	}
+	if err != nil {
+		code := "[SERVICE] GetPublishedContentBySlug - 1"
 		log.Errorw(code, err)
 		return nil, err
 	}
 
+	if !result.IsLive(time.Now()) {
+		return nil, repository.ErrContentNotFound
+	}
+
 	return result, nil
 }
 
 // GetContents implements ContentService.
+// query.OrderBy must be one of entity.ContentOrderColumns or entity.OrderByRelevance and
+// query.OrderType asc or desc; anything else is ErrInvalidContentOrder.
 func (c *contentService) GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, int64, error) {
+	query.OrderType = strings.ToLower(query.OrderType)
+	if query.OrderBy != entity.OrderByRelevance && !slices.Contains(entity.ContentOrderColumns, query.OrderBy) ||
+		query.OrderType != "asc" && query.OrderType != "desc" {
+		code := "[SERVICE] GetContents - 1"
+		log.Errorw(code, ErrInvalidContentOrder)
+		return nil, 0, 0, ErrInvalidContentOrder
+	}
+
 	results, totalData, totalPages, err := c.contentRepository.GetContents(ctx, query)
 	if err != nil {
-		code = "[SERVICE] GetContents - 1"
+		code := "[SERVICE] GetContents - 2"
 		log.Errorw(code, err)
 		return nil, 0, 0, err
 	}
@@ -75,17 +241,292 @@ func (c *contentService) GetContents(ctx context.Context, query entity.QueryStri
 }
 
 // UpdateContent implements ContentService.
-func (c *contentService) UpdateContent(ctx context.Context, req entity.ContentEntity) error {
-	err = c.contentRepository.UpdateContent(ctx, req)
+// The status is left as it is; it only changes through TransitionContent. Without
+// entity.PermissionContentPublish only contents in editableStatuses can be edited, so
+// scheduled and published contents do not change without going through review again.
+// Without a slug in req the slug follows the title: it is kept while the title stays the same
+// and made anew when it changes. The previous slug keeps redirecting to the content.
+func (c *contentService) UpdateContent(ctx context.Context, req entity.ContentEntity, actor *entity.JwtData) error {
+	req.Status = ""
+
+	if err := checkSchedule(req); err != nil {
+		return err
+	}
+
+	err := c.checkOwnership(ctx, req.ID, actor)
 	if err != nil {
 		code := "[SERVICE] UpdateContent - 1"
 		log.Errorw(code, err)
 		return err
 	}
 
+	current, err := c.contentRepository.GetContentByID(ctx, req.ID)
+	if err != nil {
+		code := "[SERVICE] UpdateContent - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	var editable []string
+	if !actor.Can(entity.PermissionContentPublish) {
+		editable = editableStatuses
+		if !slices.Contains(editable, current.Status) {
+			code := "[SERVICE] UpdateContent - 3"
+			log.Errorw(code, ErrContentLocked)
+			return ErrContentLocked
+		}
+	}
+
+	switch {
+	case req.Slug == "" && req.Title == current.Title, req.Slug == current.Slug:
+		req.Slug = current.Slug
+	default:
+		req.Slug, err = c.resolveSlug(ctx, req.Slug, req.Title, req.ID)
+		if err != nil {
+			code := "[SERVICE] UpdateContent - 4"
+			log.Errorw(code, err)
+			return err
+		}
+	}
+
+	err = c.contentRepository.UpdateContent(ctx, req, editable)
+	if err != nil {
+		code := "[SERVICE] UpdateContent - 5"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// TransitionContent implements ContentService.
+// It takes the named step of the content workflow and returns the content in its new status.
+// reason is required when rejecting and ignored otherwise.
+func (c *contentService) TransitionContent(ctx context.Context, id int64, transition, reason string, actor *entity.JwtData) (*entity.ContentEntity, error) {
+	step, ok := contentTransitions[transition]
+	if !ok {
+		return nil, ErrInvalidContentTransition
+	}
+
+	if step.permission != "" && !actor.Can(step.permission) {
+		code := "[SERVICE] TransitionContent - 1"
+		log.Errorw(code, ErrForbidden)
+		return nil, ErrForbidden
+	}
+
+	content, err := c.contentRepository.GetContentByID(ctx, id)
+	if err != nil {
+		code := "[SERVICE] TransitionContent - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if step.permission == "" && !actor.Can(entity.PermissionContentManageAll) && content.CreatedByID != int64(actor.UserID) {
+		code := "[SERVICE] TransitionContent - 3"
+		log.Errorw(code, ErrForbidden)
+		return nil, ErrForbidden
+	}
+
+	if !step.allowedFrom(content.Status) {
+		return nil, apperror.Conflict(ErrInvalidContentTransition.Code, fmt.Sprintf("cannot %s a content that is %s", transition, content.Status))
+	}
+
+	if transition != entity.ContentTransitionReject {
+		reason = ""
+	} else if strings.TrimSpace(reason) == "" {
+		return nil, ErrRejectionReasonRequired
+	}
+
+	if step.to == entity.ContentStatusPublished && content.UnpublishAt != nil && !content.UnpublishAt.After(time.Now()) {
+		return nil, ErrContentUnpublishAtPassed
+	}
+
+	err = c.changeStatus(ctx, content, step.to, reason)
+	if err != nil {
+		code := "[SERVICE] TransitionContent - 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// ApplySchedule implements ContentService.
+// Scheduled contents whose publish_at has come, or that have none, are published and
+// published contents whose unpublish_at has passed are archived. Contents an editor moves in
+// the meantime are skipped.
+func (c *contentService) ApplySchedule(ctx context.Context, now time.Time) (published, unpublished int, err error) {
+	due, err := c.contentRepository.GetContentsDueToPublish(ctx, now)
+	if err != nil {
+		code := "[SERVICE] ApplySchedule - 1"
+		log.Errorw(code, err)
+		return 0, 0, err
+	}
+
+	for i := range due {
+		if due[i].UnpublishAt != nil && !due[i].UnpublishAt.After(now) {
+			continue
+		}
+
+		err := c.changeStatus(ctx, &due[i], entity.ContentStatusPublished, "")
+		if errors.Is(err, repository.ErrContentStatusChanged) || errors.Is(err, repository.ErrContentNotFound) {
+			continue
+		}
+		if err != nil {
+			code := "[SERVICE] ApplySchedule - 2"
+			log.Errorw(code, err)
+			return published, unpublished, err
+		}
+		published++
+	}
+
+	expired, err := c.contentRepository.GetContentsDueToUnpublish(ctx, now)
+	if err != nil {
+		code := "[SERVICE] ApplySchedule - 3"
+		log.Errorw(code, err)
+		return published, unpublished, err
+	}
+
+	for i := range expired {
+		err := c.changeStatus(ctx, &expired[i], entity.ContentStatusArchived, "")
+		if errors.Is(err, repository.ErrContentStatusChanged) || errors.Is(err, repository.ErrContentNotFound) {
+			continue
+		}
+		if err != nil {
+			code := "[SERVICE] ApplySchedule - 4"
+			log.Errorw(code, err)
+			return published, unpublished, err
+		}
+		unpublished++
+	}
+
+	return published, unpublished, nil
+}
+
+// changeStatus moves content to the status to and updates it in place. Contents that enter or
+// leave PUBLISHED are announced through the event publisher; a failure to hand over the event is
+// logged and does not undo the change.
+func (c *contentService) changeStatus(ctx context.Context, content *entity.ContentEntity, to, reason string) error {
+	from := content.Status
+
+	err := c.contentRepository.UpdateContentStatus(ctx, content.ID, from, to, reason)
+	if err != nil {
+		return err
+	}
+
+	content.Status = to
+	content.RejectionReason = reason
+
+	var eventType string
+	switch {
+	case to == entity.ContentStatusPublished:
+		eventType = entity.EventContentPublished
+	case from == entity.ContentStatusPublished:
+		eventType = entity.EventContentUnpublished
+	default:
+		return nil
+	}
+
+	err = c.events.Publish(ctx, entity.EventEntity{
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		Data: map[string]interface{}{
+			"id":            content.ID,
+			"title":         content.Title,
+			"status":        content.Status,
+			"category_id":   content.CategoryID,
+			"created_by_id": content.CreatedByID,
+			"publish_at":    content.PublishAt,
+			"unpublish_at":  content.UnpublishAt,
+		},
+	})
+	if err != nil {
+		code := "[SERVICE] changeStatus - 1"
+		log.Errorw(code, err)
+	}
+
 	return nil
 }
 
+// GetContentRevisions implements ContentService.
+// Revisions are returned newest first.
+func (c *contentService) GetContentRevisions(ctx context.Context, contentID int64) ([]entity.ContentRevisionEntity, error) {
+	if _, err := c.contentRepository.GetContentByID(ctx, contentID); err != nil {
+		code := "[SERVICE] GetContentRevisions - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	results, err := c.contentRepository.GetContentRevisions(ctx, contentID)
+	if err != nil {
+		code := "[SERVICE] GetContentRevisions - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// DiffContentRevisions implements ContentService.
+// A zero to compares against the latest revision and a zero from against the revision before to.
+func (c *contentService) DiffContentRevisions(ctx context.Context, contentID int64, from, to int) (*entity.ContentRevisionDiffEntity, error) {
+	if to == 0 {
+		revisions, err := c.GetContentRevisions(ctx, contentID)
+		if err != nil {
+			return nil, err
+		}
+		if len(revisions) == 0 {
+			return nil, repository.ErrContentRevisionNotFound
+		}
+		to = revisions[0].Revision
+	}
+
+	if from == 0 {
+		from = to - 1
+	}
+
+	fromRevision, err := c.contentRepository.GetContentRevision(ctx, contentID, from)
+	if err != nil {
+		code := "[SERVICE] DiffContentRevisions - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	toRevision, err := c.contentRepository.GetContentRevision(ctx, contentID, to)
+	if err != nil {
+		code := "[SERVICE] DiffContentRevisions - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return &entity.ContentRevisionDiffEntity{
+		ContentID: contentID,
+		From:      *fromRevision,
+		To:        *toRevision,
+		Changes:   diffContentRevisions(*fromRevision, *toRevision),
+	}, nil
+}
+
+// RestoreContentRevision implements ContentService.
+// The restored state is saved as a new revision, which is returned.
+func (c *contentService) RestoreContentRevision(ctx context.Context, contentID int64, revision int, actor *entity.JwtData) (*entity.ContentRevisionEntity, error) {
+	err := c.checkOwnership(ctx, contentID, actor)
+	if err != nil {
+		code := "[SERVICE] RestoreContentRevision - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	result, err := c.contentRepository.RestoreContentRevision(ctx, contentID, revision)
+	if err != nil {
+		code := "[SERVICE] RestoreContentRevision - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // UploadImageR2 implements ContentService.
 func (c *contentService) UploadImageR2(ctx context.Context, req entity.FileUploadEntity) (string, error) {
 	urlImage, err := c.r2.UploadImage(&req)
@@ -98,10 +539,91 @@ func (c *contentService) UploadImageR2(ctx context.Context, req entity.FileUploa
 	return urlImage, nil
 }
 
-func NewContentService(repo repository.ContentRepository, cfg *config.Config, r2 cloudflare.CloudflareR2Adapter) ContentService {
+// diffContentRevisions lists the fields that differ between two revisions, in a fixed order.
+func diffContentRevisions(from, to entity.ContentRevisionEntity) []entity.FieldChangeEntity {
+	changes := []entity.FieldChangeEntity{}
+	compare := func(field string, before, after interface{}, equal bool) {
+		if !equal {
+			changes = append(changes, entity.FieldChangeEntity{Field: field, From: before, To: after})
+		}
+	}
+
+	compare("title", from.Title, to.Title, from.Title == to.Title)
+	compare("excerpt", from.Excerpt, to.Excerpt, from.Excerpt == to.Excerpt)
+	compare("description", from.Description, to.Description, from.Description == to.Description)
+	compare("image", from.Image, to.Image, from.Image == to.Image)
+	compare("tags", from.Tags, to.Tags, slices.Equal(from.Tags, to.Tags))
+	compare("category_id", from.CategoryID, to.CategoryID, from.CategoryID == to.CategoryID)
+
+	return changes
+}
+
+// resolveSlug returns the slug for the content with the given ID, zero for a new one. A custom
+// slug is used as it is unless another content has it. Otherwise the slug is made from the
+// title and numbered, as in "title-2", until it is free.
+func (c *contentService) resolveSlug(ctx context.Context, custom, title string, contentID int64) (string, error) {
+	base := custom
+	if base == "" {
+		base = conv.Slugify(title)
+		if base == "" {
+			base = "content"
+		}
+	}
+
+	taken, err := c.contentRepository.GetTakenContentSlugs(ctx, base, contentID)
+	if err != nil {
+		return "", err
+	}
+
+	if custom != "" {
+		if slices.Contains(taken, custom) {
+			return "", ErrContentSlugTaken
+		}
+		return custom, nil
+	}
+
+	slug := base
+	for n := 2; slices.Contains(taken, slug); n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	return slug, nil
+}
+
+// checkSchedule returns ErrInvalidContentSchedule when the content would be unpublished before
+// it is published.
+func checkSchedule(content entity.ContentEntity) error {
+	if content.PublishAt != nil && content.UnpublishAt != nil && !content.UnpublishAt.After(*content.PublishAt) {
+		return ErrInvalidContentSchedule
+	}
+
+	return nil
+}
+
+// checkOwnership returns ErrForbidden when the actor may only manage their own contents
+// and the content with the given ID was created by someone else.
+func (c *contentService) checkOwnership(ctx context.Context, id int64, actor *entity.JwtData) error {
+	if actor.Can(entity.PermissionContentManageAll) {
+		return nil
+	}
+
+	content, err := c.contentRepository.GetContentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if content.CreatedByID != int64(actor.UserID) {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+func NewContentService(repo repository.ContentRepository, cfg *config.Config, r2 cloudflare.CloudflareR2Adapter, events port.EventPublisher) ContentService {
 	return &contentService{
 		contentRepository: repo,
 		cfg:               cfg,
 		r2:                r2,
+		events:            events,
 	}
 }
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"portal-blog/config"
+	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/apperror"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/port"
+	"portal-blog/lib/conv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var (
+	ErrInvitationNotFound = apperror.NotFound("invitation_not_found", "invitation not found")
+	ErrInvitationOpen     = apperror.Conflict("invitation_open", "this email already has an open invitation, resend or revoke it")
+	ErrInvitationClosed   = apperror.Conflict("invitation_closed", "invitation was already accepted or revoked")
+	ErrInvalidInvitation  = apperror.Validation("invalid_invitation", "invalid or expired invitation")
+	ErrInvalidRole        = apperror.Validation("invalid_role", "unknown role")
+)
+
+type InvitationService interface {
+	CreateInvitation(ctx context.Context, req entity.InvitationEntity, actor *entity.JwtData) (*entity.InvitationEntity, error)
+	GetInvitations(ctx context.Context) ([]entity.InvitationEntity, error)
+	ResendInvitation(ctx context.Context, id int64) (*entity.InvitationEntity, error)
+	RevokeInvitation(ctx context.Context, id int64) error
+	AcceptInvitation(ctx context.Context, req entity.AcceptInvitationEntity) error
+}
+
+type invitationService struct {
+	invitationRepository repository.InvitationRepository
+	userRepository       repository.UserRepository
+	mailer               port.Mailer
+	cfg                  *config.Config
+}
+
+// CreateInvitation implements InvitationService.
+//
+// A single-use token is generated, only its hash is stored, and the invitation link carrying
+// it is mailed in the background. Emails that already belong to a user or have an open
+// invitation cannot be invited.
+func (i *invitationService) CreateInvitation(ctx context.Context, req entity.InvitationEntity, actor *entity.JwtData) (*entity.InvitationEntity, error) {
+	req.Email = strings.TrimSpace(req.Email)
+	if !entity.IsRole(req.Role) {
+		code := "[SERVICE] CreateInvitation - 1"
+		log.Errorw(code, ErrInvalidRole)
+		return nil, ErrInvalidRole
+	}
+
+	count, err := i.userRepository.CountUsersByEmail(ctx, req.Email, 0)
+	if err != nil {
+		code := "[SERVICE] CreateInvitation - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, ErrEmailAlreadyUsed
+	}
+
+	count, err = i.invitationRepository.CountOpenInvitationsByEmail(ctx, req.Email)
+	if err != nil {
+		code := "[SERVICE] CreateInvitation - 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, ErrInvitationOpen
+	}
+
+	token, err := conv.GenerateRandomToken(32)
+	if err != nil {
+		code := "[SERVICE] CreateInvitation - 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	invitedBy := int64(actor.UserID)
+	req.InvitedBy = &invitedBy
+	req.TokenHash = conv.HashToken(token)
+	req.ExpiresAt = time.Now().Add(i.cfg.App.InvitationTTL)
+
+	result, err := i.invitationRepository.CreateInvitation(ctx, req)
+	if err != nil {
+		code := "[SERVICE] CreateInvitation - 5"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	i.sendInvitation(*result, token)
+
+	return result, nil
+}
+
+// GetInvitations implements InvitationService.
+func (i *invitationService) GetInvitations(ctx context.Context) ([]entity.InvitationEntity, error) {
+	results, err := i.invitationRepository.GetInvitations(ctx)
+	if err != nil {
+		code := "[SERVICE] GetInvitations - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ResendInvitation implements InvitationService.
+// A new link with a fresh expiry is mailed and the previous link stops working. Expired
+// invitations can be resent; accepted and revoked ones cannot.
+func (i *invitationService) ResendInvitation(ctx context.Context, id int64) (*entity.InvitationEntity, error) {
+	invitation, err := i.invitationRepository.GetInvitationByID(ctx, id)
+	if err != nil {
+		code := "[SERVICE] ResendInvitation - 1"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+
+	token, err := conv.GenerateRandomToken(32)
+	if err != nil {
+		code := "[SERVICE] ResendInvitation - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	invitation.TokenHash = conv.HashToken(token)
+	invitation.ExpiresAt = time.Now().Add(i.cfg.App.InvitationTTL)
+	invitation.SentAt = time.Now()
+
+	err = i.invitationRepository.RenewInvitation(ctx, id, invitation.TokenHash, invitation.ExpiresAt)
+	if err != nil {
+		code := "[SERVICE] ResendInvitation - 3"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrInvitationClosed) {
+			return nil, ErrInvitationClosed
+		}
+		return nil, err
+	}
+
+	i.sendInvitation(*invitation, token)
+
+	return invitation, nil
+}
+
+// RevokeInvitation implements InvitationService.
+func (i *invitationService) RevokeInvitation(ctx context.Context, id int64) error {
+	_, err := i.invitationRepository.GetInvitationByID(ctx, id)
+	if err != nil {
+		code := "[SERVICE] RevokeInvitation - 1"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			return ErrInvitationNotFound
+		}
+		return err
+	}
+
+	err = i.invitationRepository.RevokeInvitation(ctx, id)
+	if err != nil {
+		code := "[SERVICE] RevokeInvitation - 2"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrInvitationClosed) {
+			return ErrInvitationClosed
+		}
+		return err
+	}
+
+	return nil
+}
+
+// AcceptInvitation implements InvitationService.
+// The invitee's account is created with the invited email and role, and the name and password
+// they chose. Unknown, expired, revoked and already accepted tokens are all rejected with
+// ErrInvalidInvitation.
+func (i *invitationService) AcceptInvitation(ctx context.Context, req entity.AcceptInvitationEntity) error {
+	invitation, err := i.invitationRepository.GetInvitationByTokenHash(ctx, conv.HashToken(req.Token))
+	if err != nil {
+		code := "[SERVICE] AcceptInvitation - 1"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			return ErrInvalidInvitation
+		}
+		return err
+	}
+
+	if invitation.Status(time.Now()) != entity.InvitationStatusPending {
+		code := "[SERVICE] AcceptInvitation - 2"
+		log.Errorw(code, ErrInvalidInvitation)
+		return ErrInvalidInvitation
+	}
+
+	count, err := i.userRepository.CountUsersByEmail(ctx, invitation.Email, 0)
+	if err != nil {
+		code := "[SERVICE] AcceptInvitation - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if count > 0 {
+		return ErrEmailAlreadyUsed
+	}
+
+	password, err := conv.HashPassword(req.Password)
+	if err != nil {
+		code := "[SERVICE] AcceptInvitation - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	_, err = i.invitationRepository.AcceptInvitation(ctx, invitation.ID, entity.UserEntity{
+		Name:     strings.TrimSpace(req.Name),
+		Email:    invitation.Email,
+		Password: password,
+		Role:     invitation.Role,
+	})
+	if err != nil {
+		code := "[SERVICE] AcceptInvitation - 5"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrInvitationClosed) {
+			return ErrInvalidInvitation
+		}
+		return err
+	}
+
+	return nil
+}
+
+// sendInvitation mails the invitation link in the background.
+func (i *invitationService) sendInvitation(invitation entity.InvitationEntity, token string) {
+	mail := entity.MailEntity{
+		To:      invitation.Email,
+		Subject: "You have been invited to Portal Blog",
+		Body: fmt.Sprintf("Hi,\n\nYou have been invited to join Portal Blog as %s. Open the link below to choose your name and password:\n\n%s/accept-invitation?token=%s\n\nThe link expires at %s.\n",
+			invitation.Role, i.cfg.App.FrontendURL, url.QueryEscape(token), invitation.ExpiresAt.Format(time.RFC1123)),
+	}
+
+	go func() {
+		if err := i.mailer.Send(context.Background(), mail); err != nil {
+			log.Errorw("[SERVICE] sendInvitation - 1", err)
+		}
+	}()
+}
+
+func NewInvitationService(invitationRepository repository.InvitationRepository, userRepository repository.UserRepository, mailer port.Mailer, cfg *config.Config) InvitationService {
+	return &invitationService{
+		invitationRepository: invitationRepository,
+		userRepository:       userRepository,
+		mailer:               mailer,
+		cfg:                  cfg,
+	}
+}
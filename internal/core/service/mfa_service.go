@@ -0,0 +1,517 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"portal-blog/config"
+	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/apperror"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/lib/auth"
+	"portal-blog/lib/conv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var (
+	ErrMfaAlreadyEnabled   = apperror.Conflict("mfa_already_enabled", "two-factor authentication is already enabled")
+	ErrMfaNotEnabled       = apperror.Conflict("mfa_not_enabled", "two-factor authentication is not enabled")
+	ErrMfaSetupRequired    = apperror.Conflict("mfa_setup_required", "two-factor authentication setup has not been started")
+	ErrMfaRequiredByRole   = apperror.Forbidden("mfa_required_by_role", "two-factor authentication is required for this role")
+	ErrInvalidMfaCode      = apperror.Validation("invalid_mfa_code", "invalid two-factor authentication code")
+	ErrInvalidMfaChallenge = apperror.Unauthorized("invalid_mfa_challenge", "invalid or expired mfa challenge")
+)
+
+const (
+	mfaChallengeMaxAttempts = 5
+	mfaRecoveryCodeCount    = 10
+	mfaTOTPSkew             = 1
+)
+
+type MfaService interface {
+	GetStatus(ctx context.Context, userID int64) (*entity.MfaStatusEntity, error)
+	Setup(ctx context.Context, userID int64) (*entity.MfaSetupEntity, error)
+	Enable(ctx context.Context, userID int64, code string) ([]string, error)
+	Disable(ctx context.Context, userID int64, password, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID int64, code string) ([]string, error)
+	ResetUserMfa(ctx context.Context, userID int64) error
+
+	StartChallenge(ctx context.Context, user *entity.UserEntity) (*entity.MfaChallengeEntity, error)
+	SetupChallenge(ctx context.Context, token string) (*entity.MfaSetupEntity, error)
+	VerifyChallenge(ctx context.Context, token, code string) (int64, []string, error)
+}
+
+type mfaService struct {
+	mfaRepository  repository.MfaRepository
+	userRepository repository.UserRepository
+	roleRepository repository.RoleRepository
+	cfg            *config.Config
+}
+
+// GetStatus implements MfaService.
+// It reports whether the user enabled MFA and whether their role requires it.
+func (m *mfaService) GetStatus(ctx context.Context, userID int64) (*entity.MfaStatusEntity, error) {
+	user, err := m.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] GetStatus - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	mfa, err := m.getUserMfa(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] GetStatus - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	required, err := m.isRequired(ctx, user.Role)
+	if err != nil {
+		code := "[SERVICE] GetStatus - 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return &entity.MfaStatusEntity{
+		Enabled:  mfa != nil && mfa.Enabled,
+		Required: required,
+	}, nil
+}
+
+// Setup implements MfaService.
+//
+// A new pending secret is generated and returned together with its otpauth:// provisioning URI.
+// MFA is only enabled once a code generated from the secret is confirmed with Enable.
+func (m *mfaService) Setup(ctx context.Context, userID int64) (*entity.MfaSetupEntity, error) {
+	user, err := m.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] Setup - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		code := "[SERVICE] Setup - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	err = m.mfaRepository.SaveUserMfaSecret(ctx, userID, secret)
+	if err != nil {
+		code := "[SERVICE] Setup - 3"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrMfaAlreadyEnabled) {
+			return nil, ErrMfaAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	return &entity.MfaSetupEntity{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(m.cfg.App.MfaIssuer, user.Email, secret),
+	}, nil
+}
+
+// Enable implements MfaService.
+// It confirms the pending secret with a TOTP code and returns freshly generated recovery codes,
+// which are only shown this once.
+func (m *mfaService) Enable(ctx context.Context, userID int64, mfaCode string) ([]string, error) {
+	recoveryCodes, err := m.enable(ctx, userID, mfaCode)
+	if err != nil {
+		code := "[SERVICE] Enable - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// Disable implements MfaService.
+// The user must confirm with their password and a current code. Users whose role requires
+// MFA cannot disable it.
+func (m *mfaService) Disable(ctx context.Context, userID int64, password, mfaCode string) error {
+	user, err := m.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] Disable - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if !conv.CheckPasswordHash(password, user.Password) {
+		code := "[SERVICE] Disable - 2"
+		log.Errorw(code, ErrInvalidCurrentPassword)
+		return ErrInvalidCurrentPassword
+	}
+
+	required, err := m.isRequired(ctx, user.Role)
+	if err != nil {
+		code := "[SERVICE] Disable - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if required {
+		code := "[SERVICE] Disable - 4"
+		log.Errorw(code, ErrMfaRequiredByRole)
+		return ErrMfaRequiredByRole
+	}
+
+	mfa, err := m.getEnabledMfa(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] Disable - 5"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = m.verifyCode(ctx, mfa, mfaCode)
+	if err != nil {
+		code := "[SERVICE] Disable - 6"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = m.mfaRepository.DisableUserMfa(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] Disable - 7"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes implements MfaService.
+// Every previous recovery code stops working.
+func (m *mfaService) RegenerateRecoveryCodes(ctx context.Context, userID int64, mfaCode string) ([]string, error) {
+	mfa, err := m.getEnabledMfa(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] RegenerateRecoveryCodes - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	err = m.verifyCode(ctx, mfa, mfaCode)
+	if err != nil {
+		code := "[SERVICE] RegenerateRecoveryCodes - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		code := "[SERVICE] RegenerateRecoveryCodes - 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	err = m.mfaRepository.ReplaceRecoveryCodes(ctx, userID, hashes)
+	if err != nil {
+		code := "[SERVICE] RegenerateRecoveryCodes - 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// ResetUserMfa implements MfaService.
+// It removes the MFA enrolment of a user who lost their device. If their role requires MFA
+// they are asked to enrol again on their next login.
+func (m *mfaService) ResetUserMfa(ctx context.Context, userID int64) error {
+	_, err := m.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] ResetUserMfa - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = m.mfaRepository.DisableUserMfa(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] ResetUserMfa - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// StartChallenge implements MfaService.
+//
+// It returns nil when the user can log in with their password alone. Otherwise a short-lived
+// challenge is created that must be completed with VerifyChallenge. Users whose role requires
+// MFA but who have not enrolled yet get a challenge with EnrollmentRequired set.
+func (m *mfaService) StartChallenge(ctx context.Context, user *entity.UserEntity) (*entity.MfaChallengeEntity, error) {
+	mfa, err := m.getUserMfa(ctx, user.ID)
+	if err != nil {
+		code := "[SERVICE] StartChallenge - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	enabled := mfa != nil && mfa.Enabled
+	if !enabled {
+		required, err := m.isRequired(ctx, user.Role)
+		if err != nil {
+			code := "[SERVICE] StartChallenge - 2"
+			log.Errorw(code, err)
+			return nil, err
+		}
+
+		if !required {
+			return nil, nil
+		}
+	}
+
+	token, err := conv.GenerateRandomToken(32)
+	if err != nil {
+		code := "[SERVICE] StartChallenge - 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	challenge := entity.MfaChallengeEntity{
+		UserID:             user.ID,
+		Token:              token,
+		TokenHash:          conv.HashToken(token),
+		ExpiresAt:          time.Now().Add(m.cfg.App.MfaChallengeTTL),
+		EnrollmentRequired: !enabled,
+	}
+
+	err = m.mfaRepository.CreateMfaChallenge(ctx, challenge)
+	if err != nil {
+		code := "[SERVICE] StartChallenge - 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// SetupChallenge implements MfaService.
+// It starts the enrolment of a user who has to enrol before completing their login.
+func (m *mfaService) SetupChallenge(ctx context.Context, token string) (*entity.MfaSetupEntity, error) {
+	challenge, err := m.getChallenge(ctx, token)
+	if err != nil {
+		code := "[SERVICE] SetupChallenge - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	setup, err := m.Setup(ctx, challenge.UserID)
+	if err != nil {
+		code := "[SERVICE] SetupChallenge - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return setup, nil
+}
+
+// VerifyChallenge implements MfaService.
+//
+// The code may be a TOTP code or a recovery code. When the user is still enrolling, the TOTP
+// code confirms the pending secret and the new recovery codes are returned. Each challenge
+// accepts a limited number of attempts and can only be completed once.
+// It returns the ID of the user the challenge belongs to.
+func (m *mfaService) VerifyChallenge(ctx context.Context, token, mfaCode string) (int64, []string, error) {
+	challenge, err := m.getChallenge(ctx, token)
+	if err != nil {
+		code := "[SERVICE] VerifyChallenge - 1"
+		log.Errorw(code, err)
+		return 0, nil, err
+	}
+
+	err = m.mfaRepository.IncrementMfaChallengeAttempts(ctx, challenge.ID, mfaChallengeMaxAttempts)
+	if err != nil {
+		code := "[SERVICE] VerifyChallenge - 2"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrMfaChallengeUnavailable) {
+			return 0, nil, ErrInvalidMfaChallenge
+		}
+		return 0, nil, err
+	}
+
+	mfa, err := m.getUserMfa(ctx, challenge.UserID)
+	if err != nil {
+		code := "[SERVICE] VerifyChallenge - 3"
+		log.Errorw(code, err)
+		return 0, nil, err
+	}
+
+	var recoveryCodes []string
+	if mfa != nil && mfa.Enabled {
+		err = m.verifyCode(ctx, mfa, mfaCode)
+	} else {
+		recoveryCodes, err = m.enable(ctx, challenge.UserID, mfaCode)
+	}
+	if err != nil {
+		code := "[SERVICE] VerifyChallenge - 4"
+		log.Errorw(code, err)
+		return 0, nil, err
+	}
+
+	err = m.mfaRepository.ConsumeMfaChallenge(ctx, challenge.ID)
+	if err != nil {
+		code := "[SERVICE] VerifyChallenge - 5"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrMfaChallengeUnavailable) {
+			return 0, nil, ErrInvalidMfaChallenge
+		}
+		return 0, nil, err
+	}
+
+	return challenge.UserID, recoveryCodes, nil
+}
+
+// enable confirms the pending secret of the user with a TOTP code.
+func (m *mfaService) enable(ctx context.Context, userID int64, mfaCode string) ([]string, error) {
+	mfa, err := m.getUserMfa(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa == nil {
+		return nil, ErrMfaSetupRequired
+	}
+
+	if mfa.Enabled {
+		return nil, ErrMfaAlreadyEnabled
+	}
+
+	step, ok := auth.ValidateTOTP(mfa.Secret, strings.TrimSpace(mfaCode), time.Now(), mfaTOTPSkew)
+	if !ok {
+		return nil, ErrInvalidMfaCode
+	}
+
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.mfaRepository.EnableUserMfa(ctx, userID, step, hashes)
+	if err != nil {
+		if errors.Is(err, repository.ErrMfaAlreadyEnabled) {
+			return nil, ErrMfaAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// verifyCode accepts a TOTP code of an unused time step or an unused recovery code.
+func (m *mfaService) verifyCode(ctx context.Context, mfa *entity.UserMfaEntity, mfaCode string) error {
+	mfaCode = strings.TrimSpace(mfaCode)
+
+	if step, ok := auth.ValidateTOTP(mfa.Secret, mfaCode, time.Now(), mfaTOTPSkew); ok {
+		err := m.mfaRepository.MarkTOTPStepUsed(ctx, mfa.UserID, step)
+		if errors.Is(err, repository.ErrTOTPStepUsed) {
+			return ErrInvalidMfaCode
+		}
+		return err
+	}
+
+	err := m.mfaRepository.UseRecoveryCode(ctx, mfa.UserID, conv.HashToken(normalizeRecoveryCode(mfaCode)))
+	if errors.Is(err, repository.ErrRecoveryCodeNotFound) {
+		return ErrInvalidMfaCode
+	}
+
+	return err
+}
+
+// getChallenge looks up an unused, unexpired challenge by its plain token.
+func (m *mfaService) getChallenge(ctx context.Context, token string) (*entity.MfaChallengeEntity, error) {
+	challenge, err := m.mfaRepository.GetMfaChallengeByHash(ctx, conv.HashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrMfaChallengeNotFound) {
+			return nil, ErrInvalidMfaChallenge
+		}
+		return nil, err
+	}
+
+	if challenge.UsedAt != nil || challenge.Attempts >= mfaChallengeMaxAttempts || time.Now().After(challenge.ExpiresAt) {
+		return nil, ErrInvalidMfaChallenge
+	}
+
+	return challenge, nil
+}
+
+// getUserMfa returns nil without an error when the user never started an enrolment.
+func (m *mfaService) getUserMfa(ctx context.Context, userID int64) (*entity.UserMfaEntity, error) {
+	mfa, err := m.mfaRepository.GetUserMfa(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMfaNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return mfa, nil
+}
+
+func (m *mfaService) getEnabledMfa(ctx context.Context, userID int64) (*entity.UserMfaEntity, error) {
+	mfa, err := m.getUserMfa(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa == nil || !mfa.Enabled {
+		return nil, ErrMfaNotEnabled
+	}
+
+	return mfa, nil
+}
+
+func (m *mfaService) isRequired(ctx context.Context, role string) (bool, error) {
+	result, err := m.roleRepository.GetRoleByName(ctx, role)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MfaRequired, nil
+}
+
+// generateRecoveryCodes returns recovery codes formatted as "xxxx-xxxx" and their hashes.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, mfaRecoveryCodeCount)
+	hashes := make([]string, 0, mfaRecoveryCodeCount)
+
+	for i := 0; i < mfaRecoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		codes = append(codes, raw[:4]+"-"+raw[4:])
+		hashes = append(hashes, conv.HashToken(raw))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode lets users type recovery codes in any case, with or without separators.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+func NewMfaService(
+	mfaRepository repository.MfaRepository,
+	userRepository repository.UserRepository,
+	roleRepository repository.RoleRepository,
+	cfg *config.Config,
+) MfaService {
+	return &mfaService{
+		mfaRepository:  mfaRepository,
+		userRepository: userRepository,
+		roleRepository: roleRepository,
+		cfg:            cfg,
+	}
+}
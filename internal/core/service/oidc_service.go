@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"portal-blog/config"
+	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/apperror"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/port"
+	"portal-blog/lib/conv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var (
+	ErrOidcDisabled         = apperror.NotFound("oidc_disabled", "single sign-on is not configured")
+	ErrInvalidOidcState     = apperror.Unauthorized("invalid_oidc_state", "invalid or expired sign-in state")
+	ErrOidcExchangeFailed   = apperror.Unauthorized("oidc_exchange_failed", "sign-in with the identity provider failed")
+	ErrOidcEmailNotVerified = apperror.Forbidden("oidc_email_not_verified", "identity provider did not return a verified email")
+	ErrOidcRoleNotMapped    = apperror.Forbidden("oidc_role_not_mapped", "your identity is not assigned a role in this application")
+	ErrOidcAccountExists    = apperror.Conflict("oidc_account_exists", "an account with this email already exists, sign in with its password")
+	ErrOidcAccountProtected = apperror.Forbidden("oidc_account_protected", "the account with this email is protected by two-factor authentication and cannot be linked to single sign-on")
+)
+
+// oidcRoleMapping maps one value of the role claim to a role.
+type oidcRoleMapping struct {
+	value string
+	role  string
+}
+
+type OidcService interface {
+	StartLogin(ctx context.Context) (*entity.OidcLoginEntity, error)
+	CompleteLogin(ctx context.Context, authCode, state string, client entity.ClientInfo) (*entity.AccessToken, error)
+}
+
+type oidcService struct {
+	oidcRepository repository.OidcRepository
+	authRepository repository.AuthRepository
+	userRepository repository.UserRepository
+	authService    AuthService
+	mfaService     MfaService
+	provider       port.IdentityProvider
+	cfg            *config.Config
+	roleMappings   []oidcRoleMapping
+}
+
+// StartLogin implements OidcService.
+// It stores a new state with its nonce and PKCE code verifier and returns the authorization
+// URL. The client keeps the state to check it on the callback before completing the login.
+func (o *oidcService) StartLogin(ctx context.Context) (*entity.OidcLoginEntity, error) {
+	if o.provider == nil {
+		return nil, ErrOidcDisabled
+	}
+
+	state, err := conv.GenerateRandomToken(32)
+	if err != nil {
+		code := "[SERVICE] StartLogin - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	nonce, err := conv.GenerateRandomToken(32)
+	if err != nil {
+		code := "[SERVICE] StartLogin - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	codeVerifier, err := conv.GenerateRandomToken(32)
+	if err != nil {
+		code := "[SERVICE] StartLogin - 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	stored := entity.OidcStateEntity{
+		StateHash:    conv.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(o.cfg.App.OidcStateTTL),
+	}
+
+	authURL, err := o.provider.AuthCodeURL(ctx, state, nonce, pkceChallenge(codeVerifier))
+	if err != nil {
+		code := "[SERVICE] StartLogin - 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	err = o.oidcRepository.CreateOidcState(ctx, stored)
+	if err != nil {
+		code := "[SERVICE] StartLogin - 5"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return &entity.OidcLoginEntity{
+		AuthorizationURL: authURL,
+		State:            state,
+		ExpiresAt:        stored.ExpiresAt,
+	}, nil
+}
+
+// CompleteLogin implements OidcService.
+//
+// The state is consumed, the code is exchanged for a validated ID token and the identity is
+// mapped to a role. The identity's user is looked up by issuer and subject; an unknown
+// identity gets a new user, or is linked to the user with the same verified email when
+// OIDC_LINK_EXISTING_USERS allows it. New users get the mapped or default role; existing
+// users only follow a role mapping that matches, and admins keep their role. The login ends
+// like a password login, with an MFA challenge when the user needs one.
+func (o *oidcService) CompleteLogin(ctx context.Context, authCode, state string, client entity.ClientInfo) (*entity.AccessToken, error) {
+	if o.provider == nil {
+		return nil, ErrOidcDisabled
+	}
+
+	stored, err := o.oidcRepository.ConsumeOidcState(ctx, conv.HashToken(state))
+	if err != nil {
+		code := "[SERVICE] CompleteLogin - 1"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrOidcStateNotFound) {
+			return nil, ErrInvalidOidcState
+		}
+		return nil, err
+	}
+
+	identity, err := o.provider.Exchange(ctx, authCode, stored.CodeVerifier, stored.Nonce)
+	if err != nil {
+		code := "[SERVICE] CompleteLogin - 2"
+		log.Errorw(code, err)
+		return nil, fmt.Errorf("%w: %v", ErrOidcExchangeFailed, err)
+	}
+
+	role, mapped := o.mapRole(identity.Claims)
+	if role == "" {
+		code := "[SERVICE] CompleteLogin - 3"
+		log.Errorw(code, ErrOidcRoleNotMapped)
+		return nil, ErrOidcRoleNotMapped
+	}
+
+	userID, err := o.oidcRepository.GetUserIDByIdentity(ctx, identity.Issuer, identity.Subject)
+	if err != nil && !errors.Is(err, repository.ErrOidcIdentityNotFound) {
+		code := "[SERVICE] CompleteLogin - 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	created := false
+	if userID == 0 {
+		userID, created, err = o.provisionUser(ctx, identity, role)
+		if err != nil {
+			code := "[SERVICE] CompleteLogin - 5"
+			log.Errorw(code, err)
+			return nil, err
+		}
+	}
+
+	if !created && mapped {
+		err = o.followMappedRole(ctx, userID, role)
+		if err != nil {
+			code := "[SERVICE] CompleteLogin - 6"
+			log.Errorw(code, err)
+			return nil, err
+		}
+	}
+
+	resp, err := o.authService.LoginExternal(ctx, userID, client)
+	if err != nil {
+		code := "[SERVICE] CompleteLogin - 7"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	err = o.oidcRepository.TouchUserIdentity(ctx, identity.Issuer, identity.Subject)
+	if err != nil {
+		code := "[SERVICE] CompleteLogin - 8"
+		log.Errorw(code, err)
+	}
+
+	return resp, nil
+}
+
+// provisionUser creates the user of an identity seen for the first time, with the given
+// role, and reports whether it did. New users get an unusable random password and can only
+// sign in through the identity provider until they reset it.
+//
+// When a user with the identity's email exists already, the identity is linked to it if
+// OIDC_LINK_EXISTING_USERS allows, unless the user has MFA enabled or their role requires
+// it: the identity provider must not become a way around their second factor.
+func (o *oidcService) provisionUser(ctx context.Context, identity *entity.OidcIdentityEntity, role string) (int64, bool, error) {
+	if identity.Email == "" || !identity.EmailVerified {
+		return 0, false, ErrOidcEmailNotVerified
+	}
+
+	user, err := o.authRepository.GetUserByEmail(ctx, entity.LoginRequest{Email: identity.Email})
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return 0, false, err
+	}
+
+	if user != nil {
+		if !o.cfg.App.OidcLinkExistingUsers {
+			return 0, false, ErrOidcAccountExists
+		}
+
+		mfa, err := o.mfaService.GetStatus(ctx, user.ID)
+		if err != nil {
+			return 0, false, err
+		}
+
+		if mfa.Enabled || mfa.Required {
+			return 0, false, ErrOidcAccountProtected
+		}
+
+		if err = o.oidcRepository.LinkUserIdentity(ctx, user.ID, *identity); err != nil {
+			return 0, false, err
+		}
+
+		return user.ID, false, nil
+	}
+
+	secret, err := conv.GenerateRandomToken(32)
+	if err != nil {
+		return 0, false, err
+	}
+
+	password, err := conv.HashPassword(secret)
+	if err != nil {
+		return 0, false, err
+	}
+
+	name := identity.Name
+	if name == "" {
+		name = identity.Email
+	}
+
+	userID, err := o.oidcRepository.CreateUserWithIdentity(ctx, entity.UserEntity{
+		Name:     name,
+		Email:    identity.Email,
+		Password: password,
+		Role:     role,
+	}, *identity)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return userID, true, nil
+}
+
+// followMappedRole gives an existing user the role a mapping matched for their identity.
+// Admins are never demoted this way; that is left to other admins.
+func (o *oidcService) followMappedRole(ctx context.Context, userID int64, role string) error {
+	user, err := o.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == role || user.Role == entity.RoleAdmin {
+		return nil
+	}
+
+	return o.userRepository.UpdateUser(ctx, entity.UserEntity{ID: userID, Role: role})
+}
+
+// mapRole returns the role of the first mapping whose value is in the role claim, which may
+// be a string or a list of strings, and true. When none matches it returns the default role
+// and false.
+func (o *oidcService) mapRole(claims map[string]interface{}) (string, bool) {
+	values := map[string]bool{}
+	switch claim := claims[o.cfg.App.OidcRoleClaim].(type) {
+	case string:
+		values[claim] = true
+	case []interface{}:
+		for _, v := range claim {
+			if s, ok := v.(string); ok {
+				values[s] = true
+			}
+		}
+	}
+
+	for _, mapping := range o.roleMappings {
+		if values[mapping.value] {
+			return mapping.role, true
+		}
+	}
+
+	return o.cfg.App.OidcDefaultRole, false
+}
+
+// pkceChallenge derives the S256 code challenge of a PKCE code verifier (RFC 7636).
+func pkceChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// parseOidcRoleMapping parses OIDC_ROLE_MAPPING, comma separated value=role pairs in order of precedence.
+func parseOidcRoleMapping(value string) ([]oidcRoleMapping, error) {
+	var mappings []oidcRoleMapping
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		claimValue, role, ok := strings.Cut(pair, "=")
+		claimValue, role = strings.TrimSpace(claimValue), strings.TrimSpace(role)
+		if !ok || claimValue == "" {
+			return nil, fmt.Errorf("invalid role mapping %q, expected value=role", pair)
+		}
+
+		if !entity.IsRole(role) {
+			return nil, fmt.Errorf("role mapping %q refers to unknown role %q", pair, role)
+		}
+
+		mappings = append(mappings, oidcRoleMapping{value: claimValue, role: role})
+	}
+
+	return mappings, nil
+}
+
+// NewOidcService creates the single sign-on service. provider is nil when single sign-on is
+// not configured, and every call then fails with ErrOidcDisabled.
+func NewOidcService(oidcRepository repository.OidcRepository, authRepository repository.AuthRepository, userRepository repository.UserRepository, authService AuthService, mfaService MfaService, provider port.IdentityProvider, cfg *config.Config) (OidcService, error) {
+	roleMappings, err := parseOidcRoleMapping(cfg.App.OidcRoleMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.App.OidcDefaultRole != "" {
+		if !entity.IsRole(cfg.App.OidcDefaultRole) {
+			return nil, fmt.Errorf("unknown default role %q", cfg.App.OidcDefaultRole)
+		}
+	}
+
+	return &oidcService{
+		oidcRepository: oidcRepository,
+		authRepository: authRepository,
+		userRepository: userRepository,
+		authService:    authService,
+		mfaService:     mfaService,
+		provider:       provider,
+		cfg:            cfg,
+		roleMappings:   roleMappings,
+	}, nil
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"portal-blog/config"
+	"portal-blog/internal/adapter/oidc"
+	"portal-blog/internal/adapter/oidc/oidctest"
+	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/entity"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// The tests below sign in through a real provider talking to an oidctest issuer; only the
+// repositories and the services CompleteLogin hands over to are fakes.
+
+type oidcTestUsers struct {
+	mu    sync.Mutex
+	users map[int64]entity.UserEntity
+}
+
+func (s *oidcTestUsers) add(user entity.UserEntity) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user.ID = int64(len(s.users) + 1)
+	s.users[user.ID] = user
+	return user.ID
+}
+
+func (s *oidcTestUsers) get(id int64) entity.UserEntity {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.users[id]
+}
+
+type fakeOidcRepository struct {
+	users *oidcTestUsers
+
+	mu         sync.Mutex
+	states     map[string]entity.OidcStateEntity
+	identities map[string]int64
+}
+
+func (f *fakeOidcRepository) CreateOidcState(ctx context.Context, req entity.OidcStateEntity) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.states[req.StateHash] = req
+	return nil
+}
+
+func (f *fakeOidcRepository) ConsumeOidcState(ctx context.Context, stateHash string) (*entity.OidcStateEntity, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored, ok := f.states[stateHash]
+	if !ok {
+		return nil, repository.ErrOidcStateNotFound
+	}
+	delete(f.states, stateHash)
+
+	return &stored, nil
+}
+
+func (f *fakeOidcRepository) GetUserIDByIdentity(ctx context.Context, issuer, subject string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	userID, ok := f.identities[issuer+" "+subject]
+	if !ok {
+		return 0, repository.ErrOidcIdentityNotFound
+	}
+
+	return userID, nil
+}
+
+func (f *fakeOidcRepository) LinkUserIdentity(ctx context.Context, userID int64, identity entity.OidcIdentityEntity) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.identities[identity.Issuer+" "+identity.Subject] = userID
+	return nil
+}
+
+func (f *fakeOidcRepository) CreateUserWithIdentity(ctx context.Context, user entity.UserEntity, identity entity.OidcIdentityEntity) (int64, error) {
+	userID := f.users.add(user)
+	return userID, f.LinkUserIdentity(ctx, userID, identity)
+}
+
+func (f *fakeOidcRepository) TouchUserIdentity(ctx context.Context, issuer, subject string) error {
+	return nil
+}
+
+// tamperState changes the stored nonce of every pending state.
+func (f *fakeOidcRepository) tamperState(nonce string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for hash, stored := range f.states {
+		stored.Nonce = nonce
+		f.states[hash] = stored
+	}
+}
+
+func (f *fakeOidcRepository) linkedUser(issuer, subject string) (int64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	userID, ok := f.identities[issuer+" "+subject]
+	return userID, ok
+}
+
+// fakeOidcAuthRepository only implements what CompleteLogin uses; any other call panics.
+type fakeOidcAuthRepository struct {
+	repository.AuthRepository
+	users *oidcTestUsers
+}
+
+func (f fakeOidcAuthRepository) GetUserByEmail(ctx context.Context, req entity.LoginRequest) (*entity.UserEntity, error) {
+	f.users.mu.Lock()
+	defer f.users.mu.Unlock()
+
+	for _, user := range f.users.users {
+		if user.Email == req.Email {
+			return &user, nil
+		}
+	}
+
+	return nil, repository.ErrUserNotFound
+}
+
+func (f fakeOidcAuthRepository) GetUserByID(ctx context.Context, id int64) (*entity.UserEntity, error) {
+	user := f.users.get(id)
+	if user.ID == 0 {
+		return nil, repository.ErrUserNotFound
+	}
+
+	return &user, nil
+}
+
+// fakeOidcUserRepository only implements what CompleteLogin uses; any other call panics.
+type fakeOidcUserRepository struct {
+	repository.UserRepository
+	users *oidcTestUsers
+}
+
+func (f fakeOidcUserRepository) UpdateUser(ctx context.Context, req entity.UserEntity) error {
+	f.users.mu.Lock()
+	defer f.users.mu.Unlock()
+
+	user := f.users.users[req.ID]
+	user.Role = req.Role
+	f.users.users[req.ID] = user
+	return nil
+}
+
+// fakeOidcAuthService only implements what CompleteLogin uses; any other call panics.
+type fakeOidcAuthService struct {
+	AuthService
+}
+
+func (fakeOidcAuthService) LoginExternal(ctx context.Context, userID int64, client entity.ClientInfo) (*entity.AccessToken, error) {
+	return &entity.AccessToken{AccessToken: "access-token"}, nil
+}
+
+// fakeOidcMfaService only implements what CompleteLogin uses; any other call panics.
+type fakeOidcMfaService struct {
+	MfaService
+	status entity.MfaStatusEntity
+}
+
+func (f fakeOidcMfaService) GetStatus(ctx context.Context, userID int64) (*entity.MfaStatusEntity, error) {
+	return &f.status, nil
+}
+
+type oidcTestEnv struct {
+	service        OidcService
+	oidcRepository *fakeOidcRepository
+	users          *oidcTestUsers
+	issuerURL      string
+}
+
+// newOidcTestEnv starts an issuer signing in the identity in opts, with groups mapped to
+// roles as "writers=author,editors=editor".
+func newOidcTestEnv(t *testing.T, opts oidctest.IssuerOptions, linkExisting bool, mfa entity.MfaStatusEntity) *oidcTestEnv {
+	t.Helper()
+
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	entity.SetRolePermissions(map[string][]string{
+		entity.RoleAdmin:       nil,
+		entity.RoleEditor:      nil,
+		entity.RoleAuthor:      nil,
+		entity.RoleContributor: nil,
+	})
+
+	var issuer http.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	opts.IssuerURL = server.URL
+	opts.ClientID = "portal-blog"
+	opts.RoleClaim = "groups"
+	issuer, err := oidctest.NewIssuer(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{App: config.App{
+		OidcIssuerURL:         server.URL,
+		OidcClientID:          "portal-blog",
+		OidcRedirectURL:       "http://portal.test/oidc/callback",
+		OidcScopes:            "openid email profile",
+		OidcRoleClaim:         "groups",
+		OidcRoleMapping:       "writers=author,editors=editor",
+		OidcDefaultRole:       entity.RoleContributor,
+		OidcLinkExistingUsers: linkExisting,
+		OidcStateTTL:          time.Minute,
+	}}
+
+	users := &oidcTestUsers{users: map[int64]entity.UserEntity{}}
+	oidcRepository := &fakeOidcRepository{
+		users:      users,
+		states:     map[string]entity.OidcStateEntity{},
+		identities: map[string]int64{},
+	}
+
+	svc, err := NewOidcService(oidcRepository, fakeOidcAuthRepository{users: users}, fakeOidcUserRepository{users: users},
+		fakeOidcAuthService{}, fakeOidcMfaService{status: mfa}, oidc.NewProvider(cfg), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &oidcTestEnv{service: svc, oidcRepository: oidcRepository, users: users, issuerURL: server.URL}
+}
+
+// authorize starts a login and follows the authorization URL, returning the code and state
+// the issuer redirects back with.
+func (e *oidcTestEnv) authorize(t *testing.T) (string, string) {
+	t.Helper()
+
+	login, err := e.service.StartLogin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(login.AuthorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	location, err := resp.Location()
+	if err != nil {
+		t.Fatalf("authorization was not redirected: status %d", resp.StatusCode)
+	}
+
+	query := location.Query()
+	if query.Get("state") != login.State {
+		t.Fatalf("state %q, want %q", query.Get("state"), login.State)
+	}
+
+	return query.Get("code"), query.Get("state")
+}
+
+func (e *oidcTestEnv) completeLogin(authCode, state string) (*entity.AccessToken, error) {
+	return e.service.CompleteLogin(context.Background(), authCode, state, entity.ClientInfo{})
+}
+
+func TestCompleteLoginRejectsReusedState(t *testing.T) {
+	env := newOidcTestEnv(t, oidctest.IssuerOptions{
+		Subject: "subject-1",
+		Email:   "writer@example.com",
+		Roles:   []string{"writers"},
+	}, false, entity.MfaStatusEntity{})
+
+	authCode, state := env.authorize(t)
+
+	if _, err := env.completeLogin(authCode, state); err != nil {
+		t.Fatalf("first login: %v", err)
+	}
+
+	if _, err := env.completeLogin(authCode, state); !errors.Is(err, ErrInvalidOidcState) {
+		t.Fatalf("reused state: error %v, want %v", err, ErrInvalidOidcState)
+	}
+
+	if _, err := env.completeLogin(authCode, state+"x"); !errors.Is(err, ErrInvalidOidcState) {
+		t.Fatalf("unknown state: error %v, want %v", err, ErrInvalidOidcState)
+	}
+}
+
+func TestCompleteLoginRejectsNonceMismatch(t *testing.T) {
+	env := newOidcTestEnv(t, oidctest.IssuerOptions{
+		Subject: "subject-1",
+		Email:   "writer@example.com",
+		Roles:   []string{"writers"},
+	}, false, entity.MfaStatusEntity{})
+
+	authCode, state := env.authorize(t)
+	env.oidcRepository.tamperState("another-nonce")
+
+	if _, err := env.completeLogin(authCode, state); !errors.Is(err, ErrOidcExchangeFailed) {
+		t.Fatalf("error %v, want %v", err, ErrOidcExchangeFailed)
+	}
+
+	if _, ok := env.oidcRepository.linkedUser(env.issuerURL, "subject-1"); ok {
+		t.Fatal("identity was linked after a nonce mismatch")
+	}
+}
+
+func TestCompleteLoginRefusesToLinkProtectedAccount(t *testing.T) {
+	tests := []struct {
+		name string
+		mfa  entity.MfaStatusEntity
+		want error
+	}{
+		{"mfa enabled", entity.MfaStatusEntity{Enabled: true}, ErrOidcAccountProtected},
+		{"mfa required by role", entity.MfaStatusEntity{Required: true}, ErrOidcAccountProtected},
+		{"not protected", entity.MfaStatusEntity{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOidcTestEnv(t, oidctest.IssuerOptions{
+				Subject: "subject-1",
+				Email:   "editor@example.com",
+				Roles:   []string{"editors"},
+			}, true, tt.mfa)
+			existingID := env.users.add(entity.UserEntity{Email: "editor@example.com", Role: entity.RoleEditor})
+
+			_, err := env.completeLogin(env.authorize(t))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error %v, want %v", err, tt.want)
+			}
+
+			userID, linked := env.oidcRepository.linkedUser(env.issuerURL, "subject-1")
+			if tt.want != nil && linked {
+				t.Fatal("protected account was linked")
+			}
+			if tt.want == nil && userID != existingID {
+				t.Fatalf("identity linked to user %d, want %d", userID, existingID)
+			}
+		})
+	}
+}
+
+func TestCompleteLoginNeverDemotesAdmin(t *testing.T) {
+	tests := []struct {
+		name string
+		role string
+		want string
+	}{
+		{"admin keeps role", entity.RoleAdmin, entity.RoleAdmin},
+		{"editor follows mapping", entity.RoleEditor, entity.RoleAuthor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOidcTestEnv(t, oidctest.IssuerOptions{
+				Subject: "subject-1",
+				Email:   "someone@example.com",
+				Roles:   []string{"writers"},
+			}, false, entity.MfaStatusEntity{})
+			userID := env.users.add(entity.UserEntity{Email: "someone@example.com", Role: tt.role})
+			env.oidcRepository.identities[env.issuerURL+" subject-1"] = userID
+
+			if _, err := env.completeLogin(env.authorize(t)); err != nil {
+				t.Fatal(err)
+			}
+
+			if role := env.users.get(userID).Role; role != tt.want {
+				t.Fatalf("role %q, want %q", role, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"portal-blog/config"
+	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/apperror"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/internal/core/port"
+	"portal-blog/lib/auth"
+	"portal-blog/lib/conv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var ErrInvalidResetToken = apperror.Validation("invalid_reset_token", "invalid or expired password reset token")
+
+type PasswordResetService interface {
+	ForgotPassword(ctx context.Context, email, ip string) error
+	ResetPassword(ctx context.Context, token, newPass string) error
+}
+
+type passwordResetService struct {
+	passwordResetRepository repository.PasswordResetRepository
+	authRepository          repository.AuthRepository
+	userService             UserService
+	mailer                  port.Mailer
+	loginThrottle           auth.LoginThrottle
+	cfg                     *config.Config
+}
+
+// ForgotPassword implements PasswordResetService.
+//
+// When the email belongs to an active user a single-use reset token is stored (hashed) and
+// a reset link is mailed to them. Unknown or inactive emails are silently ignored and the
+// mail is sent in the background, so the caller cannot tell whether the email exists.
+//
+// Requests are counted per client IP and per email with the login throttle, under their own
+// keys, whether or not the email exists. Once either is throttled a TooManyLoginAttemptsError
+// is returned and no mail is sent.
+func (p *passwordResetService) ForgotPassword(ctx context.Context, email, ip string) error {
+	ipKey, emailKey := passwordResetAttemptKeys(email, ip)
+
+	wait, err := p.loginThrottle.Check(ctx, ipKey, emailKey)
+	if err != nil {
+		code := "[SERVICE] ForgotPassword - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if wait > 0 {
+		code := "[SERVICE] ForgotPassword - 2"
+		err = &TooManyLoginAttemptsError{RetryAfter: wait}
+		log.Errorw(code, err)
+		return err
+	}
+
+	p.registerRequest(ctx, ipKey, emailKey)
+
+	user, err := p.authRepository.GetUserByEmail(ctx, entity.LoginRequest{Email: email})
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil
+		}
+
+		code := "[SERVICE] ForgotPassword - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if !user.IsActive {
+		return nil
+	}
+
+	token, err := conv.GenerateRandomToken(32)
+	if err != nil {
+		code := "[SERVICE] ForgotPassword - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	expiresAt := time.Now().Add(p.cfg.App.PasswordResetTTL)
+	err = p.passwordResetRepository.CreatePasswordResetToken(ctx, entity.PasswordResetTokenEntity{
+		UserID:    user.ID,
+		TokenHash: conv.HashToken(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		code := "[SERVICE] ForgotPassword - 5"
+		log.Errorw(code, err)
+		return err
+	}
+
+	mail := entity.MailEntity{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s/reset-password?token=%s\n\nThe link expires at %s. If you did not request a password reset you can ignore this email.\n",
+			user.Name, p.cfg.App.FrontendURL, url.QueryEscape(token), expiresAt.Format(time.RFC1123)),
+	}
+
+	go func() {
+		if err := p.mailer.Send(context.Background(), mail); err != nil {
+			log.Errorw("[SERVICE] ForgotPassword - 6", err)
+		}
+	}()
+
+	return nil
+}
+
+// ResetPassword implements PasswordResetService.
+//
+// The token is consumed before the password is changed so it can only be used once. If the new
+// password is rejected the token is released again, letting the user retry with another password.
+// On success every other pending reset token of the user is invalidated and all sessions are signed out.
+func (p *passwordResetService) ResetPassword(ctx context.Context, token, newPass string) error {
+	stored, err := p.passwordResetRepository.GetPasswordResetTokenByHash(ctx, conv.HashToken(token))
+	if err != nil {
+		code := "[SERVICE] ResetPassword - 1"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrPasswordResetNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		code := "[SERVICE] ResetPassword - 2"
+		log.Errorw(code, ErrInvalidResetToken)
+		return ErrInvalidResetToken
+	}
+
+	err = p.passwordResetRepository.ConsumePasswordResetToken(ctx, stored.ID)
+	if err != nil {
+		code := "[SERVICE] ResetPassword - 3"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrPasswordResetTokenUsed) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	err = p.userService.ResetPassword(ctx, stored.UserID, newPass)
+	if err != nil {
+		code := "[SERVICE] ResetPassword - 4"
+		log.Errorw(code, err)
+		if releaseErr := p.passwordResetRepository.ReleasePasswordResetToken(ctx, stored.ID); releaseErr != nil {
+			log.Errorw(code, releaseErr)
+		}
+		return err
+	}
+
+	err = p.passwordResetRepository.InvalidateUserPasswordResetTokens(ctx, stored.UserID)
+	if err != nil {
+		code := "[SERVICE] ResetPassword - 5"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// registerRequest counts a password reset request for the client IP and the email. Counter
+// errors are only logged so they do not reveal anything about the email.
+func (p *passwordResetService) registerRequest(ctx context.Context, ipKey, emailKey string) {
+	if _, err := p.loginThrottle.RegisterFailure(ctx, ipKey, p.cfg.App.LoginIPMaxFailures); err != nil {
+		log.Errorw("[SERVICE] registerRequest - 1", err)
+	}
+
+	if _, err := p.loginThrottle.RegisterFailure(ctx, emailKey, p.cfg.App.LoginMaxFailures); err != nil {
+		log.Errorw("[SERVICE] registerRequest - 2", err)
+	}
+}
+
+// passwordResetAttemptKeys returns the request counter keys of the client IP and the email,
+// kept apart from the failed login counters.
+func passwordResetAttemptKeys(email, ip string) (string, string) {
+	return "reset-ip:" + ip, "reset-account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func NewPasswordResetService(
+	passwordResetRepository repository.PasswordResetRepository,
+	authRepository repository.AuthRepository,
+	userService UserService,
+	mailer port.Mailer,
+	loginThrottle auth.LoginThrottle,
+	cfg *config.Config,
+) PasswordResetService {
+	return &passwordResetService{
+		passwordResetRepository: passwordResetRepository,
+		authRepository:          authRepository,
+		userService:             userService,
+		mailer:                  mailer,
+		loginThrottle:           loginThrottle,
+		cfg:                     cfg,
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/apperror"
+	"portal-blog/internal/core/domain/entity"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var ErrRoleNotFound = apperror.NotFound("role_not_found", "role not found")
+
+type RoleService interface {
+	GetRoles(ctx context.Context) ([]entity.RoleEntity, error)
+	SetRoleMfaRequired(ctx context.Context, name string, required bool) error
+	RefreshRolePermissions(ctx context.Context) error
+}
+
+type roleService struct {
+	roleRepository repository.RoleRepository
+}
+
+// GetRoles implements RoleService.
+func (r *roleService) GetRoles(ctx context.Context) ([]entity.RoleEntity, error) {
+	results, err := r.roleRepository.GetRoles(ctx)
+	if err != nil {
+		code := "[SERVICE] GetRoles - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// SetRoleMfaRequired implements RoleService.
+// Users of a role that requires MFA must complete a second factor, enrolling first if needed,
+// on their next login.
+func (r *roleService) SetRoleMfaRequired(ctx context.Context, name string, required bool) error {
+	err := r.roleRepository.UpdateRoleMfaRequired(ctx, name, required)
+	if err != nil {
+		code := "[SERVICE] SetRoleMfaRequired - 1"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrRoleNotFound) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RefreshRolePermissions implements RoleService.
+// The permissions of every role are reloaded from the database into the cache the
+// middleware and services check them against.
+func (r *roleService) RefreshRolePermissions(ctx context.Context) error {
+	permissions, err := r.roleRepository.GetRolePermissions(ctx)
+	if err != nil {
+		code := "[SERVICE] RefreshRolePermissions - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	entity.SetRolePermissions(permissions)
+
+	return nil
+}
+
+// RunRolePermissionsRefresh reloads the role permissions every interval until ctx is
+// cancelled, so changes to the role_permissions table apply without a restart. It is meant to
+// run in its own goroutine for the lifetime of the server.
+func RunRolePermissionsRefresh(ctx context.Context, roleService RoleService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := roleService.RefreshRolePermissions(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Errorw("[SERVICE] RunRolePermissionsRefresh - 1", err)
+			}
+		}
+	}
+}
+
+func NewRoleService(roleRepository repository.RoleRepository) RoleService {
+	return &roleService{roleRepository: roleRepository}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/entity"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// minSuggestLength is the shortest query type-ahead suggestions are looked up for.
+const minSuggestLength = 2
+
+type SearchService interface {
+	Suggest(ctx context.Context, q string, limit int) (*entity.SearchSuggestionEntity, error)
+	DidYouMean(ctx context.Context, q string) (string, error)
+	RefreshSearchTerms(ctx context.Context) error
+}
+
+type searchService struct {
+	searchRepository repository.SearchRepository
+}
+
+// Suggest implements SearchService.
+// Up to limit titles of live contents, categories and tags are returned for q; queries shorter
+// than two characters match nothing.
+func (s *searchService) Suggest(ctx context.Context, q string, limit int) (*entity.SearchSuggestionEntity, error) {
+	q = strings.TrimSpace(q)
+	result := entity.SearchSuggestionEntity{
+		Contents:   []entity.ContentEntity{},
+		Categories: []entity.CategoryEntity{},
+		Tags:       []string{},
+	}
+
+	if len([]rune(q)) < minSuggestLength {
+		return &result, nil
+	}
+
+	var err error
+	result.Contents, err = s.searchRepository.SuggestContents(ctx, q, limit)
+	if err != nil {
+		code := "[SERVICE] Suggest - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	result.Categories, err = s.searchRepository.SuggestCategories(ctx, q, limit)
+	if err != nil {
+		code := "[SERVICE] Suggest - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	result.Tags, err = s.searchRepository.SuggestTags(ctx, q, limit)
+	if err != nil {
+		code := "[SERVICE] Suggest - 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DidYouMean implements SearchService.
+// Every word of q that live contents do not use is replaced by the most similar word they
+// do use. The web search syntax of q is kept as it is: quotes, the OR operator and excluded
+// -words, which are not corrected. It returns the corrected query, or "" when there is
+// nothing to correct.
+func (s *searchService) DidYouMean(ctx context.Context, q string) (string, error) {
+	var b strings.Builder
+	corrected := false
+	inPhrase := false
+	excluded := false
+
+	runes := []rune(q)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			switch r := runes[i]; {
+			case r == '"':
+				inPhrase = !inPhrase
+			case r == '-' && !inPhrase && (i == 0 || unicode.IsSpace(runes[i-1])):
+				excluded = true
+			case unicode.IsSpace(r):
+				excluded = false
+			}
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(runes) && isWordRune(runes[i]) {
+			i++
+		}
+		word := string(runes[start:i])
+
+		isOperator := !inPhrase && strings.EqualFold(word, "or")
+		if excluded || isOperator || i-start <= minSuggestLength {
+			excluded = false
+			b.WriteString(word)
+			continue
+		}
+
+		lower := strings.ToLower(word)
+		term, err := s.searchRepository.GetClosestSearchTerm(ctx, lower)
+		if err != nil {
+			code := "[SERVICE] DidYouMean - 1"
+			log.Errorw(code, err)
+			return "", err
+		}
+
+		if term != "" && term != lower {
+			word = term
+			corrected = true
+		}
+		b.WriteString(word)
+	}
+
+	if !corrected {
+		return "", nil
+	}
+
+	return b.String(), nil
+}
+
+// isWordRune reports whether r is part of a word in a search query.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// RefreshSearchTerms implements SearchService.
+func (s *searchService) RefreshSearchTerms(ctx context.Context) error {
+	err := s.searchRepository.RefreshSearchTerms(ctx)
+	if err != nil {
+		code := "[SERVICE] RefreshSearchTerms - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// RunSearchTermsRefresh collects the words "did you mean" suggestions are made from every
+// interval until ctx is cancelled, so they follow contents going live and being unpublished.
+func RunSearchTermsRefresh(ctx context.Context, searchService SearchService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := searchService.RefreshSearchTerms(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Errorw("[SERVICE] RunSearchTermsRefresh - 1", err)
+			}
+		}
+	}
+}
+
+func NewSearchService(searchRepository repository.SearchRepository) SearchService {
+	return &searchService{searchRepository: searchRepository}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/apperror"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/lib/auth"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var ErrSessionNotFound = apperror.NotFound("session_not_found", "session not found")
+
+const (
+	// sessionTouchInterval limits how often last_seen_at is written for a session.
+	sessionTouchInterval = time.Minute
+	// maxSessionUserAgentLength is the size of the user_agent column.
+	maxSessionUserAgentLength = 512
+)
+
+type SessionService interface {
+	GetSessions(ctx context.Context, userID int64) ([]entity.SessionEntity, error)
+	RevokeSession(ctx context.Context, id, userID int64) error
+	RevokeAllSessions(ctx context.Context, userID int64) error
+	VerifySession(ctx context.Context, jti string) (int64, error)
+}
+
+type sessionService struct {
+	sessionRepository repository.SessionRepository
+	authService       AuthService
+}
+
+// GetSessions implements SessionService.
+// Only sessions that are neither signed out nor expired are listed.
+func (s *sessionService) GetSessions(ctx context.Context, userID int64) ([]entity.SessionEntity, error) {
+	results, err := s.sessionRepository.GetActiveSessionsByUserID(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] GetSessions - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// RevokeSession implements SessionService.
+// The session's refresh tokens stop working at once and its access tokens are rejected by
+// VerifySession on their next request.
+func (s *sessionService) RevokeSession(ctx context.Context, id, userID int64) error {
+	err := s.sessionRepository.RevokeSession(ctx, id, userID)
+	if err != nil {
+		code := "[SERVICE] RevokeSession - 1"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAllSessions implements SessionService.
+// It signs the user out everywhere, including the session making the request.
+func (s *sessionService) RevokeAllSessions(ctx context.Context, userID int64) error {
+	err := s.authService.RevokeOtherSessions(ctx, userID, "")
+	if err != nil {
+		code := "[SERVICE] RevokeAllSessions - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// VerifySession implements auth.SessionVerifier.
+// Access tokens without a known session are rejected like tokens of a revoked one. The
+// session's last_seen_at is refreshed at most once per minute.
+func (s *sessionService) VerifySession(ctx context.Context, jti string) (int64, error) {
+	session, err := s.sessionRepository.GetSessionByAccessTokenJTI(ctx, jti)
+	if err != nil {
+		code := "[SERVICE] VerifySession - 1"
+		log.Errorw(code, err)
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return 0, auth.ErrSessionRevoked
+		}
+		return 0, err
+	}
+
+	now := time.Now()
+	if session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+		return 0, auth.ErrSessionRevoked
+	}
+
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		if err = s.sessionRepository.TouchSession(ctx, session.ID, sessionTouchInterval); err != nil {
+			code := "[SERVICE] VerifySession - 2"
+			log.Errorw(code, err)
+		}
+	}
+
+	return session.ID, nil
+}
+
+// truncate shortens s to at most n bytes without splitting a UTF-8 sequence.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && s[n]&0xC0 == 0x80 {
+		n--
+	}
+
+	return s[:n]
+}
+
+func NewSessionService(sessionRepository repository.SessionRepository, authService AuthService) SessionService {
+	return &sessionService{sessionRepository: sessionRepository, authService: authService}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/apperror"
+	"portal-blog/internal/core/domain/entity"
+	"portal-blog/lib/conv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var (
+	ErrTagTitleBlank      = apperror.Validation("tag_title_blank", "the tag title cannot be blank")
+	ErrTagSlugTaken       = apperror.Conflict("tag_slug_taken", "another tag already uses this slug, merge the two tags instead")
+	ErrTagMergeIntoItself = apperror.Validation("tag_merge_into_itself", "a tag cannot be merged into itself")
+)
+
+type TagService interface {
+	GetTags(ctx context.Context) ([]entity.TagEntity, error)
+	GetPublicTags(ctx context.Context) ([]entity.TagEntity, error)
+	GetTagByID(ctx context.Context, id int64) (*entity.TagEntity, error)
+	CreateTag(ctx context.Context, req entity.TagEntity) (*entity.TagEntity, error)
+	UpdateTag(ctx context.Context, req entity.TagEntity) (*entity.TagEntity, error)
+	DeleteTag(ctx context.Context, id int64) error
+	MergeTags(ctx context.Context, sourceID, targetID int64) (*entity.TagEntity, error)
+}
+
+type tagService struct {
+	tagRepository repository.TagRepository
+}
+
+// GetTags implements TagService.
+// Every tag is returned, with the number of contents in any status that have it.
+func (t *tagService) GetTags(ctx context.Context) ([]entity.TagEntity, error) {
+	results, err := t.tagRepository.GetTags(ctx, false)
+	if err != nil {
+		code := "[SERVICE] GetTags - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetPublicTags implements TagService.
+// Only tags of live contents are returned, the most used first.
+func (t *tagService) GetPublicTags(ctx context.Context) ([]entity.TagEntity, error) {
+	results, err := t.tagRepository.GetTags(ctx, true)
+	if err != nil {
+		code := "[SERVICE] GetPublicTags - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTagByID implements TagService.
+func (t *tagService) GetTagByID(ctx context.Context, id int64) (*entity.TagEntity, error) {
+	result, err := t.tagRepository.GetTagByID(ctx, id)
+	if err != nil {
+		code := "[SERVICE] GetTagByID - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateTag implements TagService.
+// Without a slug of its own the tag gets one made from its title.
+func (t *tagService) CreateTag(ctx context.Context, req entity.TagEntity) (*entity.TagEntity, error) {
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		return nil, ErrTagTitleBlank
+	}
+
+	if req.Slug == "" {
+		req.Slug = conv.Slugify(req.Title)
+	}
+
+	if err := t.checkSlug(ctx, req.Slug, 0); err != nil {
+		code := "[SERVICE] CreateTag - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	result, err := t.tagRepository.CreateTag(ctx, req)
+	if err != nil {
+		code := "[SERVICE] CreateTag - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateTag implements TagService.
+// Renaming a tag without giving a slug makes a new one from the title. The tag is renamed in
+// every content that has it; to give it the slug of another tag, merge the two instead.
+func (t *tagService) UpdateTag(ctx context.Context, req entity.TagEntity) (*entity.TagEntity, error) {
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		return nil, ErrTagTitleBlank
+	}
+
+	current, err := t.tagRepository.GetTagByID(ctx, req.ID)
+	if err != nil {
+		code := "[SERVICE] UpdateTag - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if req.Slug == "" {
+		req.Slug = current.Slug
+		if req.Title != current.Title {
+			req.Slug = conv.Slugify(req.Title)
+		}
+		if req.Slug == "" {
+			req.Slug = fmt.Sprintf("tag-%d", req.ID)
+		}
+	}
+
+	if err := t.checkSlug(ctx, req.Slug, req.ID); err != nil {
+		code := "[SERVICE] UpdateTag - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if err := t.tagRepository.UpdateTag(ctx, req); err != nil {
+		code := "[SERVICE] UpdateTag - 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	current.Title = req.Title
+	current.Slug = req.Slug
+
+	return current, nil
+}
+
+// DeleteTag implements TagService.
+// The tag is removed from the contents that have it; the contents themselves are kept.
+func (t *tagService) DeleteTag(ctx context.Context, id int64) error {
+	err := t.tagRepository.DeleteTag(ctx, id)
+	if err != nil {
+		code := "[SERVICE] DeleteTag - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// MergeTags implements TagService.
+// The contents of the source tag are given the target tag and the source tag is deleted. It
+// returns the target tag with its new number of contents.
+func (t *tagService) MergeTags(ctx context.Context, sourceID, targetID int64) (*entity.TagEntity, error) {
+	if sourceID == targetID {
+		return nil, ErrTagMergeIntoItself
+	}
+
+	err := t.tagRepository.MergeTags(ctx, sourceID, targetID)
+	if err != nil {
+		code := "[SERVICE] MergeTags - 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	result, err := t.tagRepository.GetTagByID(ctx, targetID)
+	if err != nil {
+		code := "[SERVICE] MergeTags - 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkSlug returns ErrTagSlugTaken when a tag other than the one with the given ID, zero for
+// a new one, has slug. An empty slug is never taken.
+func (t *tagService) checkSlug(ctx context.Context, slug string, tagID int64) error {
+	if slug == "" {
+		return nil
+	}
+
+	existing, err := t.tagRepository.GetTagBySlug(ctx, slug)
+	if errors.Is(err, repository.ErrTagNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.ID != tagID {
+		return ErrTagSlugTaken
+	}
+
+	return nil
+}
+
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{tagRepository: tagRepo}
+}
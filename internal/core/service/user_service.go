@@ -2,20 +2,41 @@ package service
 
 import (
 	"context"
+	"errors"
+	"portal-blog/config"
 	"portal-blog/internal/adapter/repository"
+	"portal-blog/internal/core/domain/apperror"
 	"portal-blog/internal/core/domain/entity"
 	"portal-blog/lib/conv"
 
 	"github.com/gofiber/fiber/v2/log"
 )
 
+var (
+	ErrEmailAlreadyUsed       = apperror.Conflict("email_already_used", "email is already used by another user")
+	ErrCannotModifySelf       = apperror.Validation("cannot_modify_self", "you cannot deactivate or delete your own account")
+	ErrReassignRequired       = apperror.Conflict("reassign_required", "user still owns contents or categories, choose a user to reassign them to")
+	ErrInvalidReassignTarget  = apperror.Validation("invalid_reassign_target", "contents can only be reassigned to another active user")
+	ErrInvalidCurrentPassword = apperror.Validation("invalid_current_password", "current password is incorrect")
+	ErrPasswordReused         = apperror.Validation("password_reused", "new password must differ from your recent passwords")
+)
+
 type UserService interface {
-	UpdatePassword(ctx context.Context, newPass string, id int64) error
+	UpdatePassword(ctx context.Context, currentPass, newPass string, actor *entity.JwtData) error
+	ResetPassword(ctx context.Context, id int64, newPass string) error
 	GetUserByID(ctx context.Context, id int64) (*entity.UserEntity, error)
+
+	GetUsers(ctx context.Context, query entity.UserQueryString) ([]entity.UserEntity, int64, int64, error)
+	CreateUser(ctx context.Context, req entity.UserEntity) error
+	UpdateUser(ctx context.Context, req entity.UserEntity) error
+	UpdateUserStatus(ctx context.Context, id int64, isActive bool, actor *entity.JwtData) error
+	DeleteUser(ctx context.Context, id int64, reassignTo int64, actor *entity.JwtData) error
 }
 
 type userService struct {
 	userRepository repository.UserRepository
+	authService    AuthService
+	cfg            *config.Config
 }
 
 // GetUserByID retrieves a user from the service layer by delegating to the repository.
@@ -39,24 +60,36 @@ func (u *userService) GetUserByID(ctx context.Context, id int64) (*entity.UserEn
 
 }
 
-// UpdatePassword hashes the new password and updates it in the database.
+// UpdatePassword verifies the current password, hashes the new password and updates it in the database.
+//
+// The new password may not match the current password or any of the last PasswordHistorySize passwords.
+// After the change every other session of the user is signed out; the token used for the request stays valid.
 //
 // Parameters:
 //   - ctx: The context for handling request cancellations and timeouts.
+//   - currentPass: The user's current plain text password.
 //   - newPass: The new plain text password to be hashed and updated.
-//   - id: The unique identifier of the user whose password is being updated.
+//   - actor: The claims of the user whose password is being updated.
 //
 // Returns:
-//   - An error if hashing fails or if the update operation fails.
-func (u *userService) UpdatePassword(ctx context.Context, newPass string, id int64) error {
-	password, err := conv.HashPassword(newPass)
+//   - ErrInvalidCurrentPassword or ErrPasswordReused, or an error if hashing or the update operation fails.
+func (u *userService) UpdatePassword(ctx context.Context, currentPass, newPass string, actor *entity.JwtData) error {
+	id := int64(actor.UserID)
+
+	user, err := u.userRepository.GetUserByID(ctx, id)
 	if err != nil {
-		code := "[SERVICE] UpdatePassword - 2"
+		code := "[SERVICE] UpdatePassword - 1"
 		log.Errorw(code, err)
 		return err
 	}
 
-	err = u.userRepository.UpdatePassword(ctx, password, id)
+	if !conv.CheckPasswordHash(currentPass, user.Password) {
+		code := "[SERVICE] UpdatePassword - 2"
+		log.Errorw(code, ErrInvalidCurrentPassword)
+		return ErrInvalidCurrentPassword
+	}
+
+	err = u.changePassword(ctx, user, newPass, actor.ID)
 	if err != nil {
 		code := "[SERVICE] UpdatePassword - 3"
 		log.Errorw(code, err)
@@ -66,13 +99,284 @@ func (u *userService) UpdatePassword(ctx context.Context, newPass string, id int
 	return nil
 }
 
+// ResetPassword sets a new password for a user who proved ownership of their email
+// and signs out every session of that user.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - id: The unique identifier of the user.
+//   - newPass: The new plain text password.
+//
+// Returns:
+//   - ErrPasswordReused, or an error if hashing or the update operation fails.
+func (u *userService) ResetPassword(ctx context.Context, id int64, newPass string) error {
+	user, err := u.userRepository.GetUserByID(ctx, id)
+	if err != nil {
+		code := "[SERVICE] ResetPassword - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = u.changePassword(ctx, user, newPass, "")
+	if err != nil {
+		code := "[SERVICE] ResetPassword - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// changePassword rejects passwords found in the user's recent history, stores the new hash
+// and revokes every session except the one that issued keepJTI.
+func (u *userService) changePassword(ctx context.Context, user *entity.UserEntity, newPass, keepJTI string) error {
+	histories, err := u.userRepository.GetPasswordHistories(ctx, user.ID, u.cfg.App.PasswordHistorySize)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range append([]string{user.Password}, histories...) {
+		if conv.CheckPasswordHash(newPass, hash) {
+			return ErrPasswordReused
+		}
+	}
+
+	password, err := conv.HashPassword(newPass)
+	if err != nil {
+		return err
+	}
+
+	err = u.userRepository.UpdatePassword(ctx, password, user.ID, u.cfg.App.PasswordHistorySize)
+	if err != nil {
+		return err
+	}
+
+	return u.authService.RevokeOtherSessions(ctx, user.ID, keepJTI)
+}
+
+// GetUsers returns a page of users matching the query.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - query: The pagination and filter options.
+//
+// Returns:
+//   - The users, the total number of matching users and the total number of pages.
+//   - An error if the repository call fails.
+func (u *userService) GetUsers(ctx context.Context, query entity.UserQueryString) ([]entity.UserEntity, int64, int64, error) {
+	results, totalData, totalPages, err := u.userRepository.GetUsers(ctx, query)
+	if err != nil {
+		code := "[SERVICE] GetUsers - 1"
+		log.Errorw(code, err)
+		return nil, 0, 0, err
+	}
+
+	return results, totalData, totalPages, nil
+}
+
+// CreateUser hashes the password and creates a new active user.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - req: The user to create with a plain text password.
+//
+// Returns:
+//   - ErrEmailAlreadyUsed if the email is taken, or an error if hashing or the insert fails.
+func (u *userService) CreateUser(ctx context.Context, req entity.UserEntity) error {
+	count, err := u.userRepository.CountUsersByEmail(ctx, req.Email, 0)
+	if err != nil {
+		code := "[SERVICE] CreateUser - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if count > 0 {
+		return ErrEmailAlreadyUsed
+	}
+
+	password, err := conv.HashPassword(req.Password)
+	if err != nil {
+		code := "[SERVICE] CreateUser - 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	req.Password = password
+
+	err = u.userRepository.CreateUser(ctx, req)
+	if err != nil {
+		code := "[SERVICE] CreateUser - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateUser updates the name, email and optionally the role of a user. When the role
+// changes every session of the user is ended, as their tokens carry the old role.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - req: The user ID and the new values.
+//
+// Returns:
+//   - ErrEmailAlreadyUsed if another user has the email, or an error if the update fails.
+func (u *userService) UpdateUser(ctx context.Context, req entity.UserEntity) error {
+	count, err := u.userRepository.CountUsersByEmail(ctx, req.Email, req.ID)
+	if err != nil {
+		code := "[SERVICE] UpdateUser - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if count > 0 {
+		return ErrEmailAlreadyUsed
+	}
+
+	roleChanged := false
+	if req.Role != "" {
+		stored, err := u.userRepository.GetUserByID(ctx, req.ID)
+		if err != nil {
+			code := "[SERVICE] UpdateUser - 2"
+			log.Errorw(code, err)
+			return err
+		}
+		roleChanged = stored.Role != req.Role
+	}
+
+	err = u.userRepository.UpdateUser(ctx, req)
+	if err != nil {
+		code := "[SERVICE] UpdateUser - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if roleChanged {
+		err = u.authService.RevokeOtherSessions(ctx, req.ID, "")
+		if err != nil {
+			code := "[SERVICE] UpdateUser - 4"
+			log.Errorw(code, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateUserStatus activates or deactivates a user. Users cannot deactivate themselves.
+// Deactivating a user ends all their sessions.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - id: The unique identifier of the user.
+//   - isActive: The new value of the active flag.
+//   - actor: The claims of the user performing the change.
+//
+// Returns:
+//   - ErrCannotModifySelf when deactivating the acting user, or an error if the update fails.
+func (u *userService) UpdateUserStatus(ctx context.Context, id int64, isActive bool, actor *entity.JwtData) error {
+	if !isActive && id == int64(actor.UserID) {
+		return ErrCannotModifySelf
+	}
+
+	err := u.userRepository.UpdateUserStatus(ctx, id, isActive)
+	if err != nil {
+		code := "[SERVICE] UpdateUserStatus - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if !isActive {
+		err = u.authService.RevokeOtherSessions(ctx, id, "")
+		if err != nil {
+			code := "[SERVICE] UpdateUserStatus - 2"
+			log.Errorw(code, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteUser deletes a user. When the user still owns contents or categories they are
+// reassigned to reassignTo, which must be another active user. The user's sessions are ended
+// before the user is deleted.
+//
+// Parameters:
+//   - ctx: The context for handling request cancellations and timeouts.
+//   - id: The unique identifier of the user to delete.
+//   - reassignTo: The user receiving the contents and categories, or 0 when there is nothing to reassign.
+//   - actor: The claims of the user performing the deletion.
+//
+// Returns:
+//   - ErrCannotModifySelf, ErrReassignRequired or ErrInvalidReassignTarget, or an error if the deletion fails.
+func (u *userService) DeleteUser(ctx context.Context, id int64, reassignTo int64, actor *entity.JwtData) error {
+	if id == int64(actor.UserID) {
+		return ErrCannotModifySelf
+	}
+
+	owned, err := u.userRepository.CountUserOwnedRecords(ctx, id)
+	if err != nil {
+		code := "[SERVICE] DeleteUser - 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if owned > 0 && reassignTo == 0 {
+		return ErrReassignRequired
+	}
+
+	if reassignTo > 0 {
+		if reassignTo == id {
+			return ErrInvalidReassignTarget
+		}
+
+		target, err := u.userRepository.GetUserByID(ctx, reassignTo)
+		if err != nil {
+			code := "[SERVICE] DeleteUser - 2"
+			log.Errorw(code, err)
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return ErrInvalidReassignTarget
+			}
+			return err
+		}
+
+		if !target.IsActive {
+			return ErrInvalidReassignTarget
+		}
+	}
+
+	err = u.authService.RevokeOtherSessions(ctx, id, "")
+	if err != nil {
+		code := "[SERVICE] DeleteUser - 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = u.userRepository.DeleteUser(ctx, id, reassignTo)
+	if err != nil {
+		code := "[SERVICE] DeleteUser - 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
 // NewUserService creates a new instance of userService.
 //
 // Parameters:
 //   - userRepository: The repository instance to interact with the database.
+//   - authService: The auth service used to end sessions after a password, role or status change.
+//   - cfg: The application configuration.
 //
 // Returns:
 //   - A new UserService instance.
-func NewUserService(userRepository repository.UserRepository) UserService {
-	return &userService{userRepository: userRepository}
+func NewUserService(userRepository repository.UserRepository, authService AuthService, cfg *config.Config) UserService {
+	return &userService{
+		userRepository: userRepository,
+		authService:    authService,
+		cfg:            cfg,
+	}
 }
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+	"portal-blog/internal/core/domain/entity"
+	"strings"
+)
+
+// APIKeyPrefix starts every personal API key, which tells them apart from JWTs.
+const APIKeyPrefix = "pb_"
+
+// APIKeyVerifier resolves a personal API key to the claims of the user owning it.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*entity.JwtData, error)
+}
+
+// IsAPIKey reports whether token looks like a personal API key rather than a JWT.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
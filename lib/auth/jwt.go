@@ -1,30 +1,46 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"portal-blog/config"
 	"portal-blog/internal/core/domain/entity"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// ErrInvalidToken is wrapped by every error VerifyAccessToken returns.
+var ErrInvalidToken = errors.New("invalid token")
+
 type Jwt interface {
 	GenerateToken(data *entity.JwtData) (string, int64, error)
 	VerifyAccessToken(token string) (*entity.JwtData, error)
+	JWKS() JSONWebKeySet
 }
 
 type Options struct {
-	SigningKey string
-	Issuer     string
+	SigningKey     string
+	Issuer         string
+	AccessTokenTTL time.Duration
+	Audience       string
+	Leeway         time.Duration
+
+	// KeySet is set when tokens are signed with RS256 or EdDSA instead of the HS256 SigningKey.
+	KeySet *KeySet
 }
 
 // GenerateToken implements Jwt.
 // GenerateToken creates a new JWT token with the provided JwtData.
 //
-// This function generates a JWT token using the HS256 signing method. The token
+// This function generates a JWT token using the HS256 signing method, or the active
+// key of KeySet (whose kid is set in the token header) when one is configured. The token
 // includes claims from the provided JwtData and additional standard claims such
-// as expiration time, issuer, and not-before time.
+// as expiration time, issuer, and not-before time. The lifetime of the token is
+// taken from AccessTokenTTL.
 //
 // Parameters:
 //   - data: A pointer to a JwtData struct containing the claims to be included in the token.
@@ -35,12 +51,25 @@ type Options struct {
 //   - error: An error if token generation fails, or nil if successful.
 func (o *Options) GenerateToken(data *entity.JwtData) (string, int64, error) {
     now := time.Now().Local()
-    expireAt := now.Add(time.Hour * 24)
+    expireAt := now.Add(o.AccessTokenTTL)
     data.RegisteredClaims.ExpiresAt = jwt.NewNumericDate(expireAt)
     data.RegisteredClaims.Issuer = o.Issuer
     data.RegisteredClaims.NotBefore = jwt.NewNumericDate(now)
-    acToken := jwt.NewWithClaims(jwt.SigningMethodHS256, data)
-    accessToken, err := acToken.SignedString([]byte(o.SigningKey))
+    data.RegisteredClaims.Subject = strconv.FormatInt(int64(data.UserID), 10)
+    if o.Audience != "" {
+        data.RegisteredClaims.Audience = jwt.ClaimStrings{o.Audience}
+    }
+
+    var accessToken string
+    var err error
+    if o.KeySet != nil {
+        acToken := jwt.NewWithClaims(o.KeySet.active.method, data)
+        acToken.Header["kid"] = o.KeySet.active.kid
+        accessToken, err = acToken.SignedString(o.KeySet.active.privateKey)
+    } else {
+        acToken := jwt.NewWithClaims(jwt.SigningMethodHS256, data)
+        accessToken, err = acToken.SignedString([]byte(o.SigningKey))
+    }
     if err != nil {
         return "", 0, err
     }
@@ -52,47 +81,145 @@ func (o *Options) GenerateToken(data *entity.JwtData) (string, int64, error) {
 // VerifyAccessToken validates and parses a JWT access token.
 //
 // It takes a token string as input and attempts to verify its signature and validity.
-// If the token is valid, it extracts the claims and returns the user data.
+// Besides the signature, the expiry (required), not-before, issuer and audience are checked,
+// allowing Leeway of clock skew. The user ID is read from user_id, as a number or a numeric
+// string, or from sub when user_id is absent.
 //
 // Parameters:
 //   - token: A string representing the JWT access token to be verified.
 //
 // Returns:
-//   - *entity.JwtData: A pointer to a JwtData struct containing the extracted user information if the token is valid.
-//   - error: An error if the token is invalid, expired, or if there's any issue during the verification process.
+//   - *entity.JwtData: A pointer to a JwtData struct containing the extracted user information, token ID and expiry if the token is valid.
+//   - error: An error wrapping ErrInvalidToken if the token is malformed, invalid or expired.
 func (o *Options) VerifyAccessToken(token string) (*entity.JwtData, error) {
-    parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-        if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-            return nil, fmt.Errorf("signing method invalid")
+    claims := accessTokenClaims{}
+    _, err := jwt.ParseWithClaims(token, &claims, o.keyFunc, o.parserOptions()...)
+    if err != nil {
+        return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+    }
+
+    userID := int64(claims.UserID)
+    if userID == 0 && claims.Subject != "" {
+        userID, err = strconv.ParseInt(claims.Subject, 10, 64)
+        if err != nil {
+            return nil, fmt.Errorf("%w: subject is not a user ID", ErrInvalidToken)
         }
+    }
 
-        return []byte(o.SigningKey), nil
-    })
+    if userID <= 0 {
+        return nil, fmt.Errorf("%w: missing user ID", ErrInvalidToken)
+    }
 
-    if err != nil {
-        return nil, err
+    if claims.ID == "" {
+        return nil, fmt.Errorf("%w: missing token ID", ErrInvalidToken)
     }
 
-    if parsedToken.Valid {
-        claim, ok := parsedToken.Claims.(jwt.MapClaims)
-        if !ok || !parsedToken.Valid {
+    return &entity.JwtData{
+        UserID:           float64(userID),
+        Role:             claims.Role,
+        RegisteredClaims: claims.RegisteredClaims,
+    }, nil
+}
+
+// keyFunc returns the key a token has to be verified with, rejecting tokens whose
+// algorithm does not match the configured keys.
+func (o *Options) keyFunc(t *jwt.Token) (interface{}, error) {
+    if o.KeySet != nil {
+        key, err := o.KeySet.lookup(t)
+        if err != nil {
             return nil, err
         }
 
-        jwtData := &entity.JwtData{
-            UserID : claim["user_id"].(float64),
-        }
+        return key.publicKey, nil
+    }
 
-        return jwtData, nil
+    if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+        return nil, fmt.Errorf("signing method invalid")
     }
 
-    return nil, fmt.Errorf("Token is not valid")
+    return []byte(o.SigningKey), nil
 }
 
-func NewJwt(cfg *config.Config) Jwt {
+func (o *Options) parserOptions() []jwt.ParserOption {
+    opts := []jwt.ParserOption{
+        jwt.WithExpirationRequired(),
+        jwt.WithLeeway(o.Leeway),
+    }
+
+    if o.Issuer != "" {
+        opts = append(opts, jwt.WithIssuer(o.Issuer))
+    }
+
+    if o.Audience != "" {
+        opts = append(opts, jwt.WithAudience(o.Audience))
+    }
+
+    return opts
+}
+
+// accessTokenClaims is the wire format of an access token.
+type accessTokenClaims struct {
+    UserID userIDClaim `json:"user_id"`
+    Role   string      `json:"role"`
+    jwt.RegisteredClaims
+}
+
+// userIDClaim accepts a user ID encoded as a JSON number or as a numeric string.
+type userIDClaim int64
+
+func (u *userIDClaim) UnmarshalJSON(data []byte) error {
+    raw := strings.Trim(string(data), `"`)
+    if raw == "" || raw == "null" {
+        *u = 0
+        return nil
+    }
+
+    value, err := strconv.ParseFloat(raw, 64)
+    if err != nil || value != math.Trunc(value) {
+        return fmt.Errorf("user_id is not an integer")
+    }
+
+    *u = userIDClaim(value)
+    return nil
+}
+
+// JWKS implements Jwt.
+// It returns the public verification keys, or an empty set when tokens are signed with HS256.
+func (o *Options) JWKS() JSONWebKeySet {
+	if o.KeySet == nil {
+		return JSONWebKeySet{Keys: []JSONWebKey{}}
+	}
+
+	return o.KeySet.JWKS()
+}
+
+// NewJwt creates a Jwt from the configuration.
+// With JWT_ALGORITHM "HS256" tokens are signed with JWT_SECRET_KEY; with "RS256" or "EdDSA"
+// the keys listed in JWT_KEYS are loaded and JWT_ACTIVE_KEY_ID selects the signing key.
+func NewJwt(cfg *config.Config) (Jwt, error) {
 	apt := new(Options)
 	apt.SigningKey = cfg.App.JwtSecretKey
 	apt.Issuer = cfg.App.JwtIssuer
+	apt.AccessTokenTTL = cfg.App.JwtAccessTokenTTL
+	apt.Audience = cfg.App.JwtAudience
+	apt.Leeway = cfg.App.JwtLeeway
+
+	switch cfg.App.JwtAlgorithm {
+	case "", jwt.SigningMethodHS256.Alg():
+		return apt, nil
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg():
+		files, err := ParseKeyFiles(cfg.App.JwtKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		apt.KeySet, err = LoadKeySet(cfg.App.JwtAlgorithm, files, cfg.App.JwtActiveKeyID)
+		if err != nil {
+			return nil, err
+		}
 
-	return apt
+		return apt, nil
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.App.JwtAlgorithm)
+	}
 }
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LoginAttempt is the failed login counter of a single key, such as a client IP or an account.
+type LoginAttempt struct {
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+// LoginAttemptStore keeps failed login counters. A counter starts over when its last failure
+// is older than the window passed to RegisterFailure.
+type LoginAttemptStore interface {
+	Get(ctx context.Context, key string) (*LoginAttempt, error)
+	RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context, window time.Duration) (int64, error)
+}
+
+type memoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]*LoginAttempt
+}
+
+// Get implements LoginAttemptStore.
+// It returns nil when the key has no failures.
+func (m *memoryLoginAttemptStore) Get(ctx context.Context, key string) (*LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt, ok := m.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *attempt
+	return &copied, nil
+}
+
+// RegisterFailure implements LoginAttemptStore.
+func (m *memoryLoginAttemptStore) RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	attempt, ok := m.attempts[key]
+	if !ok || now.Sub(attempt.LastFailureAt) > window {
+		attempt = &LoginAttempt{}
+		m.attempts[key] = attempt
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = now
+
+	return attempt.Failures, nil
+}
+
+// Lock implements LoginAttemptStore.
+func (m *memoryLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt, ok := m.attempts[key]
+	if !ok {
+		attempt = &LoginAttempt{LastFailureAt: time.Now()}
+		m.attempts[key] = attempt
+	}
+
+	attempt.LockedUntil = &until
+
+	return nil
+}
+
+// Reset implements LoginAttemptStore.
+func (m *memoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, key)
+
+	return nil
+}
+
+// DeleteExpired implements LoginAttemptStore.
+// Counters are dropped once their window passed and they are no longer locked.
+func (m *memoryLoginAttemptStore) DeleteExpired(ctx context.Context, window time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	now := time.Now()
+	for key, attempt := range m.attempts {
+		if now.Sub(attempt.LastFailureAt) <= window {
+			continue
+		}
+
+		if attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil) {
+			continue
+		}
+
+		delete(m.attempts, key)
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// NewMemoryLoginAttemptStore creates a LoginAttemptStore held in process memory.
+//
+// It is intended for local development and single-instance deployments; counters
+// are lost on restart and are not shared between instances.
+func NewMemoryLoginAttemptStore() LoginAttemptStore {
+	return &memoryLoginAttemptStore{attempts: make(map[string]*LoginAttempt)}
+}
+
+// RunLoginAttemptCleanup periodically removes expired counters from the store until ctx is cancelled.
+//
+// Parameters:
+//   - ctx: Cancelling this context stops the cleanup loop.
+//   - store: The LoginAttemptStore to clean.
+//   - window: How long a counter is kept after its last failure.
+//   - interval: How often expired counters are removed.
+func RunLoginAttemptCleanup(ctx context.Context, store LoginAttemptStore, window, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := store.DeleteExpired(ctx, window)
+			if err != nil {
+				log.Error().Err(err).Msg("[AUTH] RunLoginAttemptCleanup - 1")
+				continue
+			}
+
+			if deleted > 0 {
+				log.Info().Msgf("Removed %d expired login attempt counters", deleted)
+			}
+		}
+	}
+}
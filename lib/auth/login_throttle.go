@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"portal-blog/config"
+	"time"
+)
+
+// loginFreeAttempts is how many failures a key may have before backoff starts.
+const loginFreeAttempts = 3
+
+// LoginThrottle slows down password guessing. Each key (client IP or account) gets an
+// exponentially growing delay after a few failures and is locked out once it reaches its
+// failure limit.
+type LoginThrottle interface {
+	// Check returns how long the caller has to wait before any of the keys may try again.
+	Check(ctx context.Context, keys ...string) (time.Duration, error)
+	// RegisterFailure counts a failed login for the key and returns how long it now has to wait.
+	RegisterFailure(ctx context.Context, key string, maxFailures int) (time.Duration, error)
+	Reset(ctx context.Context, key string) error
+}
+
+type loginThrottle struct {
+	store           LoginAttemptStore
+	window          time.Duration
+	backoffBase     time.Duration
+	lockoutDuration time.Duration
+}
+
+// Check implements LoginThrottle.
+func (l *loginThrottle) Check(ctx context.Context, keys ...string) (time.Duration, error) {
+	var wait time.Duration
+	now := time.Now()
+
+	for _, key := range keys {
+		attempt, err := l.store.Get(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+
+		if attempt == nil || attempt.LockedUntil == nil {
+			continue
+		}
+
+		if remaining := attempt.LockedUntil.Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+
+	return wait, nil
+}
+
+// RegisterFailure implements LoginThrottle.
+func (l *loginThrottle) RegisterFailure(ctx context.Context, key string, maxFailures int) (time.Duration, error) {
+	failures, err := l.store.RegisterFailure(ctx, key, l.window)
+	if err != nil {
+		return 0, err
+	}
+
+	wait := l.delay(failures, maxFailures)
+	if wait == 0 {
+		return 0, nil
+	}
+
+	if err := l.store.Lock(ctx, key, time.Now().Add(wait)); err != nil {
+		return 0, err
+	}
+
+	return wait, nil
+}
+
+// Reset implements LoginThrottle.
+func (l *loginThrottle) Reset(ctx context.Context, key string) error {
+	return l.store.Reset(ctx, key)
+}
+
+// delay doubles the wait for every failure past loginFreeAttempts, starting at backoffBase,
+// and locks the key for lockoutDuration once maxFailures is reached.
+func (l *loginThrottle) delay(failures, maxFailures int) time.Duration {
+	if failures >= maxFailures {
+		return l.lockoutDuration
+	}
+
+	if failures <= loginFreeAttempts {
+		return 0
+	}
+
+	wait := l.backoffBase
+	for i := loginFreeAttempts + 1; i < failures && wait < l.lockoutDuration; i++ {
+		wait *= 2
+	}
+
+	if wait > l.lockoutDuration {
+		wait = l.lockoutDuration
+	}
+
+	return wait
+}
+
+// NewLoginThrottle creates a LoginThrottle keeping its counters in store.
+func NewLoginThrottle(store LoginAttemptStore, cfg *config.Config) LoginThrottle {
+	return &loginThrottle{
+		store:           store,
+		window:          cfg.App.LoginAttemptWindow,
+		backoffBase:     cfg.App.LoginBackoffBase,
+		lockoutDuration: cfg.App.LoginLockoutDuration,
+	}
+}
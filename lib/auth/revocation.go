@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RevocationStore keeps the IDs (jti) of access tokens that were revoked before they expired.
+// Entries only need to live until the token's own expiry, after which DeleteExpired may drop them.
+type RevocationStore interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type memoryRevocationStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+// Revoke implements RevocationStore.
+func (m *memoryRevocationStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.revoked[jti] = expiresAt
+
+	return nil
+}
+
+// IsRevoked implements RevocationStore.
+func (m *memoryRevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	expiresAt, ok := m.revoked[jti]
+	if !ok {
+		return false, nil
+	}
+
+	return time.Now().Before(expiresAt), nil
+}
+
+// DeleteExpired implements RevocationStore.
+func (m *memoryRevocationStore) DeleteExpired(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	now := time.Now()
+	for jti, expiresAt := range m.revoked {
+		if !now.Before(expiresAt) {
+			delete(m.revoked, jti)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// NewMemoryRevocationStore creates a RevocationStore held in process memory.
+//
+// It is intended for local development and single-instance deployments; revocations
+// are lost on restart and are not shared between instances.
+func NewMemoryRevocationStore() RevocationStore {
+	return &memoryRevocationStore{revoked: make(map[string]time.Time)}
+}
+
+// RunRevocationCleanup periodically removes expired entries from the store until ctx is cancelled.
+//
+// Parameters:
+//   - ctx: Cancelling this context stops the cleanup loop.
+//   - store: The RevocationStore to clean.
+//   - interval: How often expired entries are removed.
+func RunRevocationCleanup(ctx context.Context, store RevocationStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := store.DeleteExpired(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("[AUTH] RunRevocationCleanup - 1")
+				continue
+			}
+
+			if deleted > 0 {
+				log.Info().Msgf("Removed %d expired revoked tokens", deleted)
+			}
+		}
+	}
+}
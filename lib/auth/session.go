@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSessionRevoked is returned for access tokens whose session was signed out or has expired.
+var ErrSessionRevoked = errors.New("session has been signed out")
+
+// SessionVerifier checks that the login session an access token was issued for is still active.
+type SessionVerifier interface {
+	// VerifySession returns the ID of the session of the token with the given jti, or
+	// ErrSessionRevoked when that session is no longer active.
+	VerifySession(ctx context.Context, jti string) (int64, error)
+}
@@ -1,6 +1,10 @@
 package conv
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"strconv"
 	"strings"
 
@@ -65,3 +69,46 @@ func StringToInt(s string) (int, error) {
 
 	return i, nil
 }
+
+// SplitList splits a comma-separated list such as "go, news,,web" into its items. Items are
+// trimmed and empty ones dropped, so an empty string gives an empty list.
+//
+// Parameters:
+//   - s: The comma-separated list.
+//
+// Returns:
+//   - []string: The non-empty items in the order they appear in s.
+func SplitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// GenerateRandomToken creates a URL-safe opaque token from cryptographically secure random bytes.
+//
+// Parameters:
+//   - size: The number of random bytes used to build the token.
+//
+// Returns:
+//   - string: The base64url encoded token without padding.
+//   - error: An error if the system random source fails, or nil if successful.
+func GenerateRandomToken(size int) (string, error) {
+	bytes := make([]byte, size)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// HashToken returns the hex encoded SHA-256 digest of an opaque token.
+// Only the digest is persisted so a database leak does not expose usable tokens.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
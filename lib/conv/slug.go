@@ -0,0 +1,80 @@
+package conv
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength is the longest slug Slugify returns.
+const MaxSlugLength = 180
+
+// letterTransliterations spells out letters that do not decompose into an ASCII letter and
+// combining marks, such as "ß" or Cyrillic.
+var letterTransliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'þ': "th", 'ł': "l", 'ı': "i",
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'ё': "e", 'є': "ye",
+	'ж': "zh", 'з': "z", 'и': "i", 'і': "i", 'ї': "yi", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh",
+	'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// stripMarks decomposes accented letters and drops the accents, so "é" becomes "e".
+var stripMarks = transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// Slugify creates a URL-friendly slug from a title. Accented letters lose their accents,
+// letters such as "ß" and Cyrillic are transliterated, and every other run of characters that
+// are not ASCII letters or digits becomes a single hyphen. Slugs longer than MaxSlugLength are
+// cut at a hyphen. The result is empty when title has nothing to transliterate.
+//
+// Parameters:
+//   - title: The original title string to be converted into a slug.
+//
+// Returns:
+//   - string: A lowercase slug made of a-z, 0-9 and single hyphens between words.
+func Slugify(title string) string {
+	var b strings.Builder
+	hyphen := false
+	write := func(part string) {
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteString(part)
+	}
+
+	// Letters are looked up before their accents are stripped, so that "й" is not taken for "и".
+	for _, r := range norm.NFC.String(strings.ToLower(title)) {
+		if transliterated, ok := letterTransliterations[r]; ok {
+			write(transliterated)
+			continue
+		}
+
+		plain, _, err := transform.String(stripMarks, string(r))
+		if err != nil {
+			plain = string(r)
+		}
+
+		for _, p := range plain {
+			if p > unicode.MaxASCII || !(unicode.IsLetter(p) || unicode.IsDigit(p)) {
+				hyphen = b.Len() > 0
+				continue
+			}
+			write(string(p))
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugLength {
+		slug = slug[:MaxSlugLength]
+		if i := strings.LastIndexByte(slug, '-'); i > 0 {
+			slug = slug[:i]
+		}
+	}
+
+	return slug
+}
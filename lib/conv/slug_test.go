@@ -0,0 +1,47 @@
+package conv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "ascii", title: "Hello, World!", want: "hello-world"},
+		{name: "accents", title: "Café crème brûlée", want: "cafe-creme-brulee"},
+		{name: "decomposed accents", title: "Café", want: "cafe"},
+		{name: "sharp s", title: "Straße", want: "strasse"},
+		{name: "russian", title: "Толстой и Йошкар-Ола", want: "tolstoy-i-yoshkar-ola"},
+		{name: "russian yo", title: "Ёлка", want: "elka"},
+		{name: "ukrainian", title: "Їжак і ґанок", want: "yizhak-i-ganok"},
+		{name: "hard sign", title: "Объявление", want: "obyavlenie"},
+		{name: "cjk", title: "日本語のタイトル", want: ""},
+		{name: "cjk with latin", title: "Go 言語 入門", want: "go"},
+		{name: "digits", title: "Top 10 tips", want: "top-10-tips"},
+		{name: "empty", title: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.title); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyMaxLength(t *testing.T) {
+	title := strings.Repeat("word ", MaxSlugLength)
+
+	got := Slugify(title)
+	if len(got) > MaxSlugLength {
+		t.Fatalf("len(Slugify) = %d, want at most %d", len(got), MaxSlugLength)
+	}
+	if strings.HasSuffix(got, "-") {
+		t.Errorf("Slugify = %q, must not end with a hyphen", got)
+	}
+}
@@ -1,8 +1,9 @@
 package middleware
 
 import (
-	"portal-blog/config"
+	"errors"
 	"portal-blog/internal/adapter/handler/response"
+	"portal-blog/internal/core/domain/entity"
 	"portal-blog/lib/auth"
 	"strings"
 
@@ -11,16 +12,25 @@ import (
 
 type Middleware interface {
 	CheckToken() fiber.Handler
+	RequireAccessToken() fiber.Handler
+	RequireRole(roles ...string) fiber.Handler
+	RequirePermission(permissions ...string) fiber.Handler
+	AuditContext() fiber.Handler
 }
 
 type Options struct {
-	authJwt auth.Jwt
+	authJwt         auth.Jwt
+	revocationStore auth.RevocationStore
+	apiKeyVerifier  auth.APIKeyVerifier
+	sessionVerifier auth.SessionVerifier
 }
 
 // CheckToken returns a Fiber middleware handler that validates JWT tokens in the request header.
 //
 // This function creates a closure that checks for the presence of a valid Authorization header,
-// extracts the JWT token, verifies it, and sets the resulting claims in the request context.
+// extracts the JWT token, verifies it, rejects tokens whose jti has been revoked or whose
+// session was signed out, and sets the resulting claims in the request context. A personal API key is accepted instead of the JWT,
+// either in the X-API-Key header or as the Bearer token.
 //
 // Parameters:
 //   - None directly, but uses the receiver (o *Options) which should have an authJwt field.
@@ -32,6 +42,10 @@ type Options struct {
 func (o *Options) CheckToken() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var errorResponse response.ErrorResponseDefault
+		if apiKey := c.Get("X-API-Key"); apiKey != "" {
+			return o.checkAPIKey(c, apiKey)
+		}
+
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
 			errorResponse.Meta.Status = false
@@ -39,7 +53,18 @@ func (o *Options) CheckToken() fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(errorResponse)
 		}
 
-		tokenString := strings.Split(authHeader, "Bearer ")[1]
+		scheme, tokenString, ok := strings.Cut(strings.TrimSpace(authHeader), " ")
+		tokenString = strings.TrimSpace(tokenString)
+		if !ok || !strings.EqualFold(scheme, "Bearer") || tokenString == "" {
+			errorResponse.Meta.Status = false
+			errorResponse.Meta.Message = "Missing or invalid Authorization header"
+			return c.Status(fiber.StatusUnauthorized).JSON(errorResponse)
+		}
+
+		if auth.IsAPIKey(tokenString) {
+			return o.checkAPIKey(c, tokenString)
+		}
+
 		claims, err := o.authJwt.VerifyAccessToken(tokenString)
 		if err != nil {
 			errorResponse.Meta.Status = false
@@ -47,27 +72,185 @@ func (o *Options) CheckToken() fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(errorResponse)
 		}
 
+		revoked, err := o.revocationStore.IsRevoked(c.Context(), claims.ID)
+		if err != nil {
+			errorResponse.Meta.Status = false
+			errorResponse.Meta.Message = "Unable to verify token"
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse)
+		}
+
+		if revoked {
+			errorResponse.Meta.Status = false
+			errorResponse.Meta.Message = "Token has been revoked"
+			return c.Status(fiber.StatusUnauthorized).JSON(errorResponse)
+		}
+
+		claims.SessionID, err = o.sessionVerifier.VerifySession(c.Context(), claims.ID)
+		if err != nil {
+			errorResponse.Meta.Status = false
+			if errors.Is(err, auth.ErrSessionRevoked) {
+				errorResponse.Meta.Message = "Session has been signed out"
+				return c.Status(fiber.StatusUnauthorized).JSON(errorResponse)
+			}
+
+			errorResponse.Meta.Message = "Unable to verify token"
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse)
+		}
+
 		c.Locals("user", claims)
 
 		return c.Next()
 	}
 }
 
+// checkAPIKey authenticates the request with a personal API key.
+func (o *Options) checkAPIKey(c *fiber.Ctx, apiKey string) error {
+	var errorResponse response.ErrorResponseDefault
+	claims, err := o.apiKeyVerifier.VerifyAPIKey(c.Context(), apiKey)
+	if err != nil {
+		errorResponse.Meta.Status = false
+		errorResponse.Meta.Message = "Invalid or expired API key"
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse)
+	}
+
+	c.Locals("user", claims)
+
+	return c.Next()
+}
+
+// RequireAccessToken returns a Fiber middleware handler that rejects requests authenticated
+// with a personal API key, for routes that manage the account itself such as passwords,
+// MFA and API keys.
+//
+// It must be registered after CheckToken, which stores the token claims in the request context.
+func (o *Options) RequireAccessToken() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var errorResponse response.ErrorResponseDefault
+		claims, ok := c.Locals("user").(*entity.JwtData)
+		if !ok {
+			errorResponse.Meta.Status = false
+			errorResponse.Meta.Message = "Unauthorized access"
+			return c.Status(fiber.StatusUnauthorized).JSON(errorResponse)
+		}
+
+		if claims.APIKeyID != 0 {
+			errorResponse.Meta.Status = false
+			errorResponse.Meta.Message = "This resource cannot be accessed with an API key"
+			return c.Status(fiber.StatusForbidden).JSON(errorResponse)
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireRole returns a Fiber middleware handler that only lets users with one of the given roles through.
+//
+// It must be registered after CheckToken, which stores the token claims in the request context.
+//
+// Parameters:
+//   - roles: The roles allowed to access the route.
+//
+// Returns:
+//   - fiber.Handler: A handler that responds with 403 Forbidden when the user's role is not listed.
+func (o *Options) RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var errorResponse response.ErrorResponseDefault
+		claims, ok := c.Locals("user").(*entity.JwtData)
+		if !ok {
+			errorResponse.Meta.Status = false
+			errorResponse.Meta.Message = "Unauthorized access"
+			return c.Status(fiber.StatusUnauthorized).JSON(errorResponse)
+		}
+
+		for _, role := range roles {
+			if claims.Role == role {
+				return c.Next()
+			}
+		}
+
+		errorResponse.Meta.Status = false
+		errorResponse.Meta.Message = "You do not have permission to access this resource"
+		return c.Status(fiber.StatusForbidden).JSON(errorResponse)
+	}
+}
+
+// RequirePermission returns a Fiber middleware handler that only lets users whose role grants
+// every one of the given permissions through. Requests made with a scoped API key also need
+// the permissions among the key's scopes.
+//
+// It must be registered after CheckToken, which stores the token claims in the request context.
+//
+// Parameters:
+//   - permissions: The permissions required to access the route.
+//
+// Returns:
+//   - fiber.Handler: A handler that responds with 403 Forbidden when a permission is missing.
+func (o *Options) RequirePermission(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var errorResponse response.ErrorResponseDefault
+		claims, ok := c.Locals("user").(*entity.JwtData)
+		if !ok {
+			errorResponse.Meta.Status = false
+			errorResponse.Meta.Message = "Unauthorized access"
+			return c.Status(fiber.StatusUnauthorized).JSON(errorResponse)
+		}
+
+		for _, permission := range permissions {
+			if !claims.Can(permission) {
+				errorResponse.Meta.Status = false
+				errorResponse.Meta.Message = "You do not have permission to access this resource"
+				return c.Status(fiber.StatusForbidden).JSON(errorResponse)
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// AuditContext returns a Fiber middleware handler that records who is making the request, for
+// the audit log written by repositories. It stores an entity.AuditActor with the user and API
+// key from the token claims, when there are any, the client IP and the request ID.
+//
+// It must be registered after CheckToken on authenticated routes, and after the requestid
+// middleware so the request ID is known.
+func (o *Options) AuditContext() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor := entity.AuditActor{
+			IP:        c.IP(),
+			RequestID: c.GetRespHeader(fiber.HeaderXRequestID),
+		}
+
+		if claims, ok := c.Locals("user").(*entity.JwtData); ok {
+			actor.UserID = int64(claims.UserID)
+			actor.APIKeyID = claims.APIKeyID
+		}
+
+		c.Locals(entity.AuditActorContextKey, actor)
+
+		return c.Next()
+	}
+}
 
 // NewMiddleware creates and initializes a new Middleware instance.
 //
-// It takes a configuration object and sets up the necessary components
-// for the middleware, including authentication.
+// It takes the token verifier shared with the auth service and sets up the
+// necessary components for the middleware.
 //
 // Parameters:
-//   - cfg: A pointer to a config.Config struct containing the application configuration.
+//   - authJwt: The Jwt used to verify access tokens.
+//   - revocationStore: The store consulted for revoked token IDs on every request.
+//   - apiKeyVerifier: Resolves personal API keys sent instead of an access token.
+//   - sessionVerifier: Checks that the session of an access token is still active.
 //
 // Returns:
 //   - Middleware: An interface that provides middleware functionality,
 //     specifically for token checking in this implementation.
-func NewMiddleware(cfg *config.Config) Middleware {
+func NewMiddleware(authJwt auth.Jwt, revocationStore auth.RevocationStore, apiKeyVerifier auth.APIKeyVerifier, sessionVerifier auth.SessionVerifier) Middleware {
 	opt := new(Options)
-	opt.authJwt = auth.NewJwt(cfg)
+	opt.authJwt = authJwt
+	opt.revocationStore = revocationStore
+	opt.apiKeyVerifier = apiKeyVerifier
+	opt.sessionVerifier = sessionVerifier
 
 	return opt
 }
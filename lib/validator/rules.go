@@ -0,0 +1,142 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// MaxTagLength is the longest tag, in characters, the tags rule accepts.
+const MaxTagLength = 50
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// registerRules adds the rules specific to this application:
+//   - slug: lowercase letters and digits in groups separated by single hyphens.
+//   - tags: a comma-separated list of non-empty tags of at most MaxTagLength characters.
+func registerRules(v *validator.Validate) {
+	_ = v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugPattern.MatchString(fl.Field().String())
+	})
+
+	_ = v.RegisterValidation("tags", func(fl validator.FieldLevel) bool {
+		for _, tag := range strings.Split(fl.Field().String(), ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// ruleMessagesEn and ruleMessagesID translate the custom rules; {0} is the field name.
+var (
+	ruleMessagesEn = map[string]string{
+		"slug": "{0} may only contain lowercase letters, numbers and single hyphens",
+		"tags": "{0} must be a comma-separated list of tags of at most {1} characters each",
+	}
+	ruleMessagesID = map[string]string{
+		"slug": "{0} hanya boleh berisi huruf kecil, angka, dan satu tanda hubung",
+		"tags": "{0} harus berupa daftar tag yang dipisahkan koma, masing-masing paling banyak {1} karakter",
+	}
+)
+
+// oneOfMessages translate the rules added by RegisterOneOf, by language; {1} lists the values.
+var oneOfMessages = map[string]string{
+	"en": "{0} must be one of {1}",
+	"id": "{0} harus salah satu dari {1}",
+}
+
+// RegisterOneOf adds a rule named rule that only accepts one of values, so enums owned by
+// other packages can be validated without this package importing them. It is meant to be
+// called once, from the init function of the package declaring the requests that use the rule.
+func RegisterOneOf(rule string, values []string) error {
+	allowed := append([]string(nil), values...)
+
+	valid := func(value string) bool {
+		for _, v := range allowed {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	return registerRule(rule, valid, oneOfMessages, strings.Join(allowed, ", "))
+}
+
+// RegisterRule adds a rule named rule that accepts the strings valid reports true for, for
+// checks whose answer can change at runtime, such as whether a role exists. messages holds
+// the message of the rule by language, one for each of Languages; {0} is the field name.
+// Like RegisterOneOf it is meant to be called once, from an init function.
+func RegisterRule(rule string, valid func(value string) bool, messages map[string]string) error {
+	return registerRule(rule, valid, messages, "")
+}
+
+// registerRule adds the rule and its translations; param is passed to the messages as {1}.
+func registerRule(rule string, valid func(value string) bool, messages map[string]string, param string) error {
+	err := validate.RegisterValidation(rule, func(fl validator.FieldLevel) bool {
+		return valid(fl.Field().String())
+	})
+	if err != nil {
+		return err
+	}
+
+	for lang, message := range messages {
+		trans, found := translator.GetTranslator(lang)
+		if !found {
+			return fmt.Errorf("no translator for language %q", lang)
+		}
+
+		message := message
+		register := func(trans ut.Translator) error {
+			return trans.Add(rule, message, true)
+		}
+		translate := func(trans ut.Translator, fe validator.FieldError) string {
+			translated, err := trans.T(rule, fe.Field(), param)
+			if err != nil {
+				return fe.Error()
+			}
+			return translated
+		}
+
+		if err := validate.RegisterTranslation(rule, trans, register, translate); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// registerRuleTranslations registers the messages of the custom rules with trans.
+func registerRuleTranslations(v *validator.Validate, trans ut.Translator, messages map[string]string) error {
+	for rule, message := range messages {
+		rule, message := rule, message
+		register := func(trans ut.Translator) error {
+			return trans.Add(rule, message, true)
+		}
+		translate := func(trans ut.Translator, fe validator.FieldError) string {
+			param := ""
+			if rule == "tags" {
+				param = strconv.Itoa(MaxTagLength)
+			}
+			translated, err := trans.T(rule, fe.Field(), param)
+			if err != nil {
+				return fe.Error()
+			}
+			return translated
+		}
+
+		if err := v.RegisterTranslation(rule, trans, register, translate); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,70 +1,125 @@
 package validator
 
 import (
-	"errors"
+	"reflect"
+	"strings"
 
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
 )
 
-var validate *validator.Validate
+// Languages lists the languages validation messages are available in. The first one is used
+// when the client accepts none of them.
+var Languages = []string{"en", "id"}
+
+var (
+	validate   *validator.Validate
+	translator *ut.UniversalTranslator
+)
 
 func init() {
 	validate = validator.New()
+	validate.RegisterTagNameFunc(jsonFieldName)
+	registerRules(validate)
+
+	translator = ut.New(en.New(), en.New(), id.New())
+
+	enTrans, _ := translator.GetTranslator("en")
+	idTrans, _ := translator.GetTranslator("id")
+	if err := enTranslations.RegisterDefaultTranslations(validate, enTrans); err != nil {
+		panic(err)
+	}
+	if err := idTranslations.RegisterDefaultTranslations(validate, idTrans); err != nil {
+		panic(err)
+	}
+	if err := registerRuleTranslations(validate, enTrans, ruleMessagesEn); err != nil {
+		panic(err)
+	}
+	if err := registerRuleTranslations(validate, idTrans, ruleMessagesID); err != nil {
+		panic(err)
+	}
+}
+
+// FieldError describes one failed rule on one field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is returned by ValidateStruct when a request breaks one or more rules.
+type ValidationErrors []FieldError
+
+// Error joins the messages of all field errors.
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, fieldError := range v {
+		messages = append(messages, fieldError.Message)
+	}
 
+	return strings.Join(messages, "; ")
 }
 
 // ValidateStruct performs validation on the provided struct using predefined validation rules.
-// It checks for various validation tags such as email format, required fields, and minimum length requirements.
+// Fields are reported by their JSON names and messages are translated to lang, which should be
+// one of Languages; unknown languages fall back to English.
 //
 // Parameters:
 //   - s: An interface{} representing the struct to be validated.
+//   - lang: The language of the messages, usually picked from the Accept-Language header.
 //
 // Returns:
-//   - An error if validation fails, containing a concatenated string of all validation error messages.
+//   - ValidationErrors if validation fails, with one entry per failed rule.
+//   - Any other error if s cannot be validated, e.g. because it is not a struct.
 //   - nil if the struct passes all validation checks.
-func ValidateStruct(s interface{}) error {
-	var errorMessage []string
+func ValidateStruct(s interface{}, lang string) error {
 	err := validate.Struct(s)
-	if err != nil {
-		for _, err := range err.(validator.ValidationErrors) {
-			switch err.Tag() {
-			case "email":
-				errorMessage = append(errorMessage, "Invalid email format")
-			case "required":
-				errorMessage = append(errorMessage, "Field "+err.Field()+" wajib diisi")
-			case "min":
-				if err.Field() == "password" {
-					errorMessage = append(errorMessage, "Password minimal 8 character")
-				}
-			case "eqfield":
-				errorMessage = append(errorMessage, err.Field()+" harus sama dengan"+err.Param()+".")
-			default:
-				errorMessage = append(errorMessage, "Field "+err.Field()+" tidak valid")
-			}
-		}
-
-		return errors.New("Validasi gagal: " + joinMessage(errorMessage))
+	if err == nil {
+		return nil
 	}
 
-	return nil
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	trans, _ := translator.GetTranslator(lang)
+
+	fieldErrors := make(ValidationErrors, 0, len(errs))
+	for _, err := range errs {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   fieldPath(err.Namespace()),
+			Rule:    err.Tag(),
+			Message: err.Translate(trans),
+		})
+	}
+
+	return fieldErrors
 }
 
-// joinMessage concatenates a slice of strings into a single string, separating each message with a comma and space.
-//
-// Parameters:
-//   - messages: A slice of strings containing the messages to be joined.
-//
-// Returns:
-//
-//	A string containing all messages from the input slice, joined together with ", " as the separator.
-func joinMessage(messages []string) string {
-	result := ""
-	for i, message := range messages {
-		if i > 0 {
-			result += ", "
-		}
-		result += message
+// jsonFieldName names struct fields after their JSON key so errors match the request body.
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+
+	return name
+}
+
+// fieldPath drops the name of the validated struct from a namespace such as
+// "ContentRequest.title", leaving the path of the field inside the request body.
+func fieldPath(namespace string) string {
+	if i := strings.IndexByte(namespace, '.'); i >= 0 {
+		return namespace[i+1:]
 	}
 
-	return result
+	return namespace
 }